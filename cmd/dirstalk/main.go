@@ -21,8 +21,13 @@ func createCommand(logger *logrus.Logger) *cobra.Command {
 	dirStalkCmd := cmd.NewRootCommand(logger)
 
 	dirStalkCmd.AddCommand(cmd.NewScanCommand(logger))
+	dirStalkCmd.AddCommand(cmd.NewMonitorCommand(logger))
+	dirStalkCmd.AddCommand(cmd.NewDNSCommand(logger))
+	dirStalkCmd.AddCommand(cmd.NewIISShortNameCommand(logger))
 	dirStalkCmd.AddCommand(cmd.NewResultViewCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewResultDiffCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewResultWatchCommand(logger.Out))
+	dirStalkCmd.AddCommand(cmd.NewResultVerifyCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewGenerateDictionaryCommand(logger.Out))
 	dirStalkCmd.AddCommand(cmd.NewVersionCommand(logger.Out))
 
@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/robots"
 )
 
 const failedToReadPropertyError = "failed to read %s"
@@ -16,99 +20,1151 @@ const failedToReadPropertyError = "failed to read %s"
 func scanConfigFromCmd(cmd *cobra.Command) (*scan.Config, error) {
 	c := &scan.Config{}
 
+	// the order matters: some of the flags are validated against the ones read before them
+	for _, configFromCmd := range []func(cmd *cobra.Command, c *scan.Config) error{
+		dictionaryConfigFromCmd,
+		statusesConfigFromCmd,
+		connectionsConfigFromCmd,
+		throttlingConfigFromCmd,
+		limitsConfigFromCmd,
+		recursionConfigFromCmd,
+		socks5ConfigFromCmd,
+		proxyChainConfigFromCmd,
+		proxyConfigFromCmd,
+		torConfigFromCmd,
+		networkConfigFromCmd,
+		headersConfigFromCmd,
+		outputConfigFromCmd,
+		targetsConfigFromCmd,
+		tlsConfigFromCmd,
+		filtersConfigFromCmd,
+		checksConfigFromCmd,
+		crawlingConfigFromCmd,
+		authConfigFromCmd,
+		awsSigV4ConfigFromCmd,
+	} {
+		if err := configFromCmd(cmd, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+func dictionaryConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
 	var err error
 
 	c.DictionaryPath = cmd.Flag(flagScanDictionary).Value.String()
 
 	if c.DictionaryTimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanDictionaryGetTimeout); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanDictionaryGetTimeout)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDictionaryGetTimeout)
+	}
+
+	if c.Extensions, err = cmd.Flags().GetStringSlice(flagScanExtensions); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanExtensions)
+	}
+
+	if c.RandomizeOrder, err = cmd.Flags().GetBool(flagScanRandomizeOrder); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRandomizeOrder)
+	}
+
+	if c.Seed, err = cmd.Flags().GetInt64(flagScanSeed); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanSeed)
+	}
+
+	if cmd.Flags().Changed(flagScanSeed) && !c.RandomizeOrder {
+		return errors.Errorf("%s requires %s", flagScanSeed, flagScanRandomizeOrder)
+	}
+
+	if c.RandomizeOrder && !cmd.Flags().Changed(flagScanSeed) {
+		c.Seed = time.Now().UnixNano()
+	}
+
+	if c.TrailingSlash, err = cmd.Flags().GetBool(flagScanTrailingSlash); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTrailingSlash)
 	}
 
 	if c.HTTPMethods, err = cmd.Flags().GetStringSlice(flagScanHTTPMethods); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPMethods)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPMethods)
+	}
+
+	c.BodyTemplate = cmd.Flag(flagScanBodyTemplate).Value.String()
+	c.ContentType = cmd.Flag(flagScanContentType).Value.String()
+
+	if c.ContentType != "" && c.BodyTemplate == "" {
+		return errors.Errorf("%s requires %s", flagScanContentType, flagScanBodyTemplate)
 	}
 
+	return nil
+}
+
+func statusesConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	if c.HTTPStatusesToIgnore, err = cmd.Flags().GetIntSlice(flagScanHTTPStatusesToIgnore); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPStatusesToIgnore)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPStatusesToIgnore)
+	}
+
+	if c.IncludeStatuses, err = scan.ParseRanges(cmd.Flag(flagScanIncludeStatus).Value.String()); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanIncludeStatus)
+	}
+
+	if c.ExcludeStatuses, err = scan.ParseRanges(cmd.Flag(flagScanExcludeStatus).Value.String()); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanExcludeStatus)
+	}
+
+	// the statuses to include replace the default ones to ignore, eg: to report the 404 responses
+	if len(c.IncludeStatuses) > 0 && !cmd.Flags().Changed(flagScanHTTPStatusesToIgnore) {
+		c.HTTPStatusesToIgnore = nil
 	}
 
+	return nil
+}
+
+func connectionsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	if c.Threads, err = cmd.Flags().GetInt(flagScanThreads); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanThreads)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanThreads)
+	}
+
+	if c.ThreadsPerHost, err = cmd.Flags().GetInt(flagScanThreadsPerHost); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanThreadsPerHost)
+	}
+
+	if c.ThreadsPerHost < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanThreadsPerHost)
 	}
 
 	if c.TimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanHTTPTimeout); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPTimeout)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPTimeout)
+	}
+
+	granularTimeouts := []struct {
+		flag    string
+		timeout *int
+	}{
+		{flag: flagScanConnectTimeout, timeout: &c.ConnectTimeoutInMilliseconds},
+		{flag: flagScanTLSTimeout, timeout: &c.TLSTimeoutInMilliseconds},
+		{flag: flagScanResponseHeaderTimeout, timeout: &c.ResponseHeaderTimeoutInMilliseconds},
+	}
+
+	for _, granularTimeout := range granularTimeouts {
+		if *granularTimeout.timeout, err = cmd.Flags().GetInt(granularTimeout.flag); err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, granularTimeout.flag)
+		}
+
+		if *granularTimeout.timeout < 0 {
+			return errors.Errorf("%s cannot be negative", granularTimeout.flag)
+		}
+	}
+
+	if c.MaxIdleConns, err = cmd.Flags().GetInt(flagScanMaxIdleConns); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxIdleConns)
+	}
+
+	if c.MaxIdleConns < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanMaxIdleConns)
+	}
+
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = c.Threads
+	}
+
+	if c.MaxConnsPerHost, err = cmd.Flags().GetInt(flagScanMaxConnsPerHost); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxConnsPerHost)
+	}
+
+	if c.MaxConnsPerHost < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanMaxConnsPerHost)
+	}
+
+	if c.DisableKeepAlive, err = cmd.Flags().GetBool(flagScanDisableKeepAlive); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDisableKeepAlive)
+	}
+
+	c.HTTPVersion = cmd.Flag(flagScanHTTPVersion).Value.String()
+
+	return nil
+}
+
+func throttlingConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.Retries, err = cmd.Flags().GetInt(flagScanRetries); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRetries)
+	}
+
+	if c.Retries < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanRetries)
+	}
+
+	if c.RetryBackoff, err = cmd.Flags().GetDuration(flagScanRetryBackoff); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRetryBackoff)
+	}
+
+	if c.RetryBackoff < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanRetryBackoff)
+	}
+
+	if c.RateLimit, err = cmd.Flags().GetFloat64(flagScanRateLimit); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRateLimit)
+	}
+
+	if c.RateLimit < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanRateLimit)
+	}
+
+	if c.MaxBandwidth, err = parseBandwidth(cmd.Flag(flagScanMaxBandwidth).Value.String()); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanMaxBandwidth)
+	}
+
+	if c.Delay, err = cmd.Flags().GetDuration(flagScanDelay); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDelay)
+	}
+
+	if c.DelayJitter, err = cmd.Flags().GetDuration(flagScanDelayJitter); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDelayJitter)
+	}
+
+	if c.Delay < 0 || c.DelayJitter < 0 {
+		return errors.Errorf("%s and %s cannot be negative", flagScanDelay, flagScanDelayJitter)
+	}
+
+	if c.AdaptiveRate, err = cmd.Flags().GetBool(flagScanAdaptiveRate); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanAdaptiveRate)
+	}
+
+	if c.CircuitBreaker, err = cmd.Flags().GetInt(flagScanCircuitBreaker); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCircuitBreaker)
+	}
+
+	if c.CircuitBreakerPause, err = cmd.Flags().GetDuration(flagScanCircuitBreakerPause); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCircuitBreakerPause)
+	}
+
+	if c.CircuitBreaker < 0 || c.CircuitBreakerPause < 0 {
+		return errors.Errorf("%s and %s cannot be negative", flagScanCircuitBreaker, flagScanCircuitBreakerPause)
+	}
+
+	if c.CircuitBreakerPause > 0 && c.CircuitBreaker == 0 {
+		return errors.Errorf("%s requires %s", flagScanCircuitBreakerPause, flagScanCircuitBreaker)
+	}
+
+	return nil
+}
+
+func limitsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.MaxErrors, err = cmd.Flags().GetInt(flagScanMaxErrors); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxErrors)
+	}
+
+	if c.MaxErrors < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanMaxErrors)
+	}
+
+	if c.MaxScanDuration, err = cmd.Flags().GetDuration(flagScanMaxScanDuration); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxScanDuration)
+	}
+
+	if c.MaxScanDuration < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanMaxScanDuration)
+	}
+
+	if c.MaxRequests, err = cmd.Flags().GetUint64(flagScanMaxRequests); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxRequests)
+	}
+
+	if c.MaxBodySize, err = cmd.Flags().GetInt64(flagScanMaxBodySize); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxBodySize)
+	}
+
+	if c.MaxBodySize <= 0 {
+		return errors.Errorf("%s must be greater than 0", flagScanMaxBodySize)
+	}
+
+	if c.DisableCompression, err = cmd.Flags().GetBool(flagScanDisableCompression); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDisableCompression)
 	}
 
 	if c.CacheRequests, err = cmd.Flags().GetBool(flagScanHTTPCacheRequests); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPCacheRequests)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHTTPCacheRequests)
 	}
 
+	return nil
+}
+
+func recursionConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	if c.ScanDepth, err = cmd.Flags().GetInt(flagScanScanDepth); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanScanDepth)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanScanDepth)
+	}
+
+	if c.RecurseOn, err = scan.ParseRanges(cmd.Flag(flagScanRecurseOn).Value.String()); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanRecurseOn)
 	}
 
+	c.Strategy = cmd.Flag(flagScanStrategy).Value.String()
+
+	switch c.Strategy {
+	case scan.StrategyDepthFirst, scan.StrategyBreadthFirst, scan.StrategyPriority:
+	default:
+		return errors.Errorf(
+			"unsupported %s `%s`, valid values are %v",
+			flagScanStrategy,
+			c.Strategy,
+			scan.Strategies(),
+		)
+	}
+
+	if c.BloomFilterCapacity, err = cmd.Flags().GetUint(flagScanBloomFilterCapacity); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanBloomFilterCapacity)
+	}
+
+	if c.BloomFilterErrorRate, err = cmd.Flags().GetFloat64(flagScanBloomFilterErrorRate); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanBloomFilterErrorRate)
+	}
+
+	if c.BloomFilterErrorRate <= 0 || c.BloomFilterErrorRate >= 1 {
+		return errors.Errorf("%s must be a probability between 0 and 1, eg: 0.001", flagScanBloomFilterErrorRate)
+	}
+
+	rawRecursionExclusions, err := cmd.Flags().GetStringArray(flagScanRecursionExclude)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRecursionExclude)
+	}
+
+	for _, rawPattern := range rawRecursionExclusions {
+		pattern, err := scan.ParsePathPattern(rawPattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanRecursionExclude)
+		}
+
+		c.RecursionExclusions = append(c.RecursionExclusions, pattern)
+	}
+
+	rawDepthOverrides, err := cmd.Flags().GetStringArray(flagScanRecursionDepth)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRecursionDepth)
+	}
+
+	for _, rawOverride := range rawDepthOverrides {
+		override, err := scan.ParseDepthOverride(rawOverride)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanRecursionDepth)
+		}
+
+		c.DepthOverrides = append(c.DepthOverrides, override)
+	}
+
+	return nil
+}
+
+func socks5ConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	socks5Host := cmd.Flag(flagScanSocks5Host).Value.String()
 	if len(socks5Host) > 0 {
 		if c.Socks5Url, err = url.Parse("socks5://" + socks5Host); err != nil {
-			return nil, errors.Wrapf(err, "invalid value for %s", flagScanSocks5Host)
+			return errors.Wrapf(err, "invalid value for %s", flagScanSocks5Host)
+		}
+	}
+
+	socks5User := cmd.Flag(flagScanSocks5User).Value.String()
+	socks5Password := cmd.Flag(flagScanSocks5Password).Value.String()
+
+	if len(socks5User) > 0 || len(socks5Password) > 0 {
+		if c.Socks5Url == nil {
+			return errors.Errorf("%s and %s require %s", flagScanSocks5User, flagScanSocks5Password, flagScanSocks5Host)
+		}
+
+		if c.Socks5Url.User != nil {
+			return errors.Errorf(
+				"the credentials cannot be both embedded in %s and specified with %s and %s",
+				flagScanSocks5Host,
+				flagScanSocks5User,
+				flagScanSocks5Password,
+			)
+		}
+
+		c.Socks5Url.User = url.UserPassword(socks5User, socks5Password)
+	}
+
+	return nil
+}
+
+func proxyChainConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	rawProxyChain, err := cmd.Flags().GetStringSlice(flagScanProxyChain)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanProxyChain)
+	}
+
+	if c.ProxyChain, err = rawProxiesToURLs(rawProxyChain); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanProxyChain)
+	}
+
+	rawProxy := cmd.Flag(flagScanProxy).Value.String()
+	if len(rawProxy) > 0 {
+		if len(c.ProxyChain) > 0 {
+			return errors.Errorf("%s and %s cannot be used together", flagScanProxy, flagScanProxyChain)
 		}
+
+		if c.ProxyChain, err = rawProxiesToURLs([]string{rawProxy}); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanProxy)
+		}
+	}
+
+	return nil
+}
+
+func proxyConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if rawHTTPProxy := cmd.Flag(flagScanHTTPProxy).Value.String(); len(rawHTTPProxy) > 0 {
+		if c.Socks5Url != nil || len(c.ProxyChain) > 0 {
+			return errors.Errorf(
+				"%s cannot be used together with %s, %s or %s",
+				flagScanHTTPProxy,
+				flagScanSocks5Host,
+				flagScanProxy,
+				flagScanProxyChain,
+			)
+		}
+
+		if c.HTTPProxy, err = url.Parse(rawHTTPProxy); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanHTTPProxy)
+		}
+	}
+
+	c.ProxyListPath = cmd.Flag(flagScanProxyList).Value.String()
+	c.ProxyRotation = cmd.Flag(flagScanProxyRotation).Value.String()
+
+	if len(c.ProxyListPath) > 0 && (c.Socks5Url != nil || len(c.ProxyChain) > 0 || c.HTTPProxy != nil) {
+		return errors.Errorf(
+			"%s cannot be used together with %s, %s, %s or %s",
+			flagScanProxyList,
+			flagScanSocks5Host,
+			flagScanProxy,
+			flagScanProxyChain,
+			flagScanHTTPProxy,
+		)
 	}
 
+	c.ProxyPAC = cmd.Flag(flagScanProxyPAC).Value.String()
+
+	otherProxies := c.Socks5Url != nil || len(c.ProxyChain) > 0 || c.HTTPProxy != nil || c.ProxyListPath != ""
+	if len(c.ProxyPAC) > 0 && otherProxies {
+		return errors.Errorf(
+			"%s cannot be used together with %s, %s, %s, %s or %s",
+			flagScanProxyPAC,
+			flagScanSocks5Host,
+			flagScanProxy,
+			flagScanProxyChain,
+			flagScanHTTPProxy,
+			flagScanProxyList,
+		)
+	}
+
+	if c.Socks5Url != nil && len(c.ProxyChain) > 0 {
+		return errors.Errorf(
+			"%s cannot be used together with %s or %s",
+			flagScanSocks5Host,
+			flagScanProxy,
+			flagScanProxyChain,
+		)
+	}
+
+	c.ProxyAuth = cmd.Flag(flagScanProxyAuth).Value.String()
+
+	return nil
+}
+
+func networkConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.SSHTunnel = cmd.Flag(flagScanSSHTunnel).Value.String()
+	c.SSHKeyPath = cmd.Flag(flagScanSSHKey).Value.String()
+	c.SSHKnownHostsPath = cmd.Flag(flagScanSSHKnownHosts).Value.String()
+
+	if err := localAddrFromCmd(cmd, c); err != nil {
+		return err
+	}
+
+	if c.IPVersion, err = ipVersionFromCmd(cmd); err != nil {
+		return err
+	}
+
+	rawResolve, err := cmd.Flags().GetStringArray(flagScanResolve)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanResolve)
+	}
+
+	if c.Resolve, err = rawResolveToResolve(rawResolve); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanResolve)
+	}
+
+	if c.DNSCacheTTL, err = cmd.Flags().GetDuration(flagScanDNSCacheTTL); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanDNSCacheTTL)
+	}
+
+	if c.DNSCacheTTL < 0 {
+		return errors.Errorf("%s cannot be negative", flagScanDNSCacheTTL)
+	}
+
+	if rawDoHResolver := cmd.Flag(flagScanDoHResolver).Value.String(); len(rawDoHResolver) > 0 {
+		if c.DoHResolver, err = url.Parse(rawDoHResolver); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanDoHResolver)
+		}
+	}
+
+	return nil
+}
+
+func headersConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	c.UserAgent = cmd.Flag(flagScanUserAgent).Value.String()
 
+	if c.RandomUserAgent, err = cmd.Flags().GetBool(flagScanRandomUserAgent); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanRandomUserAgent)
+	}
+
+	// a list of user agents is only useful to pick them at random
+	c.UserAgentFilePath = cmd.Flag(flagScanUserAgentFile).Value.String()
+	c.RandomUserAgent = c.RandomUserAgent || c.UserAgentFilePath != ""
+
+	if c.RandomUserAgent && c.UserAgent != "" {
+		return errors.Errorf(
+			"%s cannot be used together with %s or %s",
+			flagScanUserAgent,
+			flagScanRandomUserAgent,
+			flagScanUserAgentFile,
+		)
+	}
+
 	if c.UseCookieJar, err = cmd.Flags().GetBool(flagScanCookieJar); err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanCookieJar)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCookieJar)
 	}
 
 	rawCookies, err := cmd.Flags().GetStringArray(flagScanCookie)
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanCookie)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCookie)
 	}
 
 	if c.Cookies, err = rawCookiesToCookies(rawCookies); err != nil {
-		return nil, errors.Wrap(err, "failed to convert rawCookies to objects")
+		return errors.Wrap(err, "failed to convert rawCookies to objects")
 	}
 
 	rawHeaders, err := cmd.Flags().GetStringArray(flagScanHeader)
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanHeader)
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanHeader)
 	}
 
 	if c.Headers, err = rawHeadersToHeaders(rawHeaders); err != nil {
-		return nil, errors.Wrapf(err, "failed to convert rawHeaders (%v)", rawHeaders)
+		return errors.Wrapf(err, "failed to convert rawHeaders (%v)", rawHeaders)
 	}
 
+	c.HeaderPoolPath = cmd.Flag(flagScanHeaderPool).Value.String()
+
+	c.HostHeader = cmd.Flag(flagScanHostHeader).Value.String()
+	if strings.ContainsAny(c.HostHeader, "/ \t") {
+		return errors.Errorf(
+			"%s must be a host optionally followed by a port, eg: admin.example.com",
+			flagScanHostHeader,
+		)
+	}
+
+	return nil
+}
+
+func outputConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	c.Out = cmd.Flag(flagScanResultOutput).Value.String()
+	c.OutputDir = cmd.Flag(flagScanResultOutputDir).Value.String()
+
+	if c.Out != "" && c.OutputDir != "" {
+		return errors.Errorf("%s and %s cannot be used together", flagScanResultOutput, flagScanResultOutputDir)
+	}
+
+	c.StatePath = cmd.Flag(flagScanStateFile).Value.String()
+	c.ResumePath = cmd.Flag(flagScanResume).Value.String()
+
+	// the progress of a resumed scan is saved back where it was read from, unless asked otherwise
+	if c.StatePath == "" {
+		c.StatePath = c.ResumePath
+	}
+
+	c.SignResultsKeyPath = cmd.Flag(flagScanSignResults).Value.String()
+
+	if c.SignResultsKeyPath != "" && c.Out == "" && c.OutputDir == "" {
+		return errors.Errorf("%s requires %s or %s", flagScanSignResults, flagScanResultOutput, flagScanResultOutputDir)
+	}
+
+	noInteractive, err := cmd.Flags().GetBool(flagScanNoInteractive)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanNoInteractive)
+	}
+
+	c.Interactive = !noInteractive
+
+	c.ScanName = cmd.Flag(flagScanName).Value.String()
+
+	rawTags, err := cmd.Flags().GetStringArray(flagScanTag)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTag)
+	}
+
+	if c.Tags, err = rawTagsToTags(rawTags); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanTag)
+	}
+
+	return nil
+}
+
+func targetsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.TargetsPath = cmd.Flag(flagScanTargets).Value.String()
+
+	if c.ParallelTargets, err = cmd.Flags().GetInt(flagScanParallelTargets); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanParallelTargets)
+	}
+
+	if c.ParallelTargets < 1 {
+		return errors.Errorf("%s must be at least 1", flagScanParallelTargets)
+	}
+
+	if c.ProbePorts, err = cmd.Flags().GetIntSlice(flagScanProbePorts); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanProbePorts)
+	}
+
+	for _, port := range c.ProbePorts {
+		if port < 1 || port > 65535 {
+			return errors.Errorf("%s must be between 1 and 65535, got %d", flagScanProbePorts, port)
+		}
+	}
+
+	if c.ProbeTimeoutInMilliseconds, err = cmd.Flags().GetInt(flagScanProbeTimeout); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanProbeTimeout)
+	}
+
+	return nil
+}
+
+func tlsConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
 
 	c.ShouldSkipSSLCertificatesValidation, err = cmd.Flags().GetBool(flagShouldSkipSSLCertificatesValidation)
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagShouldSkipSSLCertificatesValidation)
+		return errors.Wrapf(err, failedToReadPropertyError, flagShouldSkipSSLCertificatesValidation)
+	}
+
+	c.SkipSSLCertificatesValidationHosts, err = cmd.Flags().GetStringSlice(flagSkipSSLCertificatesValidationHosts)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagSkipSSLCertificatesValidationHosts)
+	}
+
+	c.CACertPath = cmd.Flag(flagScanCACert).Value.String()
+
+	c.TLSP12Path = cmd.Flag(flagScanTLSP12).Value.String()
+	c.TLSP12Password = cmd.Flag(flagScanTLSP12Password).Value.String()
+
+	c.TLSCertPath = cmd.Flag(flagScanTLSCert).Value.String()
+	c.TLSKeyPath = cmd.Flag(flagScanTLSKey).Value.String()
+	c.TLSKeyPassword = cmd.Flag(flagScanTLSKeyPassword).Value.String()
+
+	if (c.TLSCertPath == "") != (c.TLSKeyPath == "") {
+		return errors.Errorf("%s and %s must be used together", flagScanTLSCert, flagScanTLSKey)
 	}
 
+	if c.TLSCertPath != "" && c.TLSP12Path != "" {
+		return errors.Errorf("%s and %s cannot be used together", flagScanTLSCert, flagScanTLSP12)
+	}
+
+	c.TLSFingerprint = cmd.Flag(flagScanTLSFingerprint).Value.String()
+	c.TLSJA3 = cmd.Flag(flagScanTLSJA3).Value.String()
+
+	if c.TLSFingerprint == client.TLSFingerprintCustom && c.TLSJA3 == "" {
+		return errors.Errorf("%s %s requires %s", flagScanTLSFingerprint, client.TLSFingerprintCustom, flagScanTLSJA3)
+	}
+
+	if c.TLSJA3 != "" && c.TLSFingerprint != client.TLSFingerprintCustom {
+		return errors.Errorf("%s requires %s %s", flagScanTLSJA3, flagScanTLSFingerprint, client.TLSFingerprintCustom)
+	}
+
+	c.TLSMinVersion = cmd.Flag(flagScanTLSMinVersion).Value.String()
+	c.TLSMaxVersion = cmd.Flag(flagScanTLSMaxVersion).Value.String()
+	c.SNI = cmd.Flag(flagScanSNI).Value.String()
+
+	if c.TLSCipherSuites, err = cmd.Flags().GetStringSlice(flagScanTLSCiphers); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTLSCiphers)
+	}
+
+	return nil
+}
+
+func filtersConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
 	c.IgnoreEmpty20xResponses, err = cmd.Flags().GetBool(flagIgnore20xWithEmptyBody)
 	if err != nil {
-		return nil, errors.Wrapf(err, failedToReadPropertyError, flagIgnore20xWithEmptyBody)
+		return errors.Wrapf(err, failedToReadPropertyError, flagIgnore20xWithEmptyBody)
 	}
 
-	return c, nil
+	if c.AutoCalibrate, err = cmd.Flags().GetBool(flagScanAutoCalibrate); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanAutoCalibrate)
+	}
+
+	// the virtual hosts are told apart by answering differently than the unknown hosts the calibration requests
+	if strings.Contains(c.HostHeader, scan.FuzzKeyword) {
+		c.AutoCalibrate = true
+	}
+
+	if c.CalibrationSimilarity, err = cmd.Flags().GetInt(flagScanCalibrationSimilar); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanCalibrationSimilar)
+	}
+
+	if c.CalibrationSimilarity < 0 || c.CalibrationSimilarity > 100 {
+		return errors.Errorf("%s must be a percentage between 1 and 100", flagScanCalibrationSimilar)
+	}
+
+	for flagName, ranges := range map[string]*[]scan.Range{
+		flagScanFilterSize:  &c.FilterSize,
+		flagScanFilterWords: &c.FilterWords,
+		flagScanFilterLines: &c.FilterLines,
+		flagScanMatchSize:   &c.MatchSize,
+		flagScanMatchWords:  &c.MatchWords,
+		flagScanMatchLines:  &c.MatchLines,
+	} {
+		if *ranges, err = scan.ParseRanges(cmd.Flag(flagName).Value.String()); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagName)
+		}
+	}
+
+	if c.MatchRegexes, err = cmd.Flags().GetStringArray(flagScanMatchRegex); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMatchRegex)
+	}
+
+	if c.FilterRegexes, err = cmd.Flags().GetStringArray(flagScanFilterRegex); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanFilterRegex)
+	}
+
+	return nil
+}
+
+func checksConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.ExtractRegexes, err = cmd.Flags().GetStringArray(flagScanExtractRegex); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanExtractRegex)
+	}
+
+	if c.ScanSecrets, err = cmd.Flags().GetBool(flagScanSecrets); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanSecrets)
+	}
+
+	if c.SecretPatterns, err = cmd.Flags().GetStringArray(flagScanSecretPattern); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanSecretPattern)
+	}
+
+	// custom patterns are only useful when scanning for secrets
+	c.ScanSecrets = c.ScanSecrets || len(c.SecretPatterns) > 0
+
+	if c.BackupVariants, err = cmd.Flags().GetBool(flagScanBackups); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanBackups)
+	}
+
+	if c.LeakChecks, err = cmd.Flags().GetBool(flagScanLeakChecks); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanLeakChecks)
+	}
+
+	if c.Fingerprint, err = cmd.Flags().GetBool(flagScanFingerprint); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanFingerprint)
+	}
+
+	if c.ProbeMethods, err = probeMethodsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if c.MethodOverride, err = cmd.Flags().GetBool(flagScanOverride); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanOverride)
+	}
+
+	return nil
+}
+
+func crawlingConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	if c.FollowRedirects, err = cmd.Flags().GetBool(flagScanFollow); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanFollow)
+	}
+
+	if c.MaxRedirects, err = cmd.Flags().GetInt(flagScanMaxRedirects); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanMaxRedirects)
+	}
+
+	if cmd.Flags().Changed(flagScanMaxRedirects) && !c.FollowRedirects {
+		return errors.Errorf("%s requires %s", flagScanMaxRedirects, flagScanFollow)
+	}
+
+	if c.MaxRedirects < 1 {
+		return errors.Errorf("%s must be at least 1", flagScanMaxRedirects)
+	}
+
+	c.Robots = cmd.Flag(flagScanRobots).Value.String()
+
+	switch c.Robots {
+	case robots.ModeSeed, robots.ModeRespect, robots.ModeIgnore:
+	default:
+		return errors.Errorf("unsupported %s `%s`, valid values are %v", flagScanRobots, c.Robots, robots.Modes())
+	}
+
+	c.RulesPath = cmd.Flag(flagScanRules).Value.String()
+	c.ClassifyPath = cmd.Flag(flagScanClassify).Value.String()
+
+	c.ScopePath = cmd.Flag(flagScanScope).Value.String()
+
+	return nil
+}
+
+func authConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	var err error
+
+	c.OAuth2TokenURL = cmd.Flag(flagScanOAuth2TokenURL).Value.String()
+	c.OAuth2ClientID = cmd.Flag(flagScanOAuth2ClientID).Value.String()
+	c.OAuth2ClientSecret = cmd.Flag(flagScanOAuth2ClientSecret).Value.String()
+
+	if c.OAuth2Scopes, err = cmd.Flags().GetStringSlice(flagScanOAuth2Scopes); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanOAuth2Scopes)
+	}
+
+	if len(c.OAuth2TokenURL) > 0 && len(c.OAuth2ClientID) == 0 {
+		return errors.Errorf("%s is required when %s is specified", flagScanOAuth2ClientID, flagScanOAuth2TokenURL)
+	}
+
+	if rawDigest := cmd.Flag(flagScanAuthDigest).Value.String(); len(rawDigest) > 0 {
+		username, password, found := strings.Cut(rawDigest, ":")
+		if !found || username == "" {
+			return errors.Errorf("%s must be in the format user:password", flagScanAuthDigest)
+		}
+
+		if len(c.OAuth2TokenURL) > 0 {
+			return errors.Errorf("%s and %s cannot be used together", flagScanAuthDigest, flagScanOAuth2TokenURL)
+		}
+
+		c.DigestAuthUsername, c.DigestAuthPassword = username, password
+	}
+
+	if rawNTLM := cmd.Flag(flagScanAuthNTLM).Value.String(); len(rawNTLM) > 0 {
+		username, password, found := strings.Cut(rawNTLM, ":")
+		if !found || username == "" {
+			return errors.Errorf("%s must be in the format user:password", flagScanAuthNTLM)
+		}
+
+		if len(c.OAuth2TokenURL) > 0 || len(c.DigestAuthUsername) > 0 {
+			return errors.Errorf(
+				"%s cannot be used together with %s or %s",
+				flagScanAuthNTLM,
+				flagScanOAuth2TokenURL,
+				flagScanAuthDigest,
+			)
+		}
+
+		c.NTLMAuthUsername, c.NTLMAuthPassword = username, password
+	}
+
+	c.AuthRefreshCommand = cmd.Flag(flagScanAuthRefreshCmd).Value.String()
+	c.LoginPath = cmd.Flag(flagScanLogin).Value.String()
+
+	otherAuth := len(c.OAuth2TokenURL) > 0 || len(c.DigestAuthUsername) > 0 || len(c.NTLMAuthUsername) > 0
+	if len(c.AuthRefreshCommand) > 0 && otherAuth {
+		return errors.Errorf(
+			"%s cannot be used together with %s, %s or %s",
+			flagScanAuthRefreshCmd,
+			flagScanOAuth2TokenURL,
+			flagScanAuthDigest,
+			flagScanAuthNTLM,
+		)
+	}
+
+	return nil
+}
+
+func awsSigV4ConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	if rawSigV4 := cmd.Flag(flagScanAWSSigV4).Value.String(); len(rawSigV4) > 0 {
+		parts := strings.Split(rawSigV4, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("%s must be in the format region/service: %s", flagScanAWSSigV4, rawSigV4)
+		}
+
+		c.AWSSigV4Region, c.AWSSigV4Service = parts[0], parts[1]
+	}
+
+	return nil
+}
+
+func localAddrFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	rawLocalAddr := cmd.Flag(flagScanLocalAddr).Value.String()
+	iface := cmd.Flag(flagScanInterface).Value.String()
+
+	if len(rawLocalAddr) > 0 && len(iface) > 0 {
+		return errors.Errorf("%s and %s cannot be used together", flagScanLocalAddr, flagScanInterface)
+	}
+
+	if len(rawLocalAddr) > 0 {
+		if c.LocalAddr = net.ParseIP(rawLocalAddr); c.LocalAddr == nil {
+			return errors.Errorf("%s must be an IP address, got `%s`", flagScanLocalAddr, rawLocalAddr)
+		}
+	}
+
+	if len(iface) > 0 {
+		localAddr, err := client.InterfaceAddr(iface)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for %s", flagScanInterface)
+		}
+
+		c.LocalAddr = localAddr
+	}
+
+	if c.LocalAddr != nil && c.SSHTunnel != "" {
+		return errors.Errorf(
+			"%s and %s cannot be used together with %s",
+			flagScanLocalAddr,
+			flagScanInterface,
+			flagScanSSHTunnel,
+		)
+	}
+
+	return nil
+}
+
+func ipVersionFromCmd(cmd *cobra.Command) (int, error) {
+	ipv4, err := cmd.Flags().GetBool(flagScanIPv4)
+	if err != nil {
+		return 0, errors.Wrapf(err, failedToReadPropertyError, flagScanIPv4)
+	}
+
+	ipv6, err := cmd.Flags().GetBool(flagScanIPv6)
+	if err != nil {
+		return 0, errors.Wrapf(err, failedToReadPropertyError, flagScanIPv6)
+	}
+
+	switch {
+	case ipv4 && ipv6:
+		return 0, errors.Errorf("%s and %s cannot be used together", flagScanIPv4, flagScanIPv6)
+	case ipv4:
+		return 4, nil
+	case ipv6:
+		return 6, nil
+	}
+
+	return 0, nil
+}
+
+// probeMethodsFromCmd returns the methods to probe on the paths found, uppercased and without duplicates.
+func probeMethodsFromCmd(cmd *cobra.Command) ([]string, error) {
+	rawMethods, err := cmd.Flags().GetStringSlice(flagScanProbeMethods)
+	if err != nil {
+		return nil, errors.Wrapf(err, failedToReadPropertyError, flagScanProbeMethods)
+	}
+
+	methods := make([]string, 0, len(rawMethods))
+	seen := make(map[string]struct{}, len(rawMethods))
+
+	for _, raw := range rawMethods {
+		method := strings.ToUpper(strings.TrimSpace(raw))
+
+		if !isProbeMethod(method) {
+			return nil, errors.Errorf(
+				"invalid value for %s: %s, it must be one of %s",
+				flagScanProbeMethods,
+				raw,
+				strings.Join(scan.ProbeMethods(), ","),
+			)
+		}
+
+		if _, ok := seen[method]; !ok {
+			seen[method] = struct{}{}
+			methods = append(methods, method)
+		}
+	}
+
+	return methods, nil
+}
+
+func isProbeMethod(method string) bool {
+	for _, m := range scan.ProbeMethods() {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func torConfigFromCmd(cmd *cobra.Command, c *scan.Config) error {
+	tor, err := cmd.Flags().GetBool(flagScanTor)
+	if err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTor)
+	}
+
+	c.TorControlAddr = cmd.Flag(flagScanTorControl).Value.String()
+	c.TorControlPassword = cmd.Flag(flagScanTorControlPassword).Value.String()
+
+	if c.TorRenewEvery, err = cmd.Flags().GetInt(flagScanTorRenewEvery); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTorRenewEvery)
+	}
+
+	if c.TorRenewOnTooManyRequests, err = cmd.Flags().GetBool(flagScanTorRenewOn429); err != nil {
+		return errors.Wrapf(err, failedToReadPropertyError, flagScanTorRenewOn429)
+	}
+
+	if !tor {
+		if c.TorControlAddr != "" {
+			return errors.Errorf("%s requires %s", flagScanTorControl, flagScanTor)
+		}
+
+		return nil
+	}
+
+	if c.Socks5Url != nil || len(c.ProxyChain) > 0 || c.HTTPProxy != nil || c.ProxyListPath != "" || c.ProxyPAC != "" {
+		return errors.Errorf("%s cannot be used together with other proxies", flagScanTor)
+	}
+
+	if (c.TorRenewEvery > 0 || c.TorRenewOnTooManyRequests) && c.TorControlAddr == "" {
+		return errors.Errorf(
+			"%s is required to renew the circuit with %s or %s",
+			flagScanTorControl,
+			flagScanTorRenewEvery,
+			flagScanTorRenewOn429,
+		)
+	}
+
+	if c.Socks5Url, err = url.Parse("socks5://" + cmd.Flag(flagScanTorSocks).Value.String()); err != nil {
+		return errors.Wrapf(err, "invalid value for %s", flagScanTorSocks)
+	}
+
+	return nil
+}
+
+// bandwidthUnits are the multipliers of the units accepted by parseBandwidth.
+var bandwidthUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"K":  1 << 10,
+	"KB": 1 << 10,
+	"M":  1 << 20,
+	"MB": 1 << 20,
+	"G":  1 << 30,
+	"GB": 1 << 30,
+}
+
+// parseBandwidth parses an amount of bytes per second, eg: 512KB/s or 2MB, 0 when empty.
+func parseBandwidth(raw string) (int64, error) {
+	value := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(raw)), "/S")
+	if value == "" {
+		return 0, nil
+	}
+
+	number := strings.TrimRightFunc(value, func(r rune) bool { return r >= 'A' && r <= 'Z' })
+
+	multiplier, found := bandwidthUnits[strings.TrimSpace(value[len(number):])]
+	if !found {
+		return 0, errors.Errorf("unknown unit in `%s`, valid units are B, KB, MB and GB", raw)
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || amount*float64(multiplier) < 1 {
+		return 0, errors.Errorf("`%s` must be a positive amount of bytes per second, eg: 2MB/s", raw)
+	}
+
+	return int64(amount * float64(multiplier)), nil
+}
+
+func rawProxiesToURLs(rawProxies []string) ([]*url.URL, error) {
+	proxies := make([]*url.URL, 0, len(rawProxies))
+
+	for _, rawProxy := range rawProxies {
+		u, err := url.Parse(rawProxy)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.Scheme == "" || u.Host == "" {
+			return nil, errors.Errorf("proxy must be in the format scheme://host:port: %s", rawProxy)
+		}
+
+		proxies = append(proxies, u)
+	}
+
+	return proxies, nil
 }
 
 func rawHeadersToHeaders(rawHeaders []string) (map[string]string, error) {
 	headers := make(map[string]string, len(rawHeaders)*2)
 
 	for _, rawHeader := range rawHeaders {
-		parts := strings.Split(rawHeader, ":")
-		if len(parts) != 2 {
+		// the value can contain colons, eg: a URL or the {{randstr:N}} placeholder
+		name, value, found := strings.Cut(rawHeader, ":")
+		if !found || name == "" {
 			return nil, errors.Errorf("header is in invalid format: %s", rawHeader)
 		}
 
-		headers[parts[0]] = parts[1]
+		headers[name] = value
 	}
 
 	return headers, nil
 }
 
+// rawResolveToResolve parses entries in the host:port:ip format used by curl, the ip can be an IPv6 in brackets.
+func rawResolveToResolve(rawResolve []string) (map[string]string, error) {
+	if len(rawResolve) == 0 {
+		return nil, nil
+	}
+
+	resolve := make(map[string]string, len(rawResolve))
+
+	for _, rawEntry := range rawResolve {
+		parts := strings.SplitN(rawEntry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("entry must be in the format host:port:ip: %s", rawEntry)
+		}
+
+		ip := strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+		if net.ParseIP(ip) == nil {
+			return nil, errors.Errorf("entry must be in the format host:port:ip: %s", rawEntry)
+		}
+
+		resolve[net.JoinHostPort(parts[0], parts[1])] = ip
+	}
+
+	return resolve, nil
+}
+
+func rawTagsToTags(rawTags []string) (map[string]string, error) {
+	if len(rawTags) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(rawTags))
+
+	for _, rawTag := range rawTags {
+		parts := strings.SplitN(rawTag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("tag must be in the format key=value: %s", rawTag)
+		}
+
+		tags[parts[0]] = parts[1]
+	}
+
+	return tags, nil
+}
+
 func rawCookiesToCookies(rawCookies []string) ([]*http.Cookie, error) {
 	cookies := make([]*http.Cookie, 0, len(rawCookies))
 
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common"
+	"github.com/stefanoj3/dirstalk/pkg/dictionary"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+)
+
+func NewDNSCommand(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns [domain] [-- scan flags]",
+		Short: "Brute-force the subdomains of the given domain",
+		Long: "Brute-force the subdomains of the given domain, ignoring the ones resolving only to the addresses of " +
+			"a wildcard record. The flags of the scan command given after -- scan the HTTP responders found on the " +
+			"subdomains; eg: dirstalk dns example.com --dictionary subdomains.txt -- --dictionary paths.txt",
+		RunE: buildDNSFunction(logger),
+	}
+
+	cmd.Flags().StringP(
+		flagDNSDictionary,
+		flagDNSDictionaryShort,
+		"",
+		"dictionary of the subdomains to look for (path to local file or remote url)",
+	)
+	common.Must(cmd.MarkFlagFilename(flagDNSDictionary))
+	common.Must(cmd.MarkFlagRequired(flagDNSDictionary))
+
+	cmd.Flags().Int(
+		flagDNSDictionaryGetTimeout,
+		50000,
+		"timeout in milliseconds (used when fetching remote dictionary)",
+	)
+
+	cmd.Flags().IntP(
+		flagDNSThreads,
+		flagDNSThreadsShort,
+		10,
+		"amount of lookups to perform at the same time",
+	)
+
+	cmd.Flags().String(
+		flagDNSResolver,
+		"",
+		"DNS server to query rather than the one of the system; eg: 8.8.8.8:53",
+	)
+
+	cmd.Flags().Int(
+		flagDNSTimeout,
+		2000,
+		"timeout in milliseconds of every lookup",
+	)
+
+	return cmd
+}
+
+func buildDNSFunction(logger *logrus.Logger) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		scanArgs := []string(nil)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, scanArgs = args[:dash], args[dash:]
+		}
+
+		if len(args) == 0 {
+			return errors.New("no domain provided")
+		}
+
+		domain := args[0]
+		if strings.ContainsAny(domain, "/:") {
+			return errors.Errorf("invalid domain %s, it must be a host name; eg: example.com", domain)
+		}
+
+		threads, err := cmd.Flags().GetInt(flagDNSThreads)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagDNSThreads)
+		}
+
+		if threads < 1 {
+			return errors.Errorf("%s must be at least 1", flagDNSThreads)
+		}
+
+		timeout, err := cmd.Flags().GetInt(flagDNSTimeout)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagDNSTimeout)
+		}
+
+		dictionaryTimeout, err := cmd.Flags().GetInt(flagDNSDictionaryGetTimeout)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagDNSDictionaryGetTimeout)
+		}
+
+		// the scan flags are validated upfront, not to find out they are wrong only after the brute-force
+		var scanCmd *cobra.Command
+
+		if scanArgs != nil {
+			if scanCmd, err = scanCommandFrom(logger, scanArgs, "subdomains"); err != nil {
+				return err
+			}
+		}
+
+		dict, err := dictionary.NewDictionaryFrom(
+			cmd.Flag(flagDNSDictionary).Value.String(),
+			&http.Client{Timeout: time.Millisecond * time.Duration(dictionaryTimeout)},
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to build dictionary")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		finder := discovery.NewSubdomainFinder(
+			discovery.NewResolver(cmd.Flag(flagDNSResolver).Value.String(), time.Millisecond*time.Duration(timeout)),
+			threads,
+			logger,
+		)
+
+		subdomains := finder.Find(ctx, domain, dict)
+
+		logger.Infof("Found %d subdomains", len(subdomains))
+
+		if scanCmd == nil || len(subdomains) == 0 || ctx.Err() != nil {
+			return nil
+		}
+
+		stop()
+
+		return scanSubdomains(logger, scanCmd, subdomains)
+	}
+}
+
+// scanCommandFrom parses the given flags of the scan command, which scans the given targets found.
+func scanCommandFrom(logger *logrus.Logger, args []string, targets string) (*cobra.Command, error) {
+	scanCmd := NewScanCommand(logger)
+
+	if err := scanCmd.ParseFlags(args); err != nil {
+		return nil, errors.Wrap(err, "invalid scan flags")
+	}
+
+	if len(scanCmd.Flags().Args()) > 0 {
+		return nil, errors.Errorf("no URL must be provided to the scan of the %s, got %s", targets, scanCmd.Flags().Args())
+	}
+
+	if scanCmd.Flag(flagScanDictionary).Value.String() == "" {
+		return nil, errors.Errorf("the scan of the %s requires --%s", targets, flagScanDictionary)
+	}
+
+	return scanCmd, nil
+}
+
+// scanSubdomains scans the HTTP responders found on the given subdomains.
+func scanSubdomains(logger *logrus.Logger, scanCmd *cobra.Command, subdomains []discovery.Subdomain) error {
+	cnf, err := scanConfigFromCmd(scanCmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to build config")
+	}
+
+	if err := checkMultiTargetConfig(cnf); err != nil {
+		return err
+	}
+
+	if cnf.Resolve == nil {
+		cnf.Resolve = make(map[string]string)
+	}
+
+	hosts := make([]string, 0, len(subdomains))
+
+	for _, s := range subdomains {
+		hosts = append(hosts, s.Host)
+
+		// the subdomains are reached at the addresses found, which the resolver of the system could ignore,
+		// unless the user pinned them already
+		for _, port := range cnf.ProbePorts {
+			key := net.JoinHostPort(s.Host, strconv.Itoa(port))
+			if _, ok := cnf.Resolve[key]; !ok {
+				cnf.Resolve[key] = s.Addresses[0]
+			}
+		}
+	}
+
+	targets, err := probeHosts(logger, cnf, hosts)
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		return errors.New("no HTTP responders found, nothing to scan")
+	}
+
+	return scanTargets(logger, cnf, targets)
+}
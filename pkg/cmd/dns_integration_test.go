@@ -0,0 +1,129 @@
+package cmd_test
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSShouldFindTheSubdomains(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	dnsServer := test.NewDNSServer(t, map[string][]string{
+		"home.example.com": {"10.0.0.1"},
+		"*.example.com":    {"10.0.0.9"},
+	})
+	defer dnsServer.Close()
+
+	err := executeCommand(
+		c,
+		"dns",
+		"example.com",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--resolver",
+		dnsServer.Addr(),
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "Wildcard DNS detected")
+	assert.Contains(t, loggerBuffer.String(), "host=home.example.com")
+	assert.NotContains(t, loggerBuffer.String(), "msg=Found addresses=10.0.0.9 host=blabla.example.com")
+	assert.Contains(t, loggerBuffer.String(), "Found 1 subdomains")
+}
+
+func TestDNSShouldScanTheHTTPRespondersOfTheSubdomains(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	_, port, err := net.SplitHostPort(testServer.Listener.Addr().String())
+	assert.NoError(t, err)
+
+	// the subdomain exists only for the resolver given, so the scan must reach the address it found
+	dnsServer := test.NewDNSServer(t, map[string][]string{"home.example.com": {"127.0.0.1"}})
+	defer dnsServer.Close()
+
+	err = executeCommand(
+		c,
+		"dns",
+		"example.com",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--resolver",
+		dnsServer.Addr(),
+		"--",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--probe-ports",
+		port,
+	)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "Found 1 HTTP responders")
+
+	// the probe and the dictionary
+	assert.Equal(t, 4, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "home.example.com:"+port, r.Host)
+	})
+}
+
+func TestDNSShouldErrForInvalidUsages(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--dictionary", "testdata/dict.txt"},
+			expectedError: "no domain provided",
+		},
+		{
+			args:          []string{"https://example.com/", "--dictionary", "testdata/dict.txt"},
+			expectedError: "invalid domain",
+		},
+		{
+			args:          []string{"example.com", "--dictionary", "testdata/dict.txt", "--threads", "0"},
+			expectedError: "threads must be at least 1",
+		},
+		{
+			args:          []string{"example.com", "--dictionary", "testdata/dict.txt", "--", "--threads", "2"},
+			expectedError: "the scan of the subdomains requires --dictionary",
+		},
+		{
+			args:          []string{"example.com", "--dictionary", "testdata/dict.txt", "--", "http://example.com/"},
+			expectedError: "no URL must be provided to the scan of the subdomains",
+		},
+		{
+			args:          []string{"example.com", "--dictionary", "testdata/dict.txt", "--", "--unknown"},
+			expectedError: "invalid scan flags",
+		},
+	}
+
+	for _, tc := range testCases {
+		logger, _ := test.NewLogger()
+
+		c := createCommand(logger)
+		assert.NotNil(t, c)
+
+		err := executeCommand(c, append([]string{"dns"}, tc.args...)...)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	}
+}
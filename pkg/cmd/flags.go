@@ -4,27 +4,184 @@ const (
 	// Root flags.
 	flagRootVerbose      = "verbose"
 	flagRootVerboseShort = "v"
+	flagRootLogFile      = "log-file"
 
 	// Scan flags.
 	flagScanDictionary                      = "dictionary"
 	flagScanDictionaryShort                 = "d"
 	flagScanDictionaryGetTimeout            = "dictionary-get-timeout"
+	flagScanExtensions                      = "extensions"
+	flagScanExtensionsShort                 = "x"
+	flagScanTrailingSlash                   = "trailing-slash"
+	flagScanRandomizeOrder                  = "randomize-order"
+	flagScanSeed                            = "seed"
 	flagScanHTTPMethods                     = "http-methods"
+	flagScanBodyTemplate                    = "body-template"
+	flagScanContentType                     = "content-type"
 	flagScanHTTPStatusesToIgnore            = "http-statuses-to-ignore"
+	flagScanIncludeStatus                   = "include-status"
+	flagScanExcludeStatus                   = "exclude-status"
 	flagScanHTTPTimeout                     = "http-timeout"
+	flagScanConnectTimeout                  = "connect-timeout"
+	flagScanTLSTimeout                      = "tls-timeout"
+	flagScanResponseHeaderTimeout           = "response-header-timeout"
+	flagScanMaxIdleConns                    = "max-idle-conns"
+	flagScanMaxConnsPerHost                 = "max-conns-per-host"
+	flagScanDisableKeepAlive                = "disable-keepalive"
+	flagScanRetries                         = "retries"
+	flagScanRetryBackoff                    = "retry-backoff"
+	flagScanRateLimit                       = "rate-limit"
+	flagScanMaxBandwidth                    = "max-bandwidth"
+	flagScanDelay                           = "delay"
+	flagScanDelayJitter                     = "delay-jitter"
+	flagScanAdaptiveRate                    = "adaptive-rate"
+	flagScanCircuitBreaker                  = "circuit-breaker"
+	flagScanCircuitBreakerPause             = "circuit-breaker-pause"
+	flagScanMaxErrors                       = "max-errors"
+	flagScanMaxScanDuration                 = "max-scan-duration"
+	flagScanMaxRequests                     = "max-requests"
+	flagScanMaxBodySize                     = "max-body-size"
+	flagScanDisableCompression              = "disable-compression"
 	flagScanHTTPCacheRequests               = "http-cache-requests"
 	flagScanScanDepth                       = "scan-depth"
+	flagScanRecurseOn                       = "recurse-on"
+	flagScanRecursionExclude                = "recursion-exclude"
+	flagScanRecursionDepth                  = "recursion-depth"
+	flagScanStrategy                        = "strategy"
+	flagScanBloomFilterCapacity             = "bloom-filter-capacity"
+	flagScanBloomFilterErrorRate            = "bloom-filter-error-rate"
 	flagScanThreads                         = "threads"
 	flagScanThreadsShort                    = "t"
+	flagScanThreadsPerHost                  = "threads-per-host"
 	flagScanSocks5Host                      = "socks5"
+	flagScanSocks5User                      = "socks5-user"
+	flagScanSocks5Password                  = "socks5-pass"
+	flagScanProxyChain                      = "proxy-chain"
+	flagScanProxy                           = "proxy"
+	flagScanHTTPProxy                       = "http-proxy"
+	flagScanProxyList                       = "proxy-list"
+	flagScanProxyPAC                        = "proxy-pac"
+	flagScanProxyRotation                   = "proxy-rotation"
+	flagScanProxyAuth                       = "proxy-auth"
+	flagScanTor                             = "tor"
+	flagScanTorSocks                        = "tor-socks"
+	flagScanTorControl                      = "tor-control"
+	flagScanTorControlPassword              = "tor-control-pass"
+	flagScanTorRenewEvery                   = "tor-renew-every"
+	flagScanTorRenewOn429                   = "tor-renew-on-429"
+	flagScanSSHTunnel                       = "ssh-tunnel"
+	flagScanSSHKey                          = "ssh-key"
+	flagScanSSHKnownHosts                   = "ssh-known-hosts"
+	flagScanLocalAddr                       = "local-addr"
+	flagScanInterface                       = "interface"
+	flagScanIPv4                            = "ipv4"
+	flagScanIPv4Short                       = "4"
+	flagScanIPv6                            = "ipv6"
+	flagScanIPv6Short                       = "6"
+	flagScanResolve                         = "resolve"
+	flagScanDoHResolver                     = "doh-resolver"
+	flagScanDNSCacheTTL                     = "dns-cache-ttl"
 	flagScanUserAgent                       = "user-agent"
+	flagScanRandomUserAgent                 = "random-user-agent"
+	flagScanUserAgentFile                   = "user-agent-file"
 	flagScanCookieJar                       = "use-cookie-jar"
 	flagScanCookie                          = "cookie"
 	flagScanHeader                          = "header"
+	flagScanHeaderPool                      = "header-pool"
+	flagScanHostHeader                      = "host-header"
 	flagScanResultOutput                    = "out"
+	flagScanResultOutputDir                 = "output-dir"
+	flagScanSignResults                     = "sign-results"
+	flagScanStateFile                       = "state-file"
+	flagScanResume                          = "resume"
+	flagScanTargets                         = "targets"
+	flagScanParallelTargets                 = "parallel-targets"
+	flagScanProbePorts                      = "probe-ports"
+	flagScanProbeTimeout                    = "probe-timeout"
 	flagShouldSkipSSLCertificatesValidation = "no-check-certificate"
+	flagSkipSSLCertificatesValidationHosts  = "no-check-certificate-host"
+	flagScanCACert                          = "ca-cert"
 
 	flagIgnore20xWithEmptyBody = "ignore-empty-body"
+	flagScanAutoCalibrate      = "auto-calibrate"
+	flagScanCalibrationSimilar = "calibration-similarity"
+	flagScanFilterSize         = "filter-size"
+	flagScanFilterWords        = "filter-words"
+	flagScanFilterLines        = "filter-lines"
+	flagScanMatchSize          = "match-size"
+	flagScanMatchWords         = "match-words"
+	flagScanMatchLines         = "match-lines"
+	flagScanMatchRegex         = "match-regex"
+	flagScanFilterRegex        = "filter-regex"
+
+	flagScanNoInteractive = "no-interactive"
+
+	flagScanName = "scan-name"
+	flagScanTag  = "tag"
+
+	flagScanExtractRegex  = "extract-regex"
+	flagScanSecrets       = "scan-secrets"
+	flagScanSecretPattern = "secret-pattern"
+	flagScanBackups       = "backup-variants"
+	flagScanLeakChecks    = "leak-checks"
+	flagScanFingerprint   = "fingerprint"
+	flagScanProbeMethods  = "probe-methods"
+	flagScanOverride      = "method-override"
+	flagScanFollow        = "follow-redirects"
+	flagScanMaxRedirects  = "max-redirects"
+	flagScanRobots        = "robots"
+
+	flagScanRules    = "rules"
+	flagScanClassify = "classify"
+
+	flagScanScope = "scope"
+
+	flagScanOAuth2TokenURL     = "oauth2-token-url"
+	flagScanOAuth2ClientID     = "oauth2-client-id"
+	flagScanOAuth2ClientSecret = "oauth2-client-secret"
+	flagScanOAuth2Scopes       = "oauth2-scopes"
+
+	flagScanAuthDigest = "auth-digest"
+	flagScanAuthNTLM   = "auth-ntlm"
+
+	flagScanAuthRefreshCmd = "auth-refresh-cmd"
+
+	flagScanLogin = "login"
+
+	flagScanAWSSigV4 = "aws-sigv4"
+
+	flagScanTLSP12         = "tls-p12"
+	flagScanTLSP12Password = "tls-p12-pass"
+	flagScanTLSCert        = "tls-cert"
+	flagScanTLSKey         = "tls-key"
+	flagScanTLSKeyPassword = "tls-key-pass"
+
+	flagScanTLSFingerprint = "tls-fingerprint"
+	flagScanTLSJA3         = "tls-ja3"
+	flagScanSNI            = "sni"
+	flagScanTLSMinVersion  = "tls-min-version"
+	flagScanTLSMaxVersion  = "tls-max-version"
+	flagScanTLSCiphers     = "tls-ciphers"
+	flagScanHTTPVersion    = "http-version"
+
+	// Monitor flags.
+	flagMonitorConfig = "config"
+	flagMonitorRuns   = "runs"
+
+	// DNS flags.
+	flagDNSDictionary           = "dictionary"
+	flagDNSDictionaryShort      = "d"
+	flagDNSDictionaryGetTimeout = "dictionary-get-timeout"
+	flagDNSThreads              = "threads"
+	flagDNSThreadsShort         = "t"
+	flagDNSResolver             = "resolver"
+	flagDNSTimeout              = "dns-timeout"
+
+	// IIS short name flags.
+	flagShortNameThreads            = "threads"
+	flagShortNameThreadsShort       = "t"
+	flagShortNameHTTPTimeout        = "http-timeout"
+	flagShortNameNoCheckCertificate = "no-check-certificate"
 
 	// Generate dictionary flags.
 	flagDictionaryGenerateOutput           = "out"
@@ -34,6 +191,20 @@ const (
 	// Result view flags.
 	flagResultViewResultFile      = "result-file"
 	flagResultViewResultFileShort = "r"
+	flagResultViewOnlyFound       = "only-found"
+	flagResultViewNoColor         = "no-color"
+	flagResultViewColor           = "color"
+
+	// Result watch flags.
+	flagResultWatchInterval    = "interval"
+	flagResultWatchChecks      = "checks"
+	flagResultWatchHTTPTimeout = "http-timeout"
+
+	// Result verify flags.
+	flagResultVerifyResultFile      = "result-file"
+	flagResultVerifyResultFileShort = "r"
+	flagResultVerifyKey             = "key"
+	flagResultVerifySignature       = "signature"
 
 	// Result diff flags.
 	flagResultDiffFirstFile       = "first"
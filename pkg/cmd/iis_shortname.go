@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+)
+
+func NewIISShortNameCommand(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iis-shortname [url] [-- scan flags]",
+		Short: "Enumerate the 8.3 short names disclosed by an IIS target",
+		Long: "Enumerate the 8.3 short names (eg: ADMINI~1.ASP) of the files and directories of the given URL via " +
+			"the tilde vulnerability of IIS. The flags of the scan command given after -- scan the URL, adding to " +
+			"the dictionary the short names and the long names guessed out of them; " +
+			"eg: dirstalk iis-shortname https://example.com/ -- --dictionary paths.txt",
+		RunE: buildIISShortNameFunction(logger),
+	}
+
+	cmd.Flags().IntP(
+		flagShortNameThreads,
+		flagShortNameThreadsShort,
+		10,
+		"amount of requests to perform at the same time",
+	)
+
+	cmd.Flags().Int(
+		flagShortNameHTTPTimeout,
+		5000,
+		"timeout in milliseconds",
+	)
+
+	cmd.Flags().Bool(
+		flagShortNameNoCheckCertificate,
+		false,
+		"skip the verification of the certificate of the target",
+	)
+
+	return cmd
+}
+
+func buildIISShortNameFunction(logger *logrus.Logger) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		scanArgs := []string(nil)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, scanArgs = args[:dash], args[dash:]
+		}
+
+		u, err := getURL(args)
+		if err != nil {
+			return err
+		}
+
+		threads, err := cmd.Flags().GetInt(flagShortNameThreads)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagShortNameThreads)
+		}
+
+		if threads < 1 {
+			return errors.Errorf("%s must be at least 1", flagShortNameThreads)
+		}
+
+		timeout, err := cmd.Flags().GetInt(flagShortNameHTTPTimeout)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagShortNameHTTPTimeout)
+		}
+
+		noCheckCertificate, err := cmd.Flags().GetBool(flagShortNameNoCheckCertificate)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagShortNameNoCheckCertificate)
+		}
+
+		// the scan flags are validated upfront, not to find out they are wrong only after the enumeration
+		var scanCmd *cobra.Command
+
+		if scanArgs != nil {
+			if scanCmd, err = scanCommandFrom(logger, scanArgs, "short names"); err != nil {
+				return err
+			}
+		}
+
+		c, err := client.NewClientFromConfig(timeout, nil, "", false, nil, nil, false, noCheckCertificate, u)
+		if err != nil {
+			return errors.Wrap(err, "failed to build client")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		shortNames, err := discovery.NewShortNameScanner(c, threads, logger).Scan(ctx, u)
+		if err != nil && ctx.Err() == nil {
+			return errors.Wrap(err, "failed to enumerate the short names")
+		}
+
+		logger.Infof("Found %d short names", len(shortNames))
+
+		if scanCmd == nil || len(shortNames) == 0 || ctx.Err() != nil {
+			return nil
+		}
+
+		stop()
+
+		return scanWithShortNames(logger, scanCmd, u, shortNames)
+	}
+}
+
+// scanWithShortNames scans the URL, adding to the dictionary the hints given by the short names found.
+func scanWithShortNames(
+	logger *logrus.Logger,
+	scanCmd *cobra.Command,
+	u *url.URL,
+	shortNames []discovery.ShortName,
+) error {
+	cnf, err := scanConfigFromCmd(scanCmd)
+	if err != nil {
+		return errors.Wrap(err, "failed to build config")
+	}
+
+	dict, err := buildDictionary(cnf, u)
+	if err != nil {
+		return err
+	}
+
+	cnf.DictionaryHints = discovery.ShortNameHints(shortNames, dict)
+
+	logger.WithField("hints", len(cnf.DictionaryHints)).Info("Adding the short names hints to the dictionary")
+
+	return startScan(logger, cnf, u)
+}
@@ -0,0 +1,87 @@
+package cmd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIISShortNameShouldFindTheShortNames(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewIISShortNameServerWithAssertion(
+		[]string{"BLABLA~1.PHP"},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+	)
+	defer testServer.Close()
+
+	err := executeCommand(c, "iis-shortname", testServer.URL)
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), `short-name="BLABLA~1.PHP"`)
+	assert.Contains(t, loggerBuffer.String(), "Found 1 short names")
+}
+
+func TestIISShortNameShouldScanWithTheHints(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, serverAssertion := test.NewIISShortNameServerWithAssertion(
+		[]string{"BLABLA~1.PHP"},
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/blabla.php" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		},
+	)
+	defer testServer.Close()
+
+	err := executeCommand(
+		c,
+		"iis-shortname",
+		testServer.URL,
+		"--",
+		"--dictionary",
+		"testdata/dict.txt",
+		"--scan-depth",
+		"0",
+	)
+	assert.NoError(t, err)
+
+	requestedPaths := make(map[string]struct{})
+	serverAssertion.Range(func(_ int, r http.Request) {
+		requestedPaths[r.URL.Path] = struct{}{}
+	})
+
+	assert.Contains(t, requestedPaths, "/blabla~1.php")
+	assert.Contains(t, requestedPaths, "/blabla.php")
+	assert.Contains(t, loggerBuffer.String(), "hints=2")
+}
+
+func TestIISShortNameShouldFailWhenTheShortNamesAreNotDisclosed(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	testServer, _ := test.NewServerWithAssertion(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+
+	err := executeCommand(c, "iis-shortname", testServer.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the target does not disclose its short names")
+	}
+}
@@ -0,0 +1,167 @@
+package interactive
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/common/logging"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+const help = `Available commands (type the command followed by enter):
+  +[n]  increase the number of threads by n (default 1)
+  -[n]  decrease the number of threads by n (default 1)
+  v     toggle verbose logging
+  s     skip the recursion branches currently being scanned
+  p     pause the scan, or resume it when paused
+  i     print the scan stats
+  h     print this help`
+
+// Scanner is the scanner being controlled.
+type Scanner interface {
+	Workers() int
+	SetWorkers(workers int)
+	SkipActiveBranches() []string
+	Pause() bool
+	Resume() bool
+	Stats() scan.Stats
+}
+
+func NewController(scanner Scanner, logger *logrus.Logger) *Controller {
+	return &Controller{
+		scanner:      scanner,
+		logger:       logger,
+		verboseLevel: logrus.DebugLevel,
+		quietLevel:   logrus.InfoLevel,
+	}
+}
+
+// Controller allows to change the behaviour of a running scan by reading commands from an input.
+type Controller struct {
+	scanner      Scanner
+	logger       *logrus.Logger
+	verboseLevel logrus.Level
+	quietLevel   logrus.Level
+}
+
+// Listen reads commands from the given input until the context is canceled or the input is exhausted.
+func (c *Controller) Listen(ctx context.Context, in io.Reader) {
+	lines := bufio.NewScanner(in)
+
+	for lines.Scan() {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			c.Handle(lines.Text())
+		}
+	}
+}
+
+// Handle executes a single command.
+func (c *Controller) Handle(command string) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return
+	}
+
+	switch command[0] {
+	case '+', '-':
+		c.changeWorkers(command)
+	case 'v':
+		c.toggleVerbosity()
+	case 's':
+		c.skip()
+	case 'p':
+		c.togglePause()
+	case 'i':
+		c.printStats()
+	case 'h', '?':
+		_, _ = fmt.Fprintln(c.logger.Out, help)
+	default:
+		c.logger.WithField("command", command).Warn("Unknown command, type h for the list of commands")
+	}
+}
+
+func (c *Controller) changeWorkers(command string) {
+	delta := 1
+
+	if len(command) > 1 {
+		n, err := strconv.Atoi(command[1:])
+		if err != nil || n <= 0 {
+			c.logger.WithField("command", command).Warn("Invalid number of threads")
+
+			return
+		}
+
+		delta = n
+	}
+
+	if command[0] == '-' {
+		delta = -delta
+	}
+
+	c.scanner.SetWorkers(c.scanner.Workers() + delta)
+
+	c.logger.WithField("threads", c.scanner.Workers()).Info("Changed number of threads")
+}
+
+func (c *Controller) toggleVerbosity() {
+	if level := logging.ConsoleLevel(c.logger); level == c.verboseLevel {
+		logging.SetConsoleLevel(c.logger, c.quietLevel)
+	} else {
+		c.quietLevel = level
+		logging.SetConsoleLevel(c.logger, c.verboseLevel)
+	}
+
+	c.logger.WithField("level", logging.ConsoleLevel(c.logger).String()).Info("Changed log level")
+}
+
+func (c *Controller) skip() {
+	skipped := c.scanner.SkipActiveBranches()
+	if len(skipped) == 0 {
+		c.logger.Info("No recursion branch to skip")
+
+		return
+	}
+
+	c.logger.WithField("branches", strings.Join(skipped, ", ")).Info("Skipping recursion branches")
+}
+
+func (c *Controller) togglePause() {
+	if c.scanner.Resume() {
+		c.logger.Info("Scan resumed")
+
+		return
+	}
+
+	c.scanner.Pause()
+	c.logger.Info("Scan paused, the requests in flight are completed; type p followed by enter to resume it")
+}
+
+func (c *Controller) printStats() {
+	stats := c.scanner.Stats()
+
+	requestsPerSecond := 0.0
+	if seconds := stats.Elapsed.Seconds(); seconds > 0 {
+		requestsPerSecond = float64(stats.Requests) / seconds
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"requests":        stats.Requests,
+		"errors":          stats.Errors,
+		"results":         stats.Results,
+		"threads":         stats.Workers,
+		"active-threads":  stats.ActiveWorkers,
+		"active-branches": strings.Join(stats.ActiveBranches, ", "),
+		"paused":          stats.Paused,
+		"elapsed":         stats.Elapsed.Round(time.Second).String(),
+		"requests-per-s":  fmt.Sprintf("%.2f", requestsPerSecond),
+	}).Info("Scan stats")
+}
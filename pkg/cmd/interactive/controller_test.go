@@ -0,0 +1,144 @@
+package interactive_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/cmd/interactive"
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeScanner struct {
+	workers  int
+	branches []string
+	paused   bool
+}
+
+func (f *fakeScanner) Workers() int {
+	return f.workers
+}
+
+func (f *fakeScanner) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	f.workers = workers
+}
+
+func (f *fakeScanner) SkipActiveBranches() []string {
+	return f.branches
+}
+
+func (f *fakeScanner) Pause() bool {
+	paused := f.paused
+	f.paused = true
+
+	return !paused
+}
+
+func (f *fakeScanner) Resume() bool {
+	paused := f.paused
+	f.paused = false
+
+	return paused
+}
+
+func (f *fakeScanner) Stats() scan.Stats {
+	return scan.Stats{
+		Requests:       100,
+		Errors:         2,
+		Results:        7,
+		Workers:        f.workers,
+		ActiveWorkers:  3,
+		ActiveBranches: f.branches,
+		Elapsed:        10 * time.Second,
+	}
+}
+
+func TestControllerShouldChangeTheNumberOfWorkers(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	scanner := &fakeScanner{workers: 5}
+	sut := interactive.NewController(scanner, logger)
+
+	sut.Handle("+")
+	assert.Equal(t, 6, scanner.workers)
+
+	sut.Handle("+4")
+	assert.Equal(t, 10, scanner.workers)
+
+	sut.Handle("-3")
+	assert.Equal(t, 7, scanner.workers)
+
+	sut.Handle("-")
+	assert.Equal(t, 6, scanner.workers)
+
+	sut.Handle("+abc")
+	assert.Equal(t, 6, scanner.workers)
+	assert.Contains(t, loggerBuffer.String(), "Invalid number of threads")
+
+	assert.Contains(t, loggerBuffer.String(), "threads=6")
+}
+
+func TestControllerShouldToggleVerbosity(t *testing.T) {
+	logger, _ := test.NewLogger()
+	logger.SetLevel(logrus.WarnLevel)
+
+	sut := interactive.NewController(&fakeScanner{}, logger)
+
+	sut.Handle("v")
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+
+	sut.Handle("v")
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+}
+
+func TestControllerShouldSkipBranchesAndPrintStats(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	sut := interactive.NewController(&fakeScanner{workers: 4, branches: []string{"/admin", "/home/old"}}, logger)
+
+	sut.Handle("s")
+	assert.Contains(t, loggerBuffer.String(), "Skipping recursion branches")
+	assert.Contains(t, loggerBuffer.String(), "/admin, /home/old")
+
+	sut.Handle("i")
+	assert.Contains(t, loggerBuffer.String(), "Scan stats")
+	assert.Contains(t, loggerBuffer.String(), "requests=100")
+	assert.Contains(t, loggerBuffer.String(), "requests-per-s=10.00")
+	assert.Contains(t, loggerBuffer.String(), "threads=4")
+}
+
+func TestControllerShouldListenForCommands(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	scanner := &fakeScanner{workers: 1}
+	sut := interactive.NewController(scanner, logger)
+
+	sut.Listen(context.Background(), strings.NewReader("h\n\n+2\nwhat\n"))
+
+	assert.Equal(t, 3, scanner.workers)
+	assert.Contains(t, loggerBuffer.String(), "Available commands")
+	assert.Contains(t, loggerBuffer.String(), "Unknown command")
+}
+
+func TestControllerShouldPauseAndResumeTheScan(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	scanner := &fakeScanner{workers: 4}
+	sut := interactive.NewController(scanner, logger)
+
+	sut.Handle("p")
+	assert.True(t, scanner.paused)
+	assert.Contains(t, loggerBuffer.String(), "Scan paused")
+
+	sut.Handle("p")
+	assert.False(t, scanner.paused)
+	assert.Contains(t, loggerBuffer.String(), "Scan resumed")
+}
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common"
+	"github.com/stefanoj3/dirstalk/pkg/monitor"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+)
+
+func NewMonitorCommand(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Re-scan the configured targets on an interval and alert only on new or changed paths",
+		RunE:  buildMonitorFunction(logger),
+	}
+
+	cmd.Flags().String(
+		flagMonitorConfig,
+		"",
+		"yaml file describing the targets to monitor, the scan settings, the interval and the webhook to alert",
+	)
+	common.Must(cmd.MarkFlagFilename(flagMonitorConfig))
+	common.Must(cmd.MarkFlagRequired(flagMonitorConfig))
+
+	cmd.Flags().Int(
+		flagMonitorRuns,
+		0,
+		"amount of runs to perform before exiting (0 means until interrupted)",
+	)
+
+	return cmd
+}
+
+func buildMonitorFunction(logger *logrus.Logger) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cnf, err := monitor.LoadConfig(cmd.Flag(flagMonitorConfig).Value.String())
+		if err != nil {
+			return err
+		}
+
+		runs, err := cmd.Flags().GetInt(flagMonitorRuns)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagMonitorRuns)
+		}
+
+		if err := os.MkdirAll(cnf.StateDir, 0o750); err != nil {
+			return errors.Wrapf(err, "failed to create state directory `%s`", cnf.StateDir)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return startMonitor(ctx, logger, cnf, runs)
+	}
+}
+
+func startMonitor(ctx context.Context, logger *logrus.Logger, cnf monitor.Config, runs int) error {
+	ticker := time.NewTicker(cnf.Interval)
+	defer ticker.Stop()
+
+	for run := 1; ; run++ {
+		logger.WithField("run", run).Info("Starting monitor run")
+
+		for _, target := range cnf.Targets {
+			if err := monitorTarget(ctx, logger, cnf, target); err != nil {
+				logger.WithError(err).WithField("target", target.Name).Error("failed to monitor target")
+			}
+
+			if ctx.Err() != nil {
+				logger.Info("Monitor interrupted")
+
+				return nil
+			}
+		}
+
+		if runs > 0 && run >= runs {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Monitor interrupted")
+
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func monitorTarget(ctx context.Context, logger *logrus.Logger, cnf monitor.Config, target monitor.Target) error {
+	l := logger.WithField("target", target.Name)
+
+	u, err := url.ParseRequestURI(target.URL)
+	if err != nil {
+		return errors.Wrap(err, "invalid target url")
+	}
+
+	current, err := scanTarget(ctx, logger, monitorScanConfig(cnf.Scan, target), u)
+	if err != nil {
+		return err
+	}
+
+	// a partial run would report the paths not reached as new on the next run
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	statePath := filepath.Join(cnf.StateDir, target.Name+".json")
+
+	previous, err := loadMonitorState(statePath)
+	if err != nil {
+		return err
+	}
+
+	if previous == nil {
+		l.WithField("results", len(current)).Info("First run, recorded the paths found")
+
+		return saveMonitorState(statePath, current)
+	}
+
+	changes := monitor.Diff(previous, current)
+	if len(changes) == 0 {
+		l.Info("No new or changed paths")
+
+		return saveMonitorState(statePath, current)
+	}
+
+	alert := monitor.NewAlert(target, changes)
+
+	l.WithField("changes", len(changes)).Warn(alert.Text)
+
+	// the state is only saved once the changes have been alerted, so that they are alerted again on the next
+	// run when the webhook fails
+	if cnf.Webhook != "" {
+		c := &http.Client{Timeout: time.Millisecond * time.Duration(cnf.Scan.HTTPTimeout)}
+
+		if err := monitor.NewWebhookNotifier(c, cnf.Webhook).Notify(ctx, alert); err != nil {
+			return errors.Wrap(err, "failed to alert")
+		}
+	}
+
+	return saveMonitorState(statePath, current)
+}
+
+func monitorScanConfig(s monitor.ScanConfig, target monitor.Target) *scan.Config {
+	return &scan.Config{
+		DictionaryPath:                      target.Dictionary,
+		DictionaryTimeoutInMilliseconds:     s.HTTPTimeout,
+		HTTPMethods:                         s.HTTPMethods,
+		HTTPStatusesToIgnore:                s.HTTPStatusesToIgnore,
+		Threads:                             s.Threads,
+		TimeoutInMilliseconds:               s.HTTPTimeout,
+		CacheRequests:                       true,
+		ScanDepth:                           s.ScanDepth,
+		UserAgent:                           s.UserAgent,
+		Headers:                             s.Headers,
+		ShouldSkipSSLCertificatesValidation: s.NoCheckCertificate,
+		RulesPath:                           s.Rules,
+		ClassifyPath:                        s.Classify,
+	}
+}
+
+func scanTarget(ctx context.Context, logger *logrus.Logger, cnf *scan.Config, u *url.URL) ([]scan.Result, error) {
+	dict, err := buildDictionary(cnf, u)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := buildScanner(cnf, dict, u, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]scan.Result, 0)
+
+	for r := range s.Scan(ctx, u, cnf.Threads) {
+		results = append(results, r)
+	}
+
+	if ctx.Err() != nil {
+		return results, nil
+	}
+
+	// the paths not reached would be reported as removed, and as new once reachable again
+	if err := s.Err(); err != nil {
+		return nil, errors.Wrap(err, "scan aborted")
+	}
+
+	if stats := s.Stats(); stats.Errors > 0 && stats.Errors == stats.Requests {
+		return nil, errors.Errorf("scan failed, all the %d requests performed failed", stats.Errors)
+	}
+
+	return results, nil
+}
+
+// loadMonitorState returns the results of the previous run, nil when the target was never scanned.
+func loadMonitorState(path string) ([]scan.Result, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	results, err := result.LoadResultsFromFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the results of the previous run")
+	}
+
+	return results, nil
+}
+
+func saveMonitorState(path string, results []scan.Result) error {
+	saver, err := output.NewFileSaver(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to store the results of the run")
+	}
+
+	for _, r := range results {
+		if err := saver.Save(r); err != nil {
+			_ = saver.Close()
+
+			return errors.Wrap(err, "failed to store the results of the run")
+		}
+	}
+
+	return errors.Wrap(saver.Close(), "failed to store the results of the run")
+}
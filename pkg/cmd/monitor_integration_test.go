@@ -0,0 +1,254 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorShouldAlertOnlyOnNewOrChangedPaths(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var homeRequests int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/home":
+				if atomic.AddInt32(&homeRequests, 1) > 1 {
+					w.WriteHeader(http.StatusForbidden)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			case "/blabla":
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}),
+	)
+	defer testServer.Close()
+
+	alerts := make([]map[string]interface{}, 0)
+	mx := sync.Mutex{}
+
+	webhookServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			alert := make(map[string]interface{})
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+
+			mx.Lock()
+			alerts = append(alerts, alert)
+			mx.Unlock()
+		}),
+	)
+	defer webhookServer.Close()
+
+	dictionary, err := filepath.Abs("testdata/dict2.txt")
+	assert.NoError(t, err)
+
+	stateDir := t.TempDir()
+	configPath := filepath.Join(t.TempDir(), "monitor.yaml")
+
+	config := fmt.Sprintf(
+		"interval: 10ms\nstate-dir: %s\nwebhook: %s\nscan:\n  dictionary: %s\n  scan-depth: 0\n"+
+			"targets:\n  - name: app\n    url: %s\n",
+		stateDir,
+		webhookServer.URL,
+		dictionary,
+		testServer.URL,
+	)
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte(config), 0o600))
+
+	err = executeCommand(c, "monitor", "--config", configPath, "--runs", "2")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "First run, recorded the paths found")
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "app", alerts[0]["target"])
+	assert.Equal(
+		t,
+		fmt.Sprintf(
+			"dirstalk: 1 new or changed paths on %s\n[changed] GET %s/home 403 (was 200)",
+			testServer.URL,
+			testServer.URL,
+		),
+		alerts[0]["text"],
+	)
+
+	state, err := ioutil.ReadFile(filepath.Join(stateDir, "app.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(state), `"StatusCode":403`)
+}
+
+func TestMonitorShouldAlertAgainTheChangesWhenTheWebhookFails(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var homeRequests int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/home" {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			if atomic.AddInt32(&homeRequests, 1) > 1 {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer testServer.Close()
+
+	var webhookRequests int32
+
+	alerts := make([]string, 0)
+	mx := sync.Mutex{}
+
+	webhookServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the first alert fails
+			if atomic.AddInt32(&webhookRequests, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			alert := make(map[string]interface{})
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+
+			mx.Lock()
+			alerts = append(alerts, fmt.Sprint(alert["text"]))
+			mx.Unlock()
+		}),
+	)
+	defer webhookServer.Close()
+
+	dictionary, err := filepath.Abs("testdata/dict2.txt")
+	assert.NoError(t, err)
+
+	stateDir := t.TempDir()
+	configPath := filepath.Join(t.TempDir(), "monitor.yaml")
+
+	config := fmt.Sprintf(
+		"interval: 10ms\nstate-dir: %s\nwebhook: %s\nscan:\n  dictionary: %s\n  scan-depth: 0\n"+
+			"targets:\n  - name: app\n    url: %s\n",
+		stateDir,
+		webhookServer.URL,
+		dictionary,
+		testServer.URL,
+	)
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte(config), 0o600))
+
+	err = executeCommand(c, "monitor", "--config", configPath, "--runs", "4")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "failed to alert")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&webhookRequests))
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	// the change is alerted on the run following the failure, and then no more
+	assert.Len(t, alerts, 1)
+	assert.Contains(t, alerts[0], "[changed] GET "+testServer.URL+"/home 403 (was 200)")
+}
+
+func TestMonitorShouldNotAlertNorSaveTheStateWhenTheRequestsFail(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var requests int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the target goes down after the first run
+			if atomic.AddInt32(&requests, 1) > 4 {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				assert.NoError(t, err)
+				assert.NoError(t, conn.Close())
+
+				return
+			}
+
+			if r.URL.Path == "/home" {
+				w.WriteHeader(http.StatusOK)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	webhookServer, webhookAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer webhookServer.Close()
+
+	dictionary, err := filepath.Abs("testdata/dict2.txt")
+	assert.NoError(t, err)
+
+	stateDir := t.TempDir()
+	configPath := filepath.Join(t.TempDir(), "monitor.yaml")
+
+	config := fmt.Sprintf(
+		"interval: 10ms\nstate-dir: %s\nwebhook: %s\nscan:\n  dictionary: %s\n  scan-depth: 0\n"+
+			"targets:\n  - name: app\n    url: %s\n",
+		stateDir,
+		webhookServer.URL,
+		dictionary,
+		testServer.URL,
+	)
+	assert.NoError(t, ioutil.WriteFile(configPath, []byte(config), 0o600))
+
+	err = executeCommand(c, "monitor", "--config", configPath, "--runs", "2")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "First run, recorded the paths found")
+	assert.Contains(t, loggerBuffer.String(), "all the 4 requests performed failed")
+	assert.Equal(t, 0, webhookAssertion.Len())
+
+	state, err := ioutil.ReadFile(filepath.Join(stateDir, "app.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(state), "/home")
+}
+
+func TestMonitorShouldErrWithInvalidConfig(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "monitor", "--config", "testdata/missing.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read monitor configuration")
+}
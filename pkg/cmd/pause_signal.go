@@ -0,0 +1,14 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyPauseSignals relays SIGUSR1, which pauses and resumes the scan, to the given channel.
+func notifyPauseSignals(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
@@ -0,0 +1,7 @@
+package cmd
+
+import "os"
+
+// notifyPauseSignals does nothing as SIGUSR1 does not exist on windows, where the scan can only be paused
+// with the `p` interactive command.
+func notifyPauseSignals(_ chan<- os.Signal) {}
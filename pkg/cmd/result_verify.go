@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+)
+
+func NewResultVerifyCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result.verify",
+		Short: "Verify that a result file was signed with the given key and was not modified since",
+		RunE:  buildResultVerifyCmd(out),
+	}
+
+	cmd.Flags().StringP(
+		flagResultVerifyResultFile,
+		flagResultVerifyResultFileShort,
+		"",
+		"result file to verify",
+	)
+	common.Must(cmd.MarkFlagFilename(flagResultVerifyResultFile))
+	common.Must(cmd.MarkFlagRequired(flagResultVerifyResultFile))
+
+	cmd.Flags().String(
+		flagResultVerifyKey,
+		"",
+		"PEM encoded public key (or private key) matching the key used to sign the results",
+	)
+	common.Must(cmd.MarkFlagFilename(flagResultVerifyKey))
+	common.Must(cmd.MarkFlagRequired(flagResultVerifyKey))
+
+	cmd.Flags().String(
+		flagResultVerifySignature,
+		"",
+		"signature to verify (default to the result file path followed by .sig)",
+	)
+	common.Must(cmd.MarkFlagFilename(flagResultVerifySignature))
+
+	return cmd
+}
+
+func buildResultVerifyCmd(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		resultFilePath := cmd.Flag(flagResultVerifyResultFile).Value.String()
+
+		signaturePath := cmd.Flag(flagResultVerifySignature).Value.String()
+		if signaturePath == "" {
+			signaturePath = result.SignaturePath(resultFilePath)
+		}
+
+		publicKey, err := result.LoadPublicKey(cmd.Flag(flagResultVerifyKey).Value.String())
+		if err != nil {
+			return err
+		}
+
+		signature, err := result.LoadSignature(signaturePath)
+		if err != nil {
+			return err
+		}
+
+		statement, err := result.Verify(publicKey, signature, resultFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "verification of %s failed", resultFilePath)
+		}
+
+		_, err = fmt.Fprintf(
+			out,
+			"%s: valid %s signature\nurl: %s\nstarted at: %s\nfinished at: %s\nsigned at: %s\n"+
+				"results sha256: %s\nconfig sha256: %s\n",
+			resultFilePath,
+			signature.Algorithm,
+			statement.URL,
+			statement.StartedAt.Format(time.RFC3339),
+			statement.FinishedAt.Format(time.RFC3339),
+			statement.SignedAt.Format(time.RFC3339),
+			statement.ResultsSHA256,
+			statement.ConfigSHA256,
+		)
+
+		return errors.Wrap(err, "failed to print verification result")
+	}
+}
@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanWithSignedResultsCanBeVerified(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/home" {
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer testServer.Close()
+
+	dir := t.TempDir()
+	privateKeyPath, publicKeyPath := writeEd25519Keys(t, dir)
+	outputFilename := filepath.Join(dir, "results.json")
+
+	err := executeCommand(
+		createCommand(logger),
+		"scan",
+		testServer.URL,
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--out",
+		outputFilename,
+		"--sign-results",
+		privateKeyPath,
+	)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(outputFilename + ".sig")
+	assert.NoError(t, err)
+
+	err = executeCommand(createCommand(logger), "result.verify", "-r", outputFilename, "--key", publicKeyPath)
+	assert.NoError(t, err)
+	assert.Contains(t, loggerBuffer.String(), outputFilename+": valid ed25519 signature")
+	assert.Contains(t, loggerBuffer.String(), "url: "+testServer.URL)
+
+	f, err := os.OpenFile(outputFilename, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"StatusCode":200}` + "\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	err = executeCommand(createCommand(logger), "result.verify", "-r", outputFilename, "--key", publicKeyPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "was modified after being signed")
+}
+
+func TestScanWithSignResultsShouldErrWithoutOutput(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	privateKeyPath, _ := writeEd25519Keys(t, t.TempDir())
+
+	err := executeCommand(
+		createCommand(logger),
+		"scan",
+		"http://localhost/",
+		"--dictionary",
+		"testdata/dict2.txt",
+		"--sign-results",
+		privateKeyPath,
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sign-results requires out or output-dir")
+}
+
+func TestResultVerifyShouldErrForMissingSignature(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	_, publicKeyPath := writeEd25519Keys(t, t.TempDir())
+
+	err := executeCommand(createCommand(logger), "result.verify", "-r", "testdata/out.txt", "--key", publicKeyPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read signature `testdata/out.txt.sig`")
+}
+
+func writeEd25519Keys(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	rawPrivateKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	rawPublicKey, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.NoError(t, err)
+
+	privateKeyPath := filepath.Join(dir, "key.pem")
+	publicKeyPath := filepath.Join(dir, "key.pub")
+
+	assert.NoError(
+		t,
+		ioutil.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rawPrivateKey}), 0o600),
+	)
+	assert.NoError(
+		t,
+		ioutil.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rawPublicKey}), 0o600),
+	)
+
+	return privateKeyPath, publicKeyPath
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -14,7 +15,7 @@ import (
 func NewResultViewCommand(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "result.view",
-		Short: "Read a scan output file and render the folder tree",
+		Short: "Read a scan output file and render the folder tree with the status, size and content type of each path",
 		RunE:  buildResultViewCmd(out),
 	}
 
@@ -27,6 +28,24 @@ func NewResultViewCommand(out io.Writer) *cobra.Command {
 	common.Must(cmd.MarkFlagFilename(flagResultViewResultFile))
 	common.Must(cmd.MarkFlagRequired(flagResultViewResultFile))
 
+	cmd.Flags().Bool(
+		flagResultViewOnlyFound,
+		false,
+		"only render the paths that replied with a 2xx or 3xx status code",
+	)
+
+	cmd.Flags().Bool(
+		flagResultViewNoColor,
+		false,
+		"do not color the results by status code class",
+	)
+
+	cmd.Flags().Bool(
+		flagResultViewColor,
+		false,
+		"color the results by status code class even when the output is not a terminal, eg: when piped to less -R",
+	)
+
 	return cmd
 }
 
@@ -39,7 +58,30 @@ func buildResultViewCmd(out io.Writer) func(cmd *cobra.Command, args []string) e
 			return errors.Wrapf(err, "failed to load results from %s", resultFilePath)
 		}
 
-		treeAsString := tree.NewResultTreeProducer().String(results)
+		onlyFound, err := cmd.Flags().GetBool(flagResultViewOnlyFound)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultViewOnlyFound)
+		}
+
+		noColor, err := cmd.Flags().GetBool(flagResultViewNoColor)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultViewNoColor)
+		}
+
+		forceColor, err := cmd.Flags().GetBool(flagResultViewColor)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultViewColor)
+		}
+
+		if noColor && forceColor {
+			return errors.Errorf("%s cannot be used together with %s", flagResultViewNoColor, flagResultViewColor)
+		}
+
+		// the escape sequences would end up in the files the output is redirected to
+		f, isFile := out.(*os.File)
+		color := forceColor || (!noColor && isFile && isTerminal(f))
+
+		treeAsString := tree.NewDetailedResultTreeProducer(onlyFound, color).String(results)
 
 		_, err = fmt.Fprintln(out, treeAsString)
 
@@ -38,15 +38,57 @@ func TestResultView(t *testing.T) {
 	c := createCommand(logger)
 	assert.NotNil(t, c)
 
-	err := executeCommand(c, "result.view", "-r", "testdata/out.txt")
+	err := executeCommand(c, "result.view", "-r", "testdata/out.txt", "--color")
+	assert.NoError(t, err)
+
+	expected := "/\n" +
+		"├── adview \x1b[32m[204] [GET] [0B]\x1b[0m\n" +
+		"├── partners \x1b[32m[200] [GET] [0B]\x1b[0m\n" +
+		"│   └── terms \x1b[32m[200] [GET] [0B]\x1b[0m\n" +
+		"└── s \x1b[33m[400] [GET] [0B]\x1b[0m\n"
+
+	assert.Contains(t, loggerBuffer.String(), expected)
+}
+
+func TestResultViewWithOnlyFoundAndNoColor(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "result.view", "-r", "testdata/out.txt", "--only-found", "--no-color")
 	assert.NoError(t, err)
 
 	expected := `/
-├── adview
-├── partners
-│   └── terms
-└── s
+├── adview [204] [GET] [0B]
+└── partners [200] [GET] [0B]
+    └── terms [200] [GET] [0B]
 `
 
 	assert.Contains(t, loggerBuffer.String(), expected)
+	assert.NotContains(t, loggerBuffer.String(), "\x1b[")
+}
+
+func TestResultViewShouldNotColorTheResultsWhenTheOutputIsNotATerminal(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "result.view", "-r", "testdata/out.txt")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "├── adview [204] [GET] [0B]\n")
+	assert.NotContains(t, loggerBuffer.String(), "\x1b[")
+}
+
+func TestResultViewWithColorAndNoColorShouldErr(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "result.view", "-r", "testdata/out.txt", "--color", "--no-color")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no-color cannot be used together with color")
 }
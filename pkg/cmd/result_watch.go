@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+)
+
+func NewResultWatchCommand(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result.watch [result-file]",
+		Short: "Periodically re-fetch the findings of a scan output file and report the ones whose content changed",
+		Args:  cobra.ExactArgs(1),
+		RunE:  buildResultWatchCmd(out),
+	}
+
+	cmd.Flags().Duration(
+		flagResultWatchInterval,
+		time.Hour,
+		"time to wait between two checks; eg: 30m, 1h",
+	)
+
+	cmd.Flags().Int(
+		flagResultWatchChecks,
+		0,
+		"amount of checks to perform before exiting, the first one records the current content (0 means until interrupted)",
+	)
+
+	cmd.Flags().Int(
+		flagResultWatchHTTPTimeout,
+		5000,
+		"timeout in milliseconds",
+	)
+
+	return cmd
+}
+
+func buildResultWatchCmd(out io.Writer) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		resultFilePath := args[0]
+
+		results, err := result.LoadResultsFromFile(resultFilePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load results from %s", resultFilePath)
+		}
+
+		interval, err := cmd.Flags().GetDuration(flagResultWatchInterval)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultWatchInterval)
+		}
+
+		if interval <= 0 {
+			return errors.Errorf("%s must be greater than 0", flagResultWatchInterval)
+		}
+
+		checks, err := cmd.Flags().GetInt(flagResultWatchChecks)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultWatchChecks)
+		}
+
+		timeout, err := cmd.Flags().GetInt(flagResultWatchHTTPTimeout)
+		if err != nil {
+			return errors.Wrapf(err, failedToReadPropertyError, flagResultWatchHTTPTimeout)
+		}
+
+		c := &http.Client{
+			Timeout: time.Millisecond * time.Duration(timeout),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return watchResults(ctx, out, result.NewWatcher(c, results), len(results), interval, checks)
+	}
+}
+
+func watchResults(
+	ctx context.Context,
+	out io.Writer,
+	watcher *result.Watcher,
+	findings int,
+	interval time.Duration,
+	checks int,
+) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for check := 1; ; check++ {
+		changes := watcher.Check(ctx)
+
+		// the changes detected while interrupting are caused by the cancellation
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if check == 1 {
+			_, err := fmt.Fprintf(out, "%s recorded the content of %d findings\n", time.Now().Format(time.RFC3339), findings)
+			if err != nil {
+				return errors.Wrap(err, "failed to print watch status")
+			}
+		}
+
+		for _, change := range changes {
+			if _, err := fmt.Fprintln(out, formatChange(change)); err != nil {
+				return errors.Wrap(err, "failed to print change")
+			}
+		}
+
+		if checks > 0 && check >= checks {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func formatChange(change result.Change) string {
+	return fmt.Sprintf(
+		"%s %s %s changed: %s -> %s",
+		change.CheckedAt.Format(time.RFC3339),
+		change.Result.Target.Method,
+		change.Result.URL.String(),
+		formatSnapshot(change.Previous),
+		formatSnapshot(change.Current),
+	)
+}
+
+func formatSnapshot(snapshot result.Snapshot) string {
+	if snapshot.Err != "" {
+		return fmt.Sprintf("[error: %s]", snapshot.Err)
+	}
+
+	return fmt.Sprintf("[%d] [%d bytes] [sha256 %.12s]", snapshot.StatusCode, snapshot.ContentLength, snapshot.Hash)
+}
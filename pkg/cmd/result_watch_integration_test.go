@@ -0,0 +1,80 @@
+package cmd_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultWatchShouldReportChangedFindings(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	var requests int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/config.php" && atomic.AddInt32(&requests, 1) > 1 {
+				_, _ = w.Write([]byte("new content"))
+
+				return
+			}
+
+			_, _ = w.Write([]byte("content"))
+		}),
+	)
+	defer testServer.Close()
+
+	resultFile := filepath.Join(t.TempDir(), "results.json")
+
+	saver, err := output.NewFileSaver(resultFile)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/config.php", "/index.php"} {
+		assert.NoError(t, saver.Save(scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: http.StatusOK,
+			URL:        *test.MustParseURL(t, testServer.URL+path),
+		}))
+	}
+
+	assert.NoError(t, saver.Close())
+
+	err = executeCommand(c, "result.watch", resultFile, "--interval", "10ms", "--checks", "2")
+	assert.NoError(t, err)
+
+	assert.Contains(t, loggerBuffer.String(), "recorded the content of 2 findings")
+	assert.Contains(t, loggerBuffer.String(), "GET "+testServer.URL+"/config.php changed: [200] [7 bytes]")
+	assert.Contains(t, loggerBuffer.String(), "-> [200] [11 bytes]")
+	assert.NotContains(t, loggerBuffer.String(), "/index.php changed")
+}
+
+func TestResultWatchShouldErrWithoutResultFile(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "result.watch")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "accepts 1 arg(s)")
+}
+
+func TestResultWatchShouldErrWithInvalidInterval(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	c := createCommand(logger)
+	assert.NotNil(t, c)
+
+	err := executeCommand(c, "result.watch", "testdata/out.txt", "--interval", "0s")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "interval must be greater than 0")
+}
@@ -1,21 +1,45 @@
 package cmd
 
 import (
+	"io"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stefanoj3/dirstalk/pkg/common/logging"
 )
 
 func NewRootCommand(logger *logrus.Logger) *cobra.Command {
-	var verbose bool
+	var (
+		verbose bool
+		logFile string
+		closer  io.Closer
+	)
 
 	cmd := &cobra.Command{
 		Use:   "dirstalk",
 		Short: "Stalk the given url trying to enumerate files and folders",
 		Long:  `dirstalk is a tool that attempts to enumerate files and folders starting from a given URL`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if verbose {
 				logger.SetLevel(logrus.DebugLevel)
 			}
+
+			if logFile == "" {
+				return nil
+			}
+
+			var err error
+
+			closer, err = logging.AddLogFile(logger, logFile)
+
+			return err
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if closer == nil {
+				return nil
+			}
+
+			return closer.Close()
 		},
 	}
 
@@ -27,5 +51,12 @@ func NewRootCommand(logger *logrus.Logger) *cobra.Command {
 		"verbose mode",
 	)
 
+	cmd.PersistentFlags().StringVar(
+		&logFile,
+		flagRootLogFile,
+		"",
+		"file where to write the full verbose log, the console keeps logging at the normal verbosity",
+	)
+
 	return cmd
 }
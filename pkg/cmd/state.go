@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// stateSaveInterval is how often the state of the scan is saved while scanning.
+const stateSaveInterval = 10 * time.Second
+
+// shutdownGracePeriod is how long the requests in flight are given to complete once the scan is interrupted.
+const shutdownGracePeriod = 5 * time.Second
+
+// scanState is what is saved to resume a scan once interrupted, see --state-file and --resume.
+type scanState struct {
+	URL string
+	// TargetsSHA256 tells apart the sequences of targets, as the progress is the amount of targets scanned
+	TargetsSHA256 string
+	Complete      bool
+	Progress      scan.Progress
+	Results       []scan.Result `json:",omitempty"`
+}
+
+// targetsSHA256 hashes the dictionary and the flags shaping the sequence of targets scanned, which the
+// progress of a scan refers to.
+func targetsSHA256(cnf *scan.Config, dict []string) string {
+	seed := int64(0)
+	if cnf.RandomizeOrder {
+		seed = cnf.Seed
+	}
+
+	hash := sha256.New()
+
+	_, _ = fmt.Fprintf(hash, "%q %q %t %d %d\n", cnf.HTTPMethods, cnf.Extensions, cnf.RandomizeOrder, seed, cnf.ScanDepth)
+
+	for _, entry := range dict {
+		_, _ = fmt.Fprintf(hash, "%q\n", entry)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// loadScanState reads the state saved for the scan of the given URL with the given sequence of targets,
+// see targetsSHA256.
+func loadScanState(path string, u string, targetsSHA256 string) (scanState, error) {
+	state := scanState{}
+
+	raw, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		return state, errors.Wrapf(err, "failed to read the scan state %s", path)
+	}
+
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return state, errors.Wrapf(err, "failed to parse the scan state %s", path)
+	}
+
+	if state.URL != u {
+		return state, errors.Errorf("the scan state %s belongs to the scan of %s, not of %s", path, state.URL, u)
+	}
+
+	if state.TargetsSHA256 != targetsSHA256 {
+		return state, errors.Errorf(
+			"the scan state %s belongs to a scan with another dictionary or other --%s, --%s, --%s, --%s or --%s",
+			path,
+			flagScanHTTPMethods,
+			flagScanExtensions,
+			flagScanRandomizeOrder,
+			flagScanSeed,
+			flagScanScanDepth,
+		)
+	}
+
+	if state.Complete {
+		return state, errors.Errorf("the scan saved in %s is already complete", path)
+	}
+
+	return state, nil
+}
+
+// saveScanState writes the state through a temporary file, so that the previous state is kept whole if the
+// application is killed while saving it.
+func saveScanState(path string, state scanState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the scan state")
+	}
+
+	if err := os.WriteFile(path+".tmp", raw, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write the scan state %s", path)
+	}
+
+	return errors.Wrapf(os.Rename(path+".tmp", path), "failed to write the scan state %s", path)
+}
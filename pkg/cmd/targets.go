@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+)
+
+// targetsFromStdin is the value of --targets reading the targets from stdin.
+const targetsFromStdin = "-"
+
+// loadTargets reads the targets to scan from the given file, or from stdin when the path is "-".
+func loadTargets(path string, stdin io.Reader) ([]string, error) {
+	source := stdin
+
+	if path != targetsFromStdin {
+		file, err := os.Open(path) // #nosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open the targets file %s", path)
+		}
+
+		defer file.Close() //nolint:errcheck
+
+		source = file
+	}
+
+	targets := make([]string, 0)
+
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		target := strings.TrimSpace(scanner.Text())
+		if target == "" || strings.HasPrefix(target, "#") {
+			continue
+		}
+
+		targets = append(targets, target)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the targets from %s", path)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no targets found in %s", path)
+	}
+
+	return targets, nil
+}
+
+// resolveTargets parses the given targets, probing the hosts of the IPs, CIDRs and IP ranges for HTTP responders.
+func resolveTargets(logger *logrus.Logger, cnf *scan.Config, rawTargets []string) ([]*url.URL, error) {
+	targets := make([]*url.URL, 0, len(rawTargets))
+	hosts := make([]string, 0)
+
+	for _, target := range rawTargets {
+		if !discovery.IsHosts(target) {
+			u, err := url.ParseRequestURI(target)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid target %s", target)
+			}
+
+			targets = append(targets, u)
+
+			continue
+		}
+
+		ips, err := discovery.ExpandHosts(target)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid target %s", target)
+		}
+
+		for _, ip := range ips {
+			hosts = append(hosts, ip.String())
+		}
+	}
+
+	if len(hosts) == 0 {
+		return targets, nil
+	}
+
+	live, err := probeHosts(logger, cnf, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	targets = append(targets, live...)
+
+	if len(targets) == 0 {
+		return nil, errors.New("no HTTP responders found, nothing to scan")
+	}
+
+	return targets, nil
+}
+
+// probeHosts returns the URL of every HTTP responder found on the given hosts, IPs or host names.
+func probeHosts(logger *logrus.Logger, cnf *scan.Config, hosts []string) ([]*url.URL, error) {
+	// the hosts are reached the way the targets found are scanned: through the same proxies, tunnels and scope
+	opts, err := buildConnectionOptions(cnf, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// the hosts are just looked for, so their certificates are not verified
+	c, err := client.NewClientFromConfig(
+		cnf.ProbeTimeoutInMilliseconds,
+		cnf.Socks5Url,
+		cnf.UserAgent,
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build probe client")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"hosts": len(hosts),
+		"ports": cnf.ProbePorts,
+	}).Info("Probing for HTTP responders")
+
+	live := discovery.NewProber(c, cnf.ProbePorts, cnf.Threads, logger).Probe(context.Background(), hosts)
+
+	logger.Infof("Found %d HTTP responders", len(live))
+
+	return live, nil
+}
+
+// checkMultiTargetConfig fails for the flags that cannot be shared among several targets.
+func checkMultiTargetConfig(cnf *scan.Config) error {
+	// every target would write to the same file
+	shared := []struct{ flag, value string }{
+		{flag: flagScanResultOutput, value: cnf.Out},
+		{flag: flagScanResume, value: cnf.ResumePath},
+		{flag: flagScanStateFile, value: cnf.StatePath},
+	}
+
+	for _, s := range shared {
+		if s.value != "" {
+			return errors.Errorf(
+				"%s cannot be used with multiple targets, use %s to store the results of every target",
+				s.flag,
+				flagScanResultOutputDir,
+			)
+		}
+	}
+
+	return nil
+}
+
+// scanTargets scans the given targets one after the other, or cnf.ParallelTargets at a time.
+// The failure of a target does not stop the others, while an interruption leaves the remaining
+// ones unscanned.
+func scanTargets(logger *logrus.Logger, cnf *scan.Config, targets []*url.URL) error {
+	// the commands typed while scanning could not tell which of the targets they are meant for
+	cnf.Interactive = false
+
+	osSignals := make(chan os.Signal, 1)
+	signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
+
+	defer signal.Stop(osSignals)
+
+	slots := make(chan struct{}, cnf.ParallelTargets)
+
+	// the threads are shared among the targets scanned at the same time
+	limiter := scan.NewConcurrencyLimiter(cnf.Threads, cnf.ThreadsPerHost)
+
+	wg := sync.WaitGroup{}
+	mx := sync.Mutex{}
+	failed := 0
+
+	for i, target := range targets {
+		if !acquireTargetSlot(slots, osSignals) {
+			logger.Infof("Interrupted, %d of %d targets were not scanned", len(targets)-i, len(targets))
+
+			break
+		}
+
+		wg.Add(1)
+
+		go func(u *url.URL) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// every scan gets its own copy of the configuration
+			targetCnf := *cnf
+
+			if err := startScan(logger, &targetCnf, u, scan.WithConcurrencyLimiter(limiter)); err != nil {
+				logger.WithError(err).WithField("url", u.String()).Error("Failed to scan the target")
+
+				mx.Lock()
+				failed++
+				mx.Unlock()
+			}
+		}(target)
+	}
+
+	wg.Wait()
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d targets failed", failed, len(targets))
+	}
+
+	return nil
+}
+
+// acquireTargetSlot waits for a slot to scan another target, returning false when interrupted meanwhile.
+func acquireTargetSlot(slots chan<- struct{}, interrupted <-chan os.Signal) bool {
+	// select picks randomly among the ready cases, so the interruption is checked first
+	select {
+	case <-interrupted:
+		return false
+	default:
+	}
+
+	select {
+	case <-interrupted:
+		return false
+	case slots <- struct{}{}:
+		return true
+	}
+}
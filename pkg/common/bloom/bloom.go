@@ -0,0 +1,67 @@
+package bloom
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// DefaultErrorRate is used when the error rate given to New is not a valid probability.
+const DefaultErrorRate = 0.001
+
+// Filter remembers the keys added in a fixed amount of memory, sized for the expected amount of keys and for
+// the rate of false positives accepted, that is of keys wrongly told to be added already.
+type Filter struct {
+	mx     sync.Mutex
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// New creates a Filter sized for the given amount of keys, the rate of false positives grows once they are
+// exceeded.
+func New(capacity uint, errorRate float64) *Filter {
+	if capacity == 0 {
+		capacity = 1
+	}
+
+	if errorRate <= 0 || errorRate >= 1 {
+		errorRate = DefaultErrorRate
+	}
+
+	size := uint64(math.Ceil(-float64(capacity) * math.Log(errorRate) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Max(1, math.Round(float64(size)/float64(capacity)*math.Ln2)))
+
+	return &Filter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+// Add sets the bits of the key, derived from two halves of its hash by double hashing, telling whether it
+// was already added.
+func (f *Filter) Add(key string) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := h.Sum64()
+
+	h1, h2 := sum&math.MaxUint32, sum>>32|1
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	added := true
+
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+
+		if f.bits[word]&mask == 0 {
+			added = false
+			f.bits[word] |= mask
+		}
+	}
+
+	return added
+}
@@ -0,0 +1,21 @@
+package bloom_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterShouldTellTheKeysAlreadyAdded(t *testing.T) {
+	sut := bloom.New(1000, 0.001)
+
+	for i := 0; i < 1000; i++ {
+		assert.False(t, sut.Add("/"+strconv.Itoa(i)))
+	}
+
+	for i := 0; i < 1000; i++ {
+		assert.True(t, sut.Add("/"+strconv.Itoa(i)))
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// AddLogFile makes the logger write every entry, regardless of the console verbosity, to the given file.
+// The console keeps logging at the current level of the logger, see SetConsoleLevel.
+func AddLogFile(logger *logrus.Logger, path string) (io.Closer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open log file %s", path)
+	}
+
+	logger.AddHook(&writerHook{
+		writer:    file,
+		formatter: &logrus.TextFormatter{DisableColors: true, FullTimestamp: true},
+	})
+
+	logger.SetFormatter(&consoleFormatter{Formatter: logger.Formatter, level: logger.GetLevel()})
+	logger.SetLevel(logrus.DebugLevel)
+
+	return file, nil
+}
+
+// ConsoleLevel returns the level of the entries printed to the console.
+func ConsoleLevel(logger *logrus.Logger) logrus.Level {
+	if f, ok := logger.Formatter.(*consoleFormatter); ok {
+		return f.getLevel()
+	}
+
+	return logger.GetLevel()
+}
+
+// SetConsoleLevel changes the level of the entries printed to the console, without affecting the log file.
+func SetConsoleLevel(logger *logrus.Logger, level logrus.Level) {
+	if f, ok := logger.Formatter.(*consoleFormatter); ok {
+		f.setLevel(level)
+
+		return
+	}
+
+	logger.SetLevel(level)
+}
+
+// writerHook writes every entry to the underlying writer.
+type writerHook struct {
+	mx        sync.Mutex
+	writer    io.Writer
+	formatter logrus.Formatter
+}
+
+func (h *writerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *writerHook) Fire(entry *logrus.Entry) error {
+	serialized, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	_, err = h.writer.Write(serialized)
+
+	return err
+}
+
+// consoleFormatter drops the entries above its level, allowing the logger to be more verbose than the console.
+type consoleFormatter struct {
+	logrus.Formatter
+	mx    sync.RWMutex
+	level logrus.Level
+}
+
+func (f *consoleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if entry.Level > f.getLevel() {
+		return nil, nil
+	}
+
+	return f.Formatter.Format(entry)
+}
+
+func (f *consoleFormatter) getLevel() logrus.Level {
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+
+	return f.level
+}
+
+func (f *consoleFormatter) setLevel(level logrus.Level) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.level = level
+}
@@ -0,0 +1,60 @@
+package logging_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/common/logging"
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddLogFileShouldWriteEveryEntryToTheFile(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.InfoLevel)
+
+	path := filepath.Join(t.TempDir(), "scan.log")
+
+	closer, err := logging.AddLogFile(logger, path)
+	assert.NoError(t, err)
+
+	logger.Debug("a debug message")
+	logger.Info("an info message")
+
+	assert.Equal(t, logrus.InfoLevel, logging.ConsoleLevel(logger))
+
+	logging.SetConsoleLevel(logger, logrus.DebugLevel)
+	logger.Debug("another debug message")
+
+	assert.NoError(t, closer.Close())
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(content), "a debug message")
+	assert.Contains(t, string(content), "an info message")
+	assert.Contains(t, string(content), "another debug message")
+
+	assert.NotContains(t, loggerBuffer.String(), "a debug message")
+	assert.Contains(t, loggerBuffer.String(), "an info message")
+	assert.Contains(t, loggerBuffer.String(), "another debug message")
+}
+
+func TestAddLogFileShouldFailForInvalidPath(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	closer, err := logging.AddLogFile(logger, filepath.Join(t.TempDir(), "missing", "scan.log"))
+	assert.Nil(t, closer)
+	assert.Error(t, err)
+}
+
+func TestConsoleLevelWithoutLogFile(t *testing.T) {
+	logger, _ := test.NewLogger()
+
+	logging.SetConsoleLevel(logger, logrus.WarnLevel)
+
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+	assert.Equal(t, logrus.WarnLevel, logging.ConsoleLevel(logger))
+}
@@ -0,0 +1,166 @@
+package test
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSServer is a DNS server over UDP answering the A and AAAA queries using static records.
+type DNSServer struct {
+	conn    net.PacketConn
+	records dnsRecords
+	mu      sync.Mutex
+	queries []string
+}
+
+// NewDNSServer starts a DNS server resolving the given host names to the given IPs, a host name starting with
+// "*." is a wildcard record.
+func NewDNSServer(t TestingT, records map[string][]string) *DNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	s := &DNSServer{conn: conn, records: newDNSRecords(t, records)}
+
+	go s.serve()
+
+	return s
+}
+
+// Addr returns the address of the server, eg: 127.0.0.1:5353.
+func (s *DNSServer) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// Close stops the server.
+func (s *DNSServer) Close() {
+	_ = s.conn.Close()
+}
+
+// Queries returns the queries received, eg: "A example.com.".
+func (s *DNSServer) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.queries...)
+}
+
+func (s *DNSServer) serve() {
+	buf := make([]byte, 512)
+
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		var parser dnsmessage.Parser
+
+		header, err := parser.Start(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		question, err := parser.Question()
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		s.queries = append(s.queries, strings.TrimPrefix(question.Type.String(), "Type")+" "+question.Name.String())
+		s.mu.Unlock()
+
+		answer, err := s.records.answer(header, question)
+		if err != nil {
+			continue
+		}
+
+		_, _ = s.conn.WriteTo(answer, addr)
+	}
+}
+
+// dnsRecords maps the fully qualified host names to their IPs.
+type dnsRecords map[string][]net.IP
+
+func newDNSRecords(t TestingT, records map[string][]string) dnsRecords {
+	r := make(dnsRecords, len(records))
+
+	for host, ips := range records {
+		for _, ip := range ips {
+			parsed := net.ParseIP(ip)
+			if parsed == nil {
+				t.Fatalf("invalid ip %s for %s", ip, host)
+			}
+
+			r[strings.ToLower(host)+"."] = append(r[strings.ToLower(host)+"."], parsed)
+		}
+	}
+
+	return r
+}
+
+func (r dnsRecords) lookup(name string) ([]net.IP, bool) {
+	name = strings.ToLower(name)
+
+	if ips, found := r[name]; found {
+		return ips, true
+	}
+
+	if i := strings.Index(name, "."); i >= 0 {
+		ips, found := r["*"+name[i:]]
+
+		return ips, found
+	}
+
+	return nil, false
+}
+
+func (r dnsRecords) answer(queryHeader dnsmessage.Header, question dnsmessage.Question) ([]byte, error) {
+	ips, found := r.lookup(question.Name.String())
+
+	header := dnsmessage.Header{ID: queryHeader.ID, Response: true, RecursionAvailable: true}
+	if !found {
+		header.RCode = dnsmessage.RCodeNameError
+	}
+
+	builder := dnsmessage.NewBuilder(nil, header)
+
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+
+	resourceHeader := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+
+	for _, ip := range ips {
+		switch {
+		case question.Type == dnsmessage.TypeA && ip.To4() != nil:
+			resource := dnsmessage.AResource{}
+			copy(resource.A[:], ip.To4())
+
+			if err := builder.AResource(resourceHeader, resource); err != nil {
+				return nil, err
+			}
+		case question.Type == dnsmessage.TypeAAAA && ip.To4() == nil:
+			resource := dnsmessage.AAAAResource{}
+			copy(resource.AAAA[:], ip.To16())
+
+			if err := builder.AAAAResource(resourceHeader, resource); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return builder.Finish()
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DoHServer is a DNS over HTTPS endpoint (RFC 8484) answering the A and AAAA queries using static records.
+type DoHServer struct {
+	*httptest.Server
+	records dnsRecords
+	mu      sync.Mutex
+	queries []string
+}
+
+// NewDoHServer starts a DNS over HTTPS endpoint resolving the given host names to the given IPs,
+// the endpoint is available at the /dns-query path.
+func NewDoHServer(t TestingT, records map[string][]string) *DoHServer {
+	s := &DoHServer{records: newDNSRecords(t, records)}
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// URL returns the URL of the endpoint.
+func (s *DoHServer) URL() string {
+	return s.Server.URL + "/dns-query"
+}
+
+// Queries returns the queries received, eg: "A example.com.".
+func (s *DoHServer) Queries() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.queries...)
+}
+
+func (s *DoHServer) handle(w http.ResponseWriter, r *http.Request) {
+	rawQuery, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+	if err != nil || r.URL.Path != "/dns-query" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var parser dnsmessage.Parser
+
+	header, err := parser.Start(rawQuery)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	question, err := parser.Question()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	s.queries = append(s.queries, strings.TrimPrefix(question.Type.String(), "Type")+" "+question.Name.String())
+	s.mu.Unlock()
+
+	answer, err := s.records.answer(header, question)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/dns-message")
+	_, _ = w.Write(answer)
+}
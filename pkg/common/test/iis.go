@@ -0,0 +1,38 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+)
+
+// NewIISShortNameServerWithAssertion starts a server disclosing the given 8.3 short names (eg: ADMINI~1.ASP) the
+// way IIS does: the requests whose last but one path segment is a wildcard pattern (eg: /ADM*~1*/.aspx) get a 404
+// when a short name matches it and a 400 otherwise. The other requests are served by the handler.
+func NewIISShortNameServerWithAssertion(
+	shortNames []string,
+	handler http.HandlerFunc,
+) (*httptest.Server, *ServerAssertion) {
+	return NewServerWithAssertion(func(w http.ResponseWriter, r *http.Request) {
+		segments := strings.Split(r.URL.Path, "/")
+		if len(segments) < 3 || segments[len(segments)-1] != ".aspx" || !strings.Contains(r.URL.Path, "~") {
+			handler(w, r)
+
+			return
+		}
+
+		pattern := strings.ToUpper(segments[len(segments)-2])
+		pattern = "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+
+		for _, shortName := range shortNames {
+			if regexp.MustCompile(pattern).MatchString(shortName) {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+)
+
+type ntlmConnectionKey struct{}
+
+// ntlmConnection is the authentication state of a connection.
+type ntlmConnection struct {
+	challenged    bool
+	authenticated bool
+}
+
+// NewNTLMServerWithAssertion starts a server requiring the NTLM handshake to be performed using the given
+// scheme (NTLM or Negotiate) before serving the requests. As it happens with IIS, the connection is
+// authenticated: the handshake has to happen on a single connection and is not repeated on it afterwards.
+// The credentials are not verified.
+func NewNTLMServerWithAssertion(scheme string, handler http.HandlerFunc) (*httptest.Server, *ServerAssertion) {
+	serverAssertion := &ServerAssertion{}
+
+	server := httptest.NewUnstartedServer(serverAssertion.wrap(func(w http.ResponseWriter, r *http.Request) {
+		connection, _ := r.Context().Value(ntlmConnectionKey{}).(*ntlmConnection)
+
+		if connection.authenticated {
+			handler(w, r)
+
+			return
+		}
+
+		switch ntlmMessageType(r.Header.Get("Authorization")) {
+		case 1:
+			connection.challenged = true
+
+			w.Header().Set("WWW-Authenticate", scheme+" "+base64.StdEncoding.EncodeToString(ntlmChallenge()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			if !connection.challenged {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			connection.authenticated = true
+
+			handler(w, r)
+		default:
+			w.Header().Set("WWW-Authenticate", scheme)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+
+	server.Config.ConnContext = func(ctx context.Context, _ net.Conn) context.Context {
+		return context.WithValue(ctx, ntlmConnectionKey{}, &ntlmConnection{})
+	}
+
+	server.Start()
+
+	return server, serverAssertion
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/armon/go-socks5"
+)
+
+// NewHTTPConnectProxyWithAssertion starts an HTTP proxy supporting the CONNECT method.
+func NewHTTPConnectProxyWithAssertion() (*httptest.Server, *ServerAssertion) {
+	return NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodConnect {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			tunnel(w, r)
+		}),
+	)
+}
+
+// NewHTTPForwardProxyWithAssertion starts an HTTP proxy forwarding the requests in absolute form
+// and tunneling the ones using the CONNECT method.
+func NewHTTPForwardProxyWithAssertion() (*httptest.Server, *ServerAssertion) {
+	return NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodConnect {
+				tunnel(w, r)
+
+				return
+			}
+
+			if !r.URL.IsAbs() {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			outgoing := r.Clone(r.Context())
+			outgoing.RequestURI = ""
+			outgoing.Header.Del("Proxy-Authorization")
+
+			res, err := http.DefaultTransport.RoundTrip(outgoing)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+
+				return
+			}
+			defer res.Body.Close() //nolint:errcheck
+
+			for name, values := range res.Header {
+				w.Header()[name] = values
+			}
+
+			w.WriteHeader(res.StatusCode)
+
+			_, _ = io.Copy(w, res.Body)
+		}),
+	)
+}
+
+// NewBasicAuthHTTPConnectProxyWithAssertion starts an HTTP proxy supporting the CONNECT method which
+// requires the given credentials using basic authentication.
+func NewBasicAuthHTTPConnectProxyWithAssertion(username, password string) (*httptest.Server, *ServerAssertion) {
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+	return NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Proxy-Authorization") != expected {
+				w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+				w.WriteHeader(http.StatusProxyAuthRequired)
+
+				return
+			}
+
+			tunnel(w, r)
+		}),
+	)
+}
+
+// NewNTLMHTTPConnectProxyWithAssertion starts an HTTP proxy supporting the CONNECT method which requires
+// NTLM authentication. The handshake is enforced but the credentials are not verified.
+func NewNTLMHTTPConnectProxyWithAssertion() (*httptest.Server, *ServerAssertion) {
+	return NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch ntlmMessageType(r.Header.Get("Proxy-Authorization")) {
+			case 1:
+				w.Header().Set("Proxy-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(ntlmChallenge()))
+				w.WriteHeader(http.StatusProxyAuthRequired)
+			case 3:
+				tunnel(w, r)
+			default:
+				w.Header().Set("Proxy-Authenticate", "NTLM")
+				w.WriteHeader(http.StatusProxyAuthRequired)
+			}
+		}),
+	)
+}
+
+func ntlmMessageType(authorization string) byte {
+	_, data, _ := strings.Cut(authorization, " ")
+
+	message, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(message) < 12 || !bytes.HasPrefix(message, []byte("NTLMSSP\x00")) {
+		return 0
+	}
+
+	return message[8]
+}
+
+// ntlmChallenge returns a minimal NTLM CHALLENGE message without target name nor target info.
+func ntlmChallenge() []byte {
+	challenge := make([]byte, 48)
+
+	copy(challenge, "NTLMSSP\x00")
+	challenge[8] = 2                               // message type
+	challenge[16] = 48                             // target name offset
+	copy(challenge[20:], []byte{0x01, 0x02, 0, 0}) // negotiate flags: unicode, ntlm
+	copy(challenge[24:], "chlngchl")               // server challenge
+	challenge[44] = 48                             // target info offset
+
+	return challenge
+}
+
+func tunnel(w http.ResponseWriter, r *http.Request) {
+	target, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = target.Close()
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		_ = target.Close()
+
+		return
+	}
+
+	_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+	go func() {
+		defer target.Close() //nolint:errcheck
+
+		_, _ = io.Copy(target, buf)
+	}()
+
+	go func() {
+		defer conn.Close() //nolint:errcheck
+
+		_, _ = io.Copy(conn, target)
+	}()
+}
+
+// NewSocks5Server starts a SOCKS5 server on a random local port.
+func NewSocks5Server(t TestingT) net.Listener {
+	return newSocks5Server(t, &socks5.Config{})
+}
+
+// NewAuthenticatedSocks5Server starts a SOCKS5 server on a random local port which requires the given
+// credentials using username/password authentication.
+func NewAuthenticatedSocks5Server(t TestingT, username, password string) net.Listener {
+	credentials := socks5.StaticCredentials{username: password}
+
+	return newSocks5Server(
+		t,
+		&socks5.Config{AuthMethods: []socks5.Authenticator{socks5.UserPassAuthenticator{Credentials: credentials}}},
+	)
+}
+
+func newSocks5Server(t TestingT, config *socks5.Config) net.Listener {
+	server, err := socks5.New(config)
+	if err != nil {
+		t.Fatalf("failed to create socks5: %s", err.Error())
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create listener: %s", err.Error())
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return listener
+}
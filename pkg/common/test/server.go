@@ -1,6 +1,7 @@
 package test
 
 import (
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -23,6 +24,17 @@ func NewTSLServerWithAssertion(handler http.HandlerFunc) (*httptest.Server, *Ser
 	return server, serverAssertion
 }
 
+// NewMTLSServerWithAssertion starts a TLS server that refuses clients not presenting a certificate.
+func NewMTLSServerWithAssertion(handler http.HandlerFunc) (*httptest.Server, *ServerAssertion) {
+	serverAssertion := &ServerAssertion{}
+
+	server := httptest.NewUnstartedServer(serverAssertion.wrap(handler))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert} //nolint:gosec
+	server.StartTLS()
+
+	return server, serverAssertion
+}
+
 type ServerAssertion struct {
 	requests   []http.Request
 	requestsMx sync.RWMutex
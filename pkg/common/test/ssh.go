@@ -0,0 +1,149 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SSHServer is an SSH server on a random local port that only accepts port forwarding requests.
+type SSHServer struct {
+	listener  net.Listener
+	hostKey   ssh.PublicKey
+	forwarded int64
+}
+
+// NewSSHServer starts an SSH server accepting the given user authenticated with the given key.
+func NewSSHServer(t TestingT, user string, authorizedKey ssh.PublicKey) *SSHServer {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate host key: %s", err.Error())
+	}
+
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		t.Fatalf("failed to create host signer: %s", err.Error())
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() != user || string(key.Marshal()) != string(authorizedKey.Marshal()) {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create listener: %s", err.Error())
+	}
+
+	s := &SSHServer{listener: listener, hostKey: hostSigner.PublicKey()}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn, config)
+		}
+	}()
+
+	return s
+}
+
+// Addr returns the address the server is listening on.
+func (s *SSHServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// KnownHostsLine returns the known_hosts entry of the server.
+func (s *SSHServer) KnownHostsLine() string {
+	host, port, _ := net.SplitHostPort(s.Addr())
+
+	return "[" + host + "]:" + port + " " + string(ssh.MarshalAuthorizedKey(s.hostKey))
+}
+
+// Forwarded returns the number of connections forwarded by the server.
+func (s *SSHServer) Forwarded() int {
+	return int(atomic.LoadInt64(&s.forwarded))
+}
+
+func (s *SSHServer) Close() error {
+	return s.listener.Close()
+}
+
+func (s *SSHServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer serverConn.Close() //nolint:errcheck
+
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only port forwarding is supported")
+
+			continue
+		}
+
+		go s.forward(newChannel)
+	}
+}
+
+func (s *SSHServer) forward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, "invalid payload")
+
+		return
+	}
+
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+
+		return
+	}
+
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		_ = target.Close()
+
+		return
+	}
+
+	atomic.AddInt64(&s.forwarded, 1)
+
+	go ssh.DiscardRequests(requests)
+
+	go func() {
+		defer target.Close() //nolint:errcheck
+
+		_, _ = io.Copy(target, channel)
+	}()
+
+	go func() {
+		defer channel.Close() //nolint:errcheck
+
+		_, _ = io.Copy(channel, target)
+	}()
+}
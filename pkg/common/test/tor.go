@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+)
+
+// TorControlServer mimics the control port of a Tor instance protected by a password.
+type TorControlServer struct {
+	listener net.Listener
+	password string
+	mu       sync.Mutex
+	signals  []string
+}
+
+// NewTorControlServer starts a fake Tor control port on a random local port.
+func NewTorControlServer(t TestingT, password string) *TorControlServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create listener: %s", err.Error())
+	}
+
+	s := &TorControlServer{listener: listener, password: password}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// Addr returns the address the server is listening on.
+func (s *TorControlServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Signals returns the signals received by authenticated clients.
+func (s *TorControlServer) Signals() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.signals...)
+}
+
+func (s *TorControlServer) Close() error {
+	return s.listener.Close()
+}
+
+func (s *TorControlServer) serve(conn net.Conn) {
+	defer conn.Close() //nolint:errcheck
+
+	reader := bufio.NewReader(conn)
+	authenticated := false
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimSpace(line)
+
+		switch {
+		case command == `AUTHENTICATE "`+s.password+`"`:
+			authenticated = true
+
+			_, _ = conn.Write([]byte("250 OK\r\n"))
+		case strings.HasPrefix(command, "AUTHENTICATE"):
+			_, _ = conn.Write([]byte("515 Authentication failed: Password did not match HashedControlPassword value\r\n"))
+
+			return
+		case !authenticated:
+			_, _ = conn.Write([]byte("514 Authentication required.\r\n"))
+
+			return
+		case strings.HasPrefix(command, "SIGNAL "):
+			s.mu.Lock()
+			s.signals = append(s.signals, strings.TrimPrefix(command, "SIGNAL "))
+			s.mu.Unlock()
+
+			_, _ = conn.Write([]byte("250 OK\r\n"))
+		case command == "QUIT":
+			_, _ = conn.Write([]byte("250 closing connection\r\n"))
+
+			return
+		default:
+			_, _ = conn.Write([]byte("510 Unrecognized command\r\n"))
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"net"
 	"net/url"
 )
 
@@ -16,3 +17,21 @@ func MustParseURL(t TestingT, rawurl string) *url.URL {
 
 	return u
 }
+
+// LoopbackInterface returns the name of the loopback network interface, which differs across platforms.
+func LoopbackInterface(t TestingT) string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("failed to list the network interfaces: %s", err)
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			return iface.Name
+		}
+	}
+
+	t.Fatalf("no loopback network interface found")
+
+	return ""
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Config represents the configuration of the monitor mode.
+type Config struct {
+	// Interval is the time to wait between two runs.
+	Interval time.Duration `yaml:"interval"`
+	// StateDir is where the results of the last run of each target are stored.
+	StateDir string `yaml:"state-dir"`
+	// Webhook is the URL the alerts are posted to, when empty the alerts are only logged.
+	Webhook string `yaml:"webhook"`
+	// Scan contains the scan settings shared by every target.
+	Scan ScanConfig `yaml:"scan"`
+	// Targets are the URLs to monitor.
+	Targets []Target `yaml:"targets"`
+}
+
+// ScanConfig represents the settings used to scan a target.
+type ScanConfig struct {
+	Dictionary           string            `yaml:"dictionary"`
+	HTTPMethods          []string          `yaml:"http-methods"`
+	HTTPStatusesToIgnore []int             `yaml:"http-statuses-to-ignore"`
+	Threads              int               `yaml:"threads"`
+	ScanDepth            int               `yaml:"scan-depth"`
+	HTTPTimeout          int               `yaml:"http-timeout"`
+	UserAgent            string            `yaml:"user-agent"`
+	Headers              map[string]string `yaml:"headers"`
+	NoCheckCertificate   bool              `yaml:"no-check-certificate"`
+	Rules                string            `yaml:"rules"`
+	Classify             string            `yaml:"classify"`
+}
+
+// Target represents a URL to monitor, its name identifies its state and its alerts.
+type Target struct {
+	Name       string `yaml:"name"`
+	URL        string `yaml:"url"`
+	Dictionary string `yaml:"dictionary"`
+}
+
+// LoadConfig reads the monitor configuration from a yaml file.
+func LoadConfig(path string) (Config, error) {
+	raw, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to read monitor configuration `%s`", path)
+	}
+
+	c := Config{
+		Interval: time.Hour,
+		StateDir: "dirstalk-monitor",
+		Scan: ScanConfig{
+			HTTPMethods:          []string{"GET"},
+			HTTPStatusesToIgnore: []int{404},
+			Threads:              3,
+			ScanDepth:            3,
+			HTTPTimeout:          5000,
+		},
+	}
+
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return Config{}, errors.Wrapf(err, "failed to parse monitor configuration `%s`", path)
+	}
+
+	return c, c.validate()
+}
+
+func (c *Config) validate() error {
+	if c.Interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	if len(c.Targets) == 0 {
+		return errors.New("at least one target is required")
+	}
+
+	names := make(map[string]struct{}, len(c.Targets))
+
+	for i := range c.Targets {
+		t := &c.Targets[i]
+
+		u, err := url.ParseRequestURI(t.URL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid url for target %d", i+1)
+		}
+
+		if t.Name == "" {
+			t.Name = strings.NewReplacer(":", "_", "/", "_").Replace(u.Host)
+		}
+
+		if _, found := names[t.Name]; found {
+			return errors.Errorf("target name `%s` is used more than once, targets sharing a host need a name", t.Name)
+		}
+
+		names[t.Name] = struct{}{}
+
+		if t.Dictionary == "" {
+			t.Dictionary = c.Scan.Dictionary
+		}
+
+		if t.Dictionary == "" {
+			return errors.Errorf("no dictionary specified for target `%s`", t.Name)
+		}
+	}
+
+	return nil
+}
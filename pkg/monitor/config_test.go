@@ -0,0 +1,96 @@
+package monitor_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/monitor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	c, err := monitor.LoadConfig("testdata/monitor.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 30*time.Minute, c.Interval)
+	assert.Equal(t, "/tmp/dirstalk-monitor", c.StateDir)
+	assert.Equal(t, "https://hooks.example.com/dirstalk", c.Webhook)
+
+	assert.Equal(t, 5, c.Scan.Threads)
+	assert.Equal(t, []int{404, 403}, c.Scan.HTTPStatusesToIgnore)
+	assert.Equal(t, []string{"GET"}, c.Scan.HTTPMethods, "defaults should be kept when not overridden")
+	assert.Equal(t, 3, c.Scan.ScanDepth)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer abc"}, c.Scan.Headers)
+
+	assert.Equal(
+		t,
+		[]monitor.Target{
+			{Name: "app.example.com_8080", URL: "http://app.example.com:8080/", Dictionary: "dict.txt"},
+			{Name: "admin", URL: "https://app.example.com/admin/", Dictionary: "admin.txt"},
+		},
+		c.Targets,
+	)
+}
+
+func TestLoadConfigShouldErrForInvalidConfigurations(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        string
+		expectedError string
+	}{
+		{
+			name:          "no targets",
+			config:        "scan:\n  dictionary: dict.txt\n",
+			expectedError: "at least one target is required",
+		},
+		{
+			name:          "invalid url",
+			config:        "targets:\n  - url: not-a-url\n    dictionary: dict.txt\n",
+			expectedError: "invalid url for target 1",
+		},
+		{
+			name:          "no dictionary",
+			config:        "targets:\n  - url: http://localhost/\n",
+			expectedError: "no dictionary specified for target `localhost`",
+		},
+		{
+			name: "duplicated name",
+			config: "scan:\n  dictionary: dict.txt\n" +
+				"targets:\n  - url: http://localhost/a/\n  - url: http://localhost/b/\n",
+			expectedError: "target name `localhost` is used more than once",
+		},
+		{
+			name:          "invalid interval",
+			config:        "interval: -1m\ntargets:\n  - url: http://localhost/\n    dictionary: dict.txt\n",
+			expectedError: "interval must be greater than 0",
+		},
+		{
+			name:          "invalid yaml",
+			config:        "targets: [",
+			expectedError: "failed to parse monitor configuration",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "monitor.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tc.config), 0o600))
+
+			_, err := monitor.LoadConfig(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestLoadConfigShouldErrForMissingFile(t *testing.T) {
+	_, err := monitor.LoadConfig("testdata/missing.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read monitor configuration")
+}
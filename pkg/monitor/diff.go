@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+const (
+	ChangeNew     = "new"
+	ChangeChanged = "changed"
+)
+
+// Change represents a path that appeared or changed since the previous run.
+type Change struct {
+	Kind     string
+	Result   scan.Result
+	Previous *scan.Result `json:",omitempty"`
+}
+
+// Diff returns the paths of current that are not in previous or that reply differently.
+func Diff(previous, current []scan.Result) []Change {
+	known := make(map[string]scan.Result, len(previous))
+	for _, r := range previous {
+		known[resultKey(r)] = r
+	}
+
+	changes := make([]Change, 0)
+
+	for _, r := range current {
+		p, found := known[resultKey(r)]
+		if !found {
+			changes = append(changes, Change{Kind: ChangeNew, Result: r})
+
+			continue
+		}
+
+		if p.StatusCode != r.StatusCode || p.ContentLength != r.ContentLength || p.ContentType != r.ContentType ||
+			!sameLabels(p.Labels, r.Labels) {
+			p := p
+			changes = append(changes, Change{Kind: ChangeChanged, Result: r, Previous: &p})
+		}
+	}
+
+	return changes
+}
+
+func resultKey(r scan.Result) string {
+	return r.Target.Method + " " + r.URL.String()
+}
+
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
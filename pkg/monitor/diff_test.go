@@ -0,0 +1,44 @@
+package monitor_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/monitor"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffShouldReportNewAndChangedPaths(t *testing.T) {
+	result := func(path string, status int) scan.Result {
+		return scan.Result{
+			Target:     scan.Target{Path: path, Method: http.MethodGet},
+			StatusCode: status,
+			URL:        *test.MustParseURL(t, "http://localhost"+path),
+		}
+	}
+
+	previous := []scan.Result{result("/home", 200), result("/admin", 403), result("/removed", 200)}
+	current := []scan.Result{result("/home", 200), result("/admin", 200), result("/backup.zip", 200)}
+
+	changes := monitor.Diff(previous, current)
+
+	assert.Len(t, changes, 2)
+
+	assert.Equal(t, monitor.ChangeChanged, changes[0].Kind)
+	assert.Equal(t, "/admin", changes[0].Result.Target.Path)
+	assert.Equal(t, 403, changes[0].Previous.StatusCode)
+
+	assert.Equal(t, monitor.ChangeNew, changes[1].Kind)
+	assert.Equal(t, "/backup.zip", changes[1].Result.Target.Path)
+	assert.Nil(t, changes[1].Previous)
+}
+
+func TestDiffShouldReportNothingForIdenticalRuns(t *testing.T) {
+	results := []scan.Result{
+		{Target: scan.Target{Method: http.MethodGet}, StatusCode: 200, URL: *test.MustParseURL(t, "http://localhost/")},
+	}
+
+	assert.Empty(t, monitor.Diff(results, results))
+}
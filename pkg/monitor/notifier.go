@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// Alert is the payload posted to the webhook, Text makes it readable by chat webhooks (Slack, Mattermost, ...).
+type Alert struct {
+	Text    string   `json:"text"`
+	Target  string   `json:"target"`
+	URL     string   `json:"url"`
+	Changes []Change `json:"changes"`
+}
+
+// NewAlert builds the alert describing the changes of a target.
+func NewAlert(target Target, changes []Change) Alert {
+	lines := []string{fmt.Sprintf("dirstalk: %d new or changed paths on %s", len(changes), target.URL)}
+
+	for _, c := range changes {
+		line := fmt.Sprintf("[%s] %s %s %d", c.Kind, c.Result.Target.Method, c.Result.URL.String(), c.Result.StatusCode)
+		if c.Previous != nil {
+			line += fmt.Sprintf(" (was %d)", c.Previous.StatusCode)
+		}
+
+		if len(c.Result.Labels) > 0 {
+			line += fmt.Sprintf(" [%s]", strings.Join(c.Result.Labels, ", "))
+		}
+
+		if c.Result.Severity != "" {
+			line += fmt.Sprintf(" [severity: %s]", c.Result.Severity)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return Alert{Text: strings.Join(lines, "\n"), Target: target.Name, URL: target.URL, Changes: changes}
+}
+
+// NewWebhookNotifier creates a notifier posting the alerts as json to the given URL.
+func NewWebhookNotifier(doer scan.Doer, webhookURL string) WebhookNotifier {
+	return WebhookNotifier{doer: doer, webhookURL: webhookURL}
+}
+
+type WebhookNotifier struct {
+	doer       scan.Doer
+	webhookURL string
+}
+
+func (n WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode alert")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to build webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send alert")
+	}
+
+	defer res.Body.Close() //nolint
+
+	if res.StatusCode/100 != 2 {
+		return errors.Errorf("webhook replied with status %d", res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package monitor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/monitor"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifierShouldPostTheAlert(t *testing.T) {
+	var payload map[string]interface{}
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		}),
+	)
+	defer testServer.Close()
+
+	alert := monitor.NewAlert(
+		monitor.Target{Name: "app", URL: "http://app.local/"},
+		[]monitor.Change{
+			{
+				Kind: monitor.ChangeNew,
+				Result: scan.Result{
+					Target:     scan.Target{Method: http.MethodGet},
+					StatusCode: http.StatusOK,
+					URL:        *test.MustParseURL(t, "http://app.local/backup.zip"),
+				},
+			},
+		},
+	)
+
+	err := monitor.NewWebhookNotifier(http.DefaultClient, testServer.URL).Notify(context.Background(), alert)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	assert.Equal(
+		t,
+		"dirstalk: 1 new or changed paths on http://app.local/\n[new] GET http://app.local/backup.zip 200",
+		payload["text"],
+	)
+	assert.Equal(t, "app", payload["target"])
+	assert.Len(t, payload["changes"], 1)
+}
+
+func TestWebhookNotifierShouldErrForUnsuccessfulStatus(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer testServer.Close()
+
+	err := monitor.NewWebhookNotifier(http.DefaultClient, testServer.URL).
+		Notify(context.Background(), monitor.Alert{Text: "test"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook replied with status 400")
+}
@@ -0,0 +1,258 @@
+package result
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Statement describes the scan whose results are signed.
+type Statement struct {
+	ResultsSHA256 string
+	ConfigSHA256  string
+	URL           string
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	SignedAt      time.Time
+}
+
+// Signature is a detached signature of a result file. The payload holds the exact bytes signed, the JSON
+// encoding of the Statement, so that the signature can still be verified once the Statement evolves.
+type Signature struct {
+	Payload   []byte
+	Algorithm string
+	Signature []byte
+}
+
+// SignaturePath returns the path of the file storing the signature of the results saved at the given path.
+func SignaturePath(resultsPath string) string {
+	return resultsPath + ".sig"
+}
+
+// LoadSigner reads a PEM encoded RSA, ECDSA or Ed25519 private key.
+func LoadSigner(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse private key `%s`", path)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+
+	if _, err := algorithmOf(signer.Public()); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// LoadPublicKey reads a PEM encoded public key, or the public part of a private key.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type != "PUBLIC KEY" {
+		signer, err := LoadSigner(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return signer.Public(), nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse public key `%s`", path)
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	raw, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read key `%s`", path)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data found in `%s`", path)
+	}
+
+	return block, nil
+}
+
+// FileSHA256 returns the hex encoded sha256 of the content of a file.
+func FileSHA256(path string) (string, error) {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open %s", path)
+	}
+
+	defer file.Close() //nolint
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Sign produces the signature of the given statement.
+func Sign(signer crypto.Signer, statement Statement) (Signature, error) {
+	algorithm, err := algorithmOf(signer.Public())
+	if err != nil {
+		return Signature{}, err
+	}
+
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return Signature{}, errors.Wrap(err, "failed to encode statement")
+	}
+
+	message, opts := signedMessage(algorithm, payload)
+
+	signature, err := signer.Sign(rand.Reader, message, opts)
+	if err != nil {
+		return Signature{}, errors.Wrap(err, "failed to sign results")
+	}
+
+	return Signature{Payload: payload, Algorithm: algorithm, Signature: signature}, nil
+}
+
+// SaveSignature writes the signature to the given path.
+func SaveSignature(path string, signature Signature) error {
+	raw, err := json.MarshalIndent(signature, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode signature")
+	}
+
+	return errors.Wrapf(ioutil.WriteFile(path, raw, 0o600), "failed to write signature to `%s`", path)
+}
+
+// LoadSignature reads a signature produced by SaveSignature.
+func LoadSignature(path string) (Signature, error) {
+	raw, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return Signature{}, errors.Wrapf(err, "failed to read signature `%s`", path)
+	}
+
+	signature := Signature{}
+	if err := json.Unmarshal(raw, &signature); err != nil {
+		return Signature{}, errors.Wrapf(err, "failed to parse signature `%s`", path)
+	}
+
+	return signature, nil
+}
+
+// Verify checks that the signature was produced by the owner of the public key and that the result file
+// was not modified since, returning the statement signed.
+func Verify(publicKey crypto.PublicKey, signature Signature, resultsPath string) (Statement, error) {
+	algorithm, err := algorithmOf(publicKey)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if algorithm != signature.Algorithm {
+		return Statement{}, errors.Errorf("the signature uses %s while the key is %s", signature.Algorithm, algorithm)
+	}
+
+	message, _ := signedMessage(algorithm, signature.Payload)
+
+	if !verifySignature(publicKey, message, signature.Signature) {
+		return Statement{}, errors.New("invalid signature")
+	}
+
+	// the statement is only decoded once its bytes are known to be authentic
+	statement := Statement{}
+	if err := json.Unmarshal(signature.Payload, &statement); err != nil {
+		return Statement{}, errors.Wrap(err, "failed to decode statement")
+	}
+
+	resultsSHA256, err := FileSHA256(resultsPath)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if resultsSHA256 != statement.ResultsSHA256 {
+		return Statement{}, errors.Errorf("`%s` was modified after being signed", resultsPath)
+	}
+
+	return statement, nil
+}
+
+const (
+	algorithmRSA     = "rsa-pkcs1v15-sha256"
+	algorithmECDSA   = "ecdsa-sha256"
+	algorithmEd25519 = "ed25519"
+)
+
+func algorithmOf(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return algorithmRSA, nil
+	case *ecdsa.PublicKey:
+		return algorithmECDSA, nil
+	case ed25519.PublicKey:
+		return algorithmEd25519, nil
+	default:
+		return "", errors.Errorf("unsupported key type %T, only RSA, ECDSA and Ed25519 keys are supported", publicKey)
+	}
+}
+
+// signedMessage returns what is actually signed: Ed25519 signs the payload, the other algorithms its digest.
+func signedMessage(algorithm string, payload []byte) ([]byte, crypto.SignerOpts) {
+	if algorithm == algorithmEd25519 {
+		return payload, crypto.Hash(0)
+	}
+
+	digest := sha256.Sum256(payload)
+
+	return digest[:], crypto.SHA256
+}
+
+func verifySignature(publicKey crypto.PublicKey, message, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, message, signature) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, message, signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,177 @@
+package result_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerifyResults(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		key       crypto.Signer
+		algorithm string
+	}{
+		{name: "rsa", key: rsaKey, algorithm: "rsa-pkcs1v15-sha256"},
+		{name: "ecdsa", key: ecdsaKey, algorithm: "ecdsa-sha256"},
+		{name: "ed25519", key: ed25519Key, algorithm: "ed25519"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			privateKeyPath := writePrivateKey(t, dir, tc.key)
+			publicKeyPath := writePublicKey(t, dir, tc.key.Public())
+
+			resultsPath := filepath.Join(dir, "results.json")
+			assert.NoError(t, ioutil.WriteFile(resultsPath, []byte(`{"StatusCode":200}`), 0o600))
+
+			resultsSHA256, err := result.FileSHA256(resultsPath)
+			assert.NoError(t, err)
+
+			signer, err := result.LoadSigner(privateKeyPath)
+			assert.NoError(t, err)
+
+			signature, err := result.Sign(signer, result.Statement{
+				ResultsSHA256: resultsSHA256,
+				URL:           "http://localhost/",
+				StartedAt:     time.Now(),
+				FinishedAt:    time.Now(),
+				SignedAt:      time.Now(),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.algorithm, signature.Algorithm)
+
+			assert.NoError(t, result.SaveSignature(result.SignaturePath(resultsPath), signature))
+
+			loaded, err := result.LoadSignature(result.SignaturePath(resultsPath))
+			assert.NoError(t, err)
+
+			publicKey, err := result.LoadPublicKey(publicKeyPath)
+			assert.NoError(t, err)
+
+			statement, err := result.Verify(publicKey, loaded, resultsPath)
+			assert.NoError(t, err)
+			assert.Equal(t, "http://localhost/", statement.URL)
+			assert.Equal(t, resultsSHA256, statement.ResultsSHA256)
+
+			// the public part of the private key can be used as well
+			publicKeyFromPrivate, err := result.LoadPublicKey(privateKeyPath)
+			assert.NoError(t, err)
+			_, err = result.Verify(publicKeyFromPrivate, loaded, resultsPath)
+			assert.NoError(t, err)
+
+			tampered := loaded
+			tampered.Payload = bytes.Replace(loaded.Payload, []byte("http://localhost/"), []byte("http://evil/"), 1)
+			_, err = result.Verify(publicKey, tampered, resultsPath)
+			assert.EqualError(t, err, "invalid signature")
+
+			assert.NoError(t, ioutil.WriteFile(resultsPath, []byte(`{"StatusCode":404}`), 0o600))
+
+			_, err = result.Verify(publicKey, loaded, resultsPath)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "was modified after being signed")
+		})
+	}
+}
+
+func TestVerifyShouldErrForADifferentKey(t *testing.T) {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	signature, err := result.Sign(signingKey, result.Statement{URL: "http://localhost/"})
+	assert.NoError(t, err)
+
+	_, err = result.Verify(otherKey.Public(), signature, "testdata/out.txt")
+	assert.EqualError(t, err, "invalid signature")
+
+	_, err = result.Verify(ed25519Key.Public(), signature, "testdata/out.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the signature uses ecdsa-sha256 while the key is ed25519")
+}
+
+func TestVerifyShouldAcceptTheStatementsHavingUnknownFields(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	resultsSHA256, err := result.FileSHA256("testdata/out.txt")
+	assert.NoError(t, err)
+
+	// eg: a statement signed by a later version, the signed bytes are verified as they are
+	payload := []byte(`{"ResultsSHA256":"` + resultsSHA256 + `","URL":"http://localhost/","Scanner":"dirstalk"}`)
+
+	statement, err := result.Verify(
+		publicKey,
+		result.Signature{Payload: payload, Algorithm: "ed25519", Signature: ed25519.Sign(privateKey, payload)},
+		"testdata/out.txt",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/", statement.URL)
+}
+
+func TestLoadSignerShouldErrForInvalidKeys(t *testing.T) {
+	_, err := result.LoadSigner("testdata/missing.pem")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read key")
+
+	_, err = result.LoadSigner("testdata/out.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM data found")
+}
+
+func writePrivateKey(t *testing.T, dir string, key crypto.Signer) string {
+	t.Helper()
+
+	raw, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "key.pem")
+	assert.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw}), 0o600))
+
+	return path
+}
+
+func writePublicKey(t *testing.T, dir string, key crypto.PublicKey) string {
+	t.Helper()
+
+	raw, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "key.pub")
+	assert.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: raw}), 0o600))
+
+	return path
+}
@@ -0,0 +1,88 @@
+package result
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// maxWatchedBodySize is the amount of bytes of a response body considered when looking for changes.
+const maxWatchedBodySize = 10 << 20
+
+// Snapshot represents the content served by a finding at a given time.
+type Snapshot struct {
+	StatusCode    int
+	ContentLength int64
+	Hash          string
+	Err           string
+}
+
+// Change represents a finding whose content differs from the previous check.
+type Change struct {
+	Result    scan.Result
+	Previous  Snapshot
+	Current   Snapshot
+	CheckedAt time.Time
+}
+
+// NewWatcher creates a Watcher re-fetching the given results.
+func NewWatcher(doer scan.Doer, results []scan.Result) *Watcher {
+	return &Watcher{doer: doer, results: results, snapshots: make(map[string]Snapshot, len(results))}
+}
+
+// Watcher tracks the content served by known findings over time.
+type Watcher struct {
+	doer      scan.Doer
+	results   []scan.Result
+	snapshots map[string]Snapshot
+}
+
+// Check re-fetches every finding and returns the ones that changed since the previous check,
+// the first check only records the current content.
+func (w *Watcher) Check(ctx context.Context) []Change {
+	changes := make([]Change, 0)
+
+	for _, r := range w.results {
+		current := w.snapshot(ctx, r)
+		checkedAt := time.Now()
+
+		key := r.Target.Method + " " + r.URL.String()
+
+		previous, found := w.snapshots[key]
+		w.snapshots[key] = current
+
+		if found && previous != current {
+			changes = append(changes, Change{Result: r, Previous: previous, Current: current, CheckedAt: checkedAt})
+		}
+	}
+
+	return changes
+}
+
+func (w *Watcher) snapshot(ctx context.Context, r scan.Result) Snapshot {
+	req, err := http.NewRequestWithContext(ctx, r.Target.Method, r.URL.String(), nil)
+	if err != nil {
+		return Snapshot{Err: err.Error()}
+	}
+
+	res, err := w.doer.Do(req)
+	if err != nil {
+		return Snapshot{Err: err.Error()}
+	}
+
+	defer res.Body.Close() //nolint
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, io.LimitReader(res.Body, maxWatchedBodySize))
+	if err != nil {
+		return Snapshot{StatusCode: res.StatusCode, Err: err.Error()}
+	}
+
+	return Snapshot{StatusCode: res.StatusCode, ContentLength: size, Hash: hex.EncodeToString(hash.Sum(nil))}
+}
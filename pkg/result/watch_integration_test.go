@@ -0,0 +1,75 @@
+package result_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/result"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcherShouldReportContentChanges(t *testing.T) {
+	var version int32
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/config.php" && atomic.LoadInt32(&version) > 0 {
+				_, _ = w.Write([]byte("changed content"))
+
+				return
+			}
+
+			_, _ = w.Write([]byte("content"))
+		}),
+	)
+	defer testServer.Close()
+
+	results := []scan.Result{
+		{Target: scan.Target{Method: http.MethodGet}, URL: *test.MustParseURL(t, testServer.URL+"/config.php")},
+		{Target: scan.Target{Method: http.MethodGet}, URL: *test.MustParseURL(t, testServer.URL+"/index.php")},
+	}
+
+	sut := result.NewWatcher(http.DefaultClient, results)
+
+	assert.Empty(t, sut.Check(context.Background()), "the first check should only record the content")
+	assert.Empty(t, sut.Check(context.Background()))
+
+	atomic.StoreInt32(&version, 1)
+
+	changes := sut.Check(context.Background())
+	assert.Len(t, changes, 1)
+
+	assert.Equal(t, results[0], changes[0].Result)
+	assert.Equal(t, http.StatusOK, changes[0].Current.StatusCode)
+	assert.Equal(t, int64(len("content")), changes[0].Previous.ContentLength)
+	assert.Equal(t, int64(len("changed content")), changes[0].Current.ContentLength)
+	assert.NotEqual(t, changes[0].Previous.Hash, changes[0].Current.Hash)
+
+	assert.Empty(t, sut.Check(context.Background()))
+}
+
+func TestWatcherShouldReportFindingsThatBecomeUnreachable(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("content"))
+		}),
+	)
+
+	results := []scan.Result{
+		{Target: scan.Target{Method: http.MethodGet}, URL: *test.MustParseURL(t, testServer.URL+"/admin")},
+	}
+
+	sut := result.NewWatcher(http.DefaultClient, results)
+	assert.Empty(t, sut.Check(context.Background()))
+
+	testServer.Close()
+
+	changes := sut.Check(context.Background())
+	assert.Len(t, changes, 1)
+	assert.Empty(t, changes[0].Previous.Err)
+	assert.NotEmpty(t, changes[0].Current.Err)
+}
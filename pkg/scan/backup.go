@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+)
+
+// LabelBackup is the label of the results exposing the backup of a file found, see WithBackupVariants.
+const LabelBackup = "backup"
+
+// WithBackupVariants requests, for every file found, the names its backups are commonly saved with, eg:
+// config.php~, config.php.bak or .config.php.swp; the backups found are reported with a high severity.
+func WithBackupVariants() ScannerOption {
+	return func(s *Scanner) {
+		s.backupVariants = true
+	}
+}
+
+// backupVariants returns the names the backups of the given file are commonly saved with.
+func backupVariants(name string) []string {
+	return []string{
+		name + "~",
+		name + ".bak",
+		name + ".old",
+		"." + name + ".swp",
+		name + ".zip",
+	}
+}
+
+// probeBackupVariants looks for the backups of the file the target points to, the responses are evaluated
+// the same way the ones of the dictionary entries are.
+func (s *Scanner) probeBackupVariants(
+	ctx context.Context,
+	l *logrus.Entry,
+	baseURL url.URL,
+	target Target,
+	results chan<- Result,
+) {
+	dir, name := path.Split(target.Path)
+
+	for _, variant := range backupVariants(name) {
+		variantTarget := Target{Path: dir + variant, Method: target.Method}
+		u := s.buildURL(baseURL, variantTarget)
+
+		variantReq, err := s.newRequest(ctx, u, variantTarget)
+		if err != nil {
+			l.WithError(err).Warn("failed to build the backup request")
+
+			continue
+		}
+
+		res, err := s.do(variantReq)
+		if err != nil {
+			l.WithError(err).WithField("backup", variant).Debug("backup request failed")
+
+			continue
+		}
+
+		atomic.AddUint64(&s.requests, 1)
+
+		result, d := s.evaluate(ctx, l, variantReq, res, variantTarget)
+		if !d.report {
+			continue
+		}
+
+		result.Labels = append(result.Labels, LabelBackup)
+		result.Severity = SeverityHigh
+
+		l.WithField("backup", u.String()).Info("Backup file found")
+
+		atomic.AddUint64(&s.results, 1)
+
+		results <- result
+	}
+}
+
+// isFile tells whether the target points to a file whose backups can be looked for.
+func isFile(target Target) bool {
+	return !strings.HasSuffix(target.Path, "/") && urlpath.HasExtension(target.Path)
+}
@@ -0,0 +1,35 @@
+package scan
+
+import "regexp"
+
+// WithBodyRegexes ignores the results whose body matches any of the filtered regexes, eg: a known error
+// page, and, when any matched regex is set, the results whose body does not match any of them.
+func WithBodyRegexes(matched, filtered []*regexp.Regexp) ScannerOption {
+	return func(s *Scanner) {
+		s.matchedBodyRegexes = matched
+		s.filteredBodyRegexes = filtered
+	}
+}
+
+func (s *Scanner) hasBodyRegexes() bool {
+	return len(s.matchedBodyRegexes) > 0 || len(s.filteredBodyRegexes) > 0
+}
+
+// ignoredByBody tells whether the body leads the result to be ignored, see WithBodyRegexes.
+func (s *Scanner) ignoredByBody(body []byte) bool {
+	if matchesAny(s.filteredBodyRegexes, body) {
+		return true
+	}
+
+	return len(s.matchedBodyRegexes) > 0 && !matchesAny(s.matchedBodyRegexes, body)
+}
+
+func matchesAny(regexes []*regexp.Regexp, body []byte) bool {
+	for _, regex := range regexes {
+		if regex.Match(body) {
+			return true
+		}
+	}
+
+	return false
+}
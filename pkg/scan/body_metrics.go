@@ -0,0 +1,25 @@
+package scan
+
+import "bytes"
+
+// WithBodyMetrics reads the body of every response to store the amount of its words and lines in the result,
+// and its size when the response does not tell it.
+func WithBodyMetrics() ScannerOption {
+	return func(s *Scanner) {
+		s.bodyMetrics = true
+	}
+}
+
+// countWords returns the amount of words separated by white spaces.
+func countWords(body []byte) int {
+	return len(bytes.Fields(body))
+}
+
+// countLines returns the amount of lines, an empty body having none.
+func countLines(body []byte) int {
+	if len(body) == 0 {
+		return 0
+	}
+
+	return bytes.Count(body, []byte("\n")) + 1
+}
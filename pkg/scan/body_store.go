@@ -0,0 +1,31 @@
+package scan
+
+import "github.com/sirupsen/logrus"
+
+// BodyStore stores the bodies of the results, returning where each of them was stored.
+type BodyStore interface {
+	Store(result Result, body []byte) (string, error)
+}
+
+// WithBodyStore stores the body of every result in the given store, eg: to review the pages found after the
+// scan without requesting them again; the location of the body is recorded in Result.BodyFile.
+func WithBodyStore(store BodyStore) ScannerOption {
+	return func(s *Scanner) {
+		s.bodyStore = store
+	}
+}
+
+func (s *Scanner) storeBody(l *logrus.Entry, body []byte, result *Result) {
+	if len(body) == 0 {
+		return
+	}
+
+	location, err := s.bodyStore.Store(*result, body)
+	if err != nil {
+		l.WithError(err).Warn("failed to store the response body")
+
+		return
+	}
+
+	result.BodyFile = location
+}
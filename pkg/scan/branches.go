@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// branchRegistry keeps track of the paths being recursed into and of the ones that have been skipped.
+type branchRegistry struct {
+	mx      sync.RWMutex
+	active  map[string]int
+	skipped map[string]struct{}
+}
+
+func newBranchRegistry() *branchRegistry {
+	return &branchRegistry{active: make(map[string]int), skipped: make(map[string]struct{})}
+}
+
+func (b *branchRegistry) enter(path string) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.active[normalizeBranch(path)]++
+}
+
+func (b *branchRegistry) leave(path string) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	path = normalizeBranch(path)
+
+	b.active[path]--
+	if b.active[path] <= 0 {
+		delete(b.active, path)
+	}
+}
+
+// skipDeepest marks as skipped the deepest branches being recursed into, the ones having no active sub-branch.
+func (b *branchRegistry) skipDeepest() []string {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	skipped := make([]string, 0, len(b.active))
+
+	for path := range b.active {
+		if b.hasActiveSubBranch(path) {
+			continue
+		}
+
+		b.skipped[path] = struct{}{}
+		skipped = append(skipped, path)
+	}
+
+	sort.Strings(skipped)
+
+	return skipped
+}
+
+func (b *branchRegistry) hasActiveSubBranch(path string) bool {
+	for other := range b.active {
+		if other != path && isInBranch(other, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *branchRegistry) isSkipped(path string) bool {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	path = normalizeBranch(path)
+
+	for skipped := range b.skipped {
+		if isInBranch(path, skipped) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *branchRegistry) activeBranches() []string {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+
+	branches := make([]string, 0, len(b.active))
+	for path := range b.active {
+		branches = append(branches, path)
+	}
+
+	sort.Strings(branches)
+
+	return branches
+}
+
+func isInBranch(path, branch string) bool {
+	return path == branch || strings.HasPrefix(path, branch+"/")
+}
+
+func normalizeBranch(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
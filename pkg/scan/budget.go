@@ -0,0 +1,37 @@
+package scan
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// ErrRequestBudgetExhausted is returned by Scanner.Err when the scan was stopped by WithMaxRequests.
+var ErrRequestBudgetExhausted = errors.New("request budget exhausted")
+
+// WithMaxRequests stops the scan once the given amount of requests was performed, counting also the retries
+// and the requests made to calibrate or to probe the results, eg: to respect the request cap of an engagement.
+// The results found so far are kept, see Scanner.Err.
+func WithMaxRequests(maxRequests uint64) ScannerOption {
+	return func(s *Scanner) {
+		s.maxRequests = maxRequests
+	}
+}
+
+// consumeRequestBudget reserves a request out of the budget, stopping the scan when it is exhausted.
+// Unlike abort the scan is not canceled: the requests in flight already consumed their budget, so they
+// are completed and their results kept, while the targets not requested yet are drained.
+func (s *Scanner) consumeRequestBudget() error {
+	if s.maxRequests == 0 || atomic.AddUint64(&s.budgetConsumed, 1) <= s.maxRequests {
+		return nil
+	}
+
+	s.abortMx.Lock()
+	defer s.abortMx.Unlock()
+
+	if s.abortErr == nil {
+		s.abortErr = errors.Wrapf(ErrRequestBudgetExhausted, "%d requests performed", s.maxRequests)
+	}
+
+	return ErrRequestBudgetExhausted
+}
@@ -0,0 +1,122 @@
+package scan
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// bypassTechnique alters a request in a way known to trick some access controls.
+type bypassTechnique struct {
+	name  string
+	apply func(req *http.Request)
+}
+
+func withHeader(name, value string) bypassTechnique {
+	return bypassTechnique{
+		name:  name + ": " + value,
+		apply: func(req *http.Request) { req.Header.Set(name, value) },
+	}
+}
+
+// withRewriteHeader sends the original path in the given header while the request points to the root.
+func withRewriteHeader(name string) bypassTechnique {
+	return bypassTechnique{
+		name: name,
+		apply: func(req *http.Request) {
+			req.Header.Set(name, req.URL.Path)
+			req.URL.Path, req.URL.RawPath = "/", ""
+		},
+	}
+}
+
+func bypassTechniques() []bypassTechnique {
+	withPath := func(name string, mutate func(path string) (string, string)) bypassTechnique {
+		return bypassTechnique{
+			name: name,
+			apply: func(req *http.Request) {
+				req.URL.Path, req.URL.RawPath = mutate(req.URL.Path)
+			},
+		}
+	}
+
+	return []bypassTechnique{
+		withHeader("X-Forwarded-For", "127.0.0.1"),
+		withHeader("X-Real-IP", "127.0.0.1"),
+		withHeader("X-Custom-IP-Authorization", "127.0.0.1"),
+		withRewriteHeader("X-Original-URL"),
+		withRewriteHeader("X-Rewrite-URL"),
+		withPath("path /%2e/", func(path string) (string, string) {
+			path = strings.TrimPrefix(path, "/")
+
+			return "/./" + path, "/%2e/" + path
+		}),
+		withPath("path //", func(path string) (string, string) {
+			return "/" + path, ""
+		}),
+		withPath("path /.", func(path string) (string, string) {
+			return strings.TrimSuffix(path, "/") + "/.", ""
+		}),
+		withPath("path ..;/", func(path string) (string, string) {
+			return strings.TrimSuffix(path, "/") + "..;/", ""
+		}),
+		withPath("path %20", func(path string) (string, string) {
+			return path + " ", ""
+		}),
+	}
+}
+
+// retryWithBypass performs the request again with every bypass technique and emits the attempts
+// that were allowed.
+func (s *Scanner) retryWithBypass(l *logrus.Entry, req *http.Request, target Target, results chan<- Result) {
+	s.retryWithTechniques(l, req, target, bypassTechniques(), func(status int) bool { return status/100 == 2 }, results)
+}
+
+// retryWithTechniques performs the request again with every given technique and emits the attempts getting
+// an allowed status.
+func (s *Scanner) retryWithTechniques(
+	l *logrus.Entry,
+	req *http.Request,
+	target Target,
+	techniques []bypassTechnique,
+	allowed func(status int) bool,
+	results chan<- Result,
+) {
+	for _, technique := range techniques {
+		bypassReq := cloneRequest(req.Context(), req)
+		technique.apply(bypassReq)
+
+		// some techniques only change the headers, the request cache would consider them redundant
+		res, err := s.do(client.SkipRequestCache(bypassReq))
+		if err != nil {
+			l.WithError(err).WithField("bypass", technique.name).Debug("bypass attempt failed")
+
+			continue
+		}
+
+		atomic.AddUint64(&s.requests, 1)
+
+		if err := res.Body.Close(); err != nil {
+			l.WithError(err).Warn("failed to close response body")
+		}
+
+		if !allowed(res.StatusCode) {
+			continue
+		}
+
+		result := NewResult(target, res)
+		result.Bypass = technique.name
+
+		l.WithFields(logrus.Fields{
+			"bypass": technique.name,
+			"status": res.StatusCode,
+		}).Info("Access control bypassed")
+
+		atomic.AddUint64(&s.results, 1)
+
+		results <- result
+	}
+}
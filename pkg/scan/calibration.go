@@ -0,0 +1,228 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// calibrationSamples is the amount of non-existent paths requested to learn how a directory answers to them.
+const calibrationSamples = 3
+
+// WithCalibration requests random non-existent paths in every directory before scanning it, and ignores the
+// results answered the same way, eg: by targets replying 200 with a "not found" page to every request.
+// The directories are calibrated separately for every method and file extension.
+func WithCalibration() ScannerOption {
+	return func(s *Scanner) {
+		s.calibrations = &calibrations{byKey: make(map[string]*calibration)}
+	}
+}
+
+// WithCalibrationSimilarity calibrates the directories as WithCalibration does, and also ignores the results
+// whose body shares at least the given percentage of words with the ones of the non-existent paths, eg: for the
+// "not found" pages embedding a timestamp or a CSRF token that defeat the exact matching.
+func WithCalibrationSimilarity(percentage int) ScannerOption {
+	return func(s *Scanner) {
+		if s.calibrations == nil {
+			WithCalibration()(s)
+		}
+
+		s.calibrationSimilarity = percentage
+	}
+}
+
+// responseFingerprint describes a response regardless of the path requested, which is removed from the body
+// and from the Location header as the pages of the missing resources often mention it, whole or only its name.
+type responseFingerprint struct {
+	status int
+	words  int
+	lines  int
+	hash   [sha256.Size]byte
+	// similarity is computed only when the results are matched by similarity
+	similarity *similarityHash
+}
+
+func (s *Scanner) fingerprintResponse(res *http.Response, body []byte, name string) responseFingerprint {
+	location := res.Header.Get("Location")
+
+	if name != "" {
+		for _, mention := range []string{res.Request.URL.Path, name} {
+			body = bytes.ReplaceAll(body, []byte(mention), nil)
+			location = strings.ReplaceAll(location, mention, "")
+		}
+	}
+
+	f := responseFingerprint{
+		status: res.StatusCode,
+		words:  countWords(body),
+		lines:  countLines(body),
+		hash:   sha256.Sum256(append(append([]byte(location), '\n'), body...)),
+	}
+
+	if s.calibrationSimilarity > 0 {
+		f.similarity = newSimilarityHash(body)
+	}
+
+	return f
+}
+
+type calibration struct {
+	once    sync.Once
+	samples []responseFingerprint
+	// dynamic is set when the samples differ only in their content, eg: because of a timestamp or a token,
+	// the responses are then matched by status, words and lines
+	dynamic bool
+}
+
+func (c *calibration) matches(f responseFingerprint, minSimilarity int) bool {
+	for _, sample := range c.samples {
+		if sample.status != f.status {
+			continue
+		}
+
+		if sample.hash == f.hash || (c.dynamic && sample.words == f.words && sample.lines == f.lines) {
+			return true
+		}
+
+		if minSimilarity > 0 && sample.similarity.similarity(f.similarity) >= minSimilarity {
+			return true
+		}
+	}
+
+	return false
+}
+
+type calibrations struct {
+	mx    sync.Mutex
+	byKey map[string]*calibration
+}
+
+func (c *calibrations) get(key string) *calibration {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if _, found := c.byKey[key]; !found {
+		c.byKey[key] = &calibration{}
+	}
+
+	return c.byKey[key]
+}
+
+// matchesCalibration tells whether the response is answered the same way as the non-existent paths of its
+// directory, calibrating the directory the first time.
+func (s *Scanner) matchesCalibration(
+	ctx context.Context,
+	l *logrus.Entry,
+	req *http.Request,
+	res *http.Response,
+	body []byte,
+	target Target,
+) bool {
+	requestedPath := req.URL.Path
+
+	// when fuzzing the non-existent paths are placed where the FuzzKeyword is, as the paths of the dictionary
+	if s.fuzzingURL != nil {
+		requestedPath = target.Path
+	}
+
+	dir, name := path.Split(requestedPath)
+	ext := path.Ext(name)
+
+	newCalibrationRequest := func(name string) (*http.Request, error) {
+		calibrationTarget := Target{Path: dir + name, Method: req.Method}
+
+		// when fuzzing the name is placed in the query or in the body as well
+		if s.fuzzingURL != nil {
+			return s.newRequest(ctx, fuzzURL(*s.fuzzingURL, calibrationTarget), calibrationTarget)
+		}
+
+		u := *req.URL
+		u.Path = dir + name
+		u.RawPath = ""
+
+		return s.newRequest(ctx, u, calibrationTarget)
+	}
+
+	c := s.calibrations.get(req.Method + " " + dir + " " + ext)
+	c.once.Do(func() {
+		s.calibrate(l.WithField("directory", dir), c, newCalibrationRequest, ext)
+	})
+
+	return c.matches(s.fingerprintResponse(res, body, name), s.calibrationSimilarity)
+}
+
+func (s *Scanner) calibrate(
+	l *logrus.Entry,
+	c *calibration,
+	newCalibrationRequest func(name string) (*http.Request, error),
+	ext string,
+) {
+	for i := 0; i < calibrationSamples; i++ {
+		name := randomCalibrationName() + ext
+
+		calibrationReq, err := newCalibrationRequest(name)
+		if err != nil {
+			l.WithError(err).Warn("failed to build the calibration request")
+
+			return
+		}
+
+		res, err := s.do(calibrationReq)
+
+		atomic.AddUint64(&s.requests, 1)
+
+		if err != nil {
+			l.WithError(err).Warn("failed to calibrate, the results of the directory are not calibrated")
+
+			return
+		}
+
+		body := s.readBody(l, res)
+
+		if err := res.Body.Close(); err != nil {
+			l.WithError(err).Warn("failed to close response body")
+		}
+
+		c.samples = append(c.samples, s.fingerprintResponse(res, body, name))
+	}
+
+	// a static page answered to every path is matched exactly, not to mistake the real pages of the same size
+	// for it
+	sameMetrics, sameContent := true, true
+
+	for _, sample := range c.samples[1:] {
+		first := c.samples[0]
+
+		if sample.status != first.status || sample.words != first.words || sample.lines != first.lines {
+			sameMetrics = false
+		}
+
+		if sample.hash != first.hash {
+			sameContent = false
+		}
+	}
+
+	c.dynamic = sameMetrics && !sameContent
+
+	l.WithFields(logrus.Fields{
+		"status":  c.samples[0].status,
+		"words":   c.samples[0].words,
+		"dynamic": c.dynamic,
+	}).Debug("Calibrated")
+}
+
+func randomCalibrationName() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
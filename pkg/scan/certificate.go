@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Certificate describes the certificate presented by a host in the TLS handshake.
+type Certificate struct {
+	Subject   string
+	Issuer    string
+	SANs      []string `json:",omitempty"`
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// certificateRegistry keeps the certificate of every host, recorded the first time the host is reached.
+type certificateRegistry struct {
+	mx           sync.Mutex
+	certificates map[string]Certificate
+}
+
+// record stores the certificate of the host, returning true only the first time the host is recorded.
+func (c *certificateRegistry) record(host string, state *tls.ConnectionState) (Certificate, bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return Certificate{}, false
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if _, found := c.certificates[host]; found {
+		return Certificate{}, false
+	}
+
+	leaf := state.PeerCertificates[0]
+
+	certificate := Certificate{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		SANs:      append([]string{}, leaf.DNSNames...),
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+	}
+
+	for _, ip := range leaf.IPAddresses {
+		certificate.SANs = append(certificate.SANs, ip.String())
+	}
+
+	if c.certificates == nil {
+		c.certificates = make(map[string]Certificate)
+	}
+
+	c.certificates[host] = certificate
+
+	return certificate, true
+}
+
+func (c *certificateRegistry) all() map[string]Certificate {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if len(c.certificates) == 0 {
+		return nil
+	}
+
+	certificates := make(map[string]Certificate, len(c.certificates))
+	for host, certificate := range c.certificates {
+		certificates[host] = certificate
+	}
+
+	return certificates
+}
+
+// Certificates returns the certificate presented by every host reached over TLS, nil when no TLS connection
+// was made.
+func (s *Scanner) Certificates() map[string]Certificate {
+	return s.certificates.all()
+}
+
+func (s *Scanner) recordCertificate(host string, state *tls.ConnectionState) {
+	certificate, recorded := s.certificates.record(host, state)
+	if !recorded {
+		return
+	}
+
+	l := s.logger.WithFields(logrus.Fields{
+		"host":      host,
+		"subject":   certificate.Subject,
+		"issuer":    certificate.Issuer,
+		"sans":      certificate.SANs,
+		"not-after": certificate.NotAfter.Format(time.RFC3339),
+	})
+
+	if time.Now().After(certificate.NotAfter) {
+		l.Warn("The certificate is expired")
+
+		return
+	}
+
+	l.Info("Certificate")
+}
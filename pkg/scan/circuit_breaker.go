@@ -0,0 +1,154 @@
+package scan
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTooManyConnectionErrors is returned by Scanner.Err when the circuit breaker aborted the scan.
+var ErrTooManyConnectionErrors = errors.New("too many consecutive connection errors")
+
+// ErrTooManyErrors is returned by Scanner.Err when the scan was aborted by WithMaxErrors.
+var ErrTooManyErrors = errors.New("too many failed requests")
+
+// WithCircuitBreaker stops the scan after the given amount of consecutive requests failed because of
+// a connection error, eg: the target or the proxy are down.
+// The requests are paused for the given time, then the scan resumes until the next failure;
+// when the pause is zero the scan is aborted instead, see Scanner.Err.
+func WithCircuitBreaker(threshold int, pause time.Duration) ScannerOption {
+	return func(s *Scanner) {
+		s.circuitBreaker = &circuitBreaker{threshold: threshold, pause: pause}
+	}
+}
+
+type circuitBreaker struct {
+	mx        sync.Mutex
+	threshold int
+	pause     time.Duration
+	failures  int
+	// openUntil is when the requests can be performed again
+	openUntil time.Time
+}
+
+// failure records a connection error, it returns true when the circuit opens because of it.
+func (c *circuitBreaker) failure() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.failures++
+	if c.failures < c.threshold || time.Now().Before(c.openUntil) {
+		return false
+	}
+
+	c.openUntil = time.Now().Add(c.pause)
+
+	return true
+}
+
+func (c *circuitBreaker) success() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.failures = 0
+}
+
+// wait blocks while the circuit is open or until the context is done.
+func (c *circuitBreaker) wait(ctx context.Context) error {
+	c.mx.Lock()
+	delay := time.Until(c.openUntil)
+	c.mx.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// recordConnectionResult feeds the circuit breaker with the outcome of a request, err being the
+// connection error if any.
+func (s *Scanner) recordConnectionResult(err error) {
+	if s.circuitBreaker == nil {
+		return
+	}
+
+	if err == nil {
+		s.circuitBreaker.success()
+
+		return
+	}
+
+	if errors.Is(err, context.Canceled) || !s.circuitBreaker.failure() {
+		return
+	}
+
+	l := s.logger.WithError(err).WithField("consecutive-errors", s.circuitBreaker.threshold)
+
+	if s.circuitBreaker.pause > 0 {
+		l.WithField("pause", s.circuitBreaker.pause).Warn("Too many connection errors, pausing the scan")
+
+		return
+	}
+
+	l.Error("Too many connection errors, aborting the scan")
+
+	s.abort(errors.Wrapf(
+		ErrTooManyConnectionErrors,
+		"%d connection errors in a row, the last one being `%s`",
+		s.circuitBreaker.threshold,
+		err,
+	))
+}
+
+// Err returns the reason why the scan was aborted, nil when it was not.
+func (s *Scanner) Err() error {
+	s.abortMx.Lock()
+	defer s.abortMx.Unlock()
+
+	return s.abortErr
+}
+
+func (s *Scanner) abort(err error) {
+	s.abortMx.Lock()
+	defer s.abortMx.Unlock()
+
+	if s.abortErr != nil {
+		return
+	}
+
+	s.abortErr = err
+
+	if s.cancelScan != nil {
+		s.cancelScan()
+	}
+}
+
+// WithMaxErrors aborts the scan once more than the given amount of requests failed, consecutive or not,
+// eg: instead of failing every request of the dictionary through a dead proxy. See Scanner.Err.
+func WithMaxErrors(maxErrors int) ScannerOption {
+	return func(s *Scanner) {
+		s.maxErrors = maxErrors
+	}
+}
+
+// recordError aborts the scan when the failed requests, errorsCount included, exceed the ones allowed.
+func (s *Scanner) recordError(err error, errorsCount uint64) {
+	if s.maxErrors <= 0 || errorsCount <= uint64(s.maxErrors) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.logger.WithError(err).WithField("errors", errorsCount).Error("Too many failed requests, aborting the scan")
+
+	s.abort(errors.Wrapf(ErrTooManyErrors, "%d requests failed, the last one with `%s`", errorsCount, err))
+}
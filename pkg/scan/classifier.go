@@ -0,0 +1,22 @@
+package scan
+
+import "net/http"
+
+// Classification is the outcome of a Classifier.
+type Classification struct {
+	Labels   []string
+	Severity string
+}
+
+// Classifier labels the results based on their response.
+type Classifier interface {
+	Classify(result Result, header http.Header, body []byte) Classification
+}
+
+// WithClassifier reads the body of every response and attaches the labels of the classifier to the result,
+// the labels are known before the Rules are matched.
+func WithClassifier(classifier Classifier) ScannerOption {
+	return func(s *Scanner) {
+		s.classifier = classifier
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minBandwidthChunk is the smallest amount of bytes read at once from a throttled body.
+const minBandwidthChunk = 1024
+
+// bandwidthLimiter spreads the bytes read by all the requests together over time, so that the download
+// rate never exceeds the given amount of bytes per second.
+type bandwidthLimiter struct {
+	mx             sync.Mutex
+	bytesPerSecond int64
+	// next is when the bytes read so far have been paid for
+	next time.Time
+}
+
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	return &bandwidthLimiter{bytesPerSecond: bytesPerSecond}
+}
+
+// chunk returns how many bytes can be read at once, so that every read is paid for in about 1/10 of second.
+func (b *bandwidthLimiter) chunk() int {
+	if chunk := b.bytesPerSecond / 10; chunk > minBandwidthChunk {
+		return int(chunk)
+	}
+
+	return minBandwidthChunk
+}
+
+// wait blocks until the given amount of read bytes fits in the bandwidth or the context is done.
+func (b *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	b.mx.Lock()
+
+	now := time.Now()
+	if b.next.Before(now) {
+		b.next = now
+	}
+
+	b.next = b.next.Add(time.Duration(int64(n) * int64(time.Second) / b.bytesPerSecond))
+	delay := b.next.Sub(now)
+
+	b.mx.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func decorateTransportWithBandwidthDecorator(
+	decorated http.RoundTripper,
+	bytesPerSecond int64,
+) (*bandwidthTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if bytesPerSecond <= 0 {
+		return nil, errors.New("the bandwidth must be greater than 0")
+	}
+
+	return &bandwidthTransportDecorator{decorated: decorated, limiter: newBandwidthLimiter(bytesPerSecond)}, nil
+}
+
+// bandwidthTransportDecorator throttles the reading of the response bodies, the bytes still in flight
+// slowing down the target through the flow control of TCP.
+type bandwidthTransportDecorator struct {
+	decorated http.RoundTripper
+	limiter   *bandwidthLimiter
+}
+
+func (b *bandwidthTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	res, err := b.decorated.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Body = &throttledReadCloser{ReadCloser: res.Body, ctx: r.Context(), limiter: b.limiter}
+
+	return res, nil
+}
+
+type throttledReadCloser struct {
+	io.ReadCloser
+
+	ctx     context.Context
+	limiter *bandwidthLimiter
+}
+
+func (t *throttledReadCloser) Read(p []byte) (int, error) {
+	if chunk := t.limiter.chunk(); len(p) > chunk {
+		p = p[:chunk]
+	}
+
+	n, err := t.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := t.limiter.wait(t.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,18 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportWithBandwidth(t *testing.T) {
+	transport, err := decorateTransportWithBandwidthDecorator(nil, 1024)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+
+	transport, err = decorateTransportWithBandwidthDecorator(http.DefaultTransport, 0)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
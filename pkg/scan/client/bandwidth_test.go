@@ -0,0 +1,65 @@
+package client_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldCapTheBandwidthOfAllTheRequestsTogether(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 8<<10)
+
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(body)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		5000,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithMaxBandwidth(48<<10),
+	)
+	assert.NoError(t, err)
+
+	start := time.Now()
+
+	done := make(chan struct{})
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			res, err := c.Get(testServer.URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			read, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, read)
+			assert.NoError(t, res.Body.Close())
+		}()
+	}
+
+	for i := 0; i < 3; i++ {
+		<-done
+	}
+
+	// 24KB at 48KB/s take about half a second
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
@@ -24,8 +24,24 @@ func NewClientFromConfig(
 	shouldCacheRequests bool,
 	shouldSkipSSLCertificatesValidation bool,
 	u *url.URL,
+	opts ...Option,
 ) (*http.Client, error) {
-	transport := buildTransport(shouldSkipSSLCertificatesValidation)
+	o := buildOptions(opts)
+
+	otherAuth := o.oauth2ClientCredentials != nil || o.digestCredentials != nil || o.tokenCommand != ""
+	if o.ntlmCredentials != nil && otherAuth {
+		return nil, errors.New(
+			"NewClientFromConfig: ntlm auth cannot be used together with oauth2, digest auth or a token command",
+		)
+	}
+
+	tlsConfig, err := buildTLSConfig(shouldSkipSSLCertificatesValidation, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig: failed to build tls config")
+	}
+
+	transport := buildTransport(tlsConfig)
+	configureTransport(transport, o)
 
 	c := &http.Client{
 		Timeout:   time.Millisecond * time.Duration(timeoutInMilliseconds),
@@ -35,27 +51,126 @@ func NewClientFromConfig(
 		},
 	}
 
-	if useCookieJar {
-		jar, err := cookiejar.New(nil)
+	if c.Jar, err = buildCookieJar(useCookieJar, cookies, u); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig: failed to create cookie jar")
+	}
+
+	baseDialer, err := useDirectDialer(transport, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	if err := useProxies(transport, socks5Url, baseDialer, o); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	c.Transport, err = buildClientRoundTripper(transport, baseDialer, shouldSkipSSLCertificatesValidation, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	if c.Transport, err = decorateConnection(c.Transport, o); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	if c.Transport, err = decorateRequests(c.Transport, userAgent, headers, o); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	tokenClient := &http.Client{Timeout: c.Timeout, Transport: transport}
+	if c.Transport, err = decorateAuthentication(c.Transport, tokenClient, o); err != nil {
+		return nil, errors.Wrap(err, "NewClientFromConfig")
+	}
+
+	if shouldCacheRequests {
+		var requests requestSet
+		if o.requestCacheBloomFilter != nil {
+			requests = bloomRequestSet{filter: o.requestCacheBloomFilter}
+		}
+
+		c.Transport, err = decorateTransportWithRequestCacheDecorator(c.Transport, requests)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to create cookie jar")
+			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
 		}
+	}
 
-		c.Jar = jar
+	if o.scope != nil {
+		c.Transport, err = decorateTransportWithScopeDecorator(c.Transport, o.scope)
+		if err != nil {
+			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+		}
 	}
 
-	if c.Jar != nil {
-		c.Jar.SetCookies(u, cookies)
+	return c, nil
+}
+
+func configureTransport(transport *http.Transport, o options) {
+	if o.tlsHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = o.tlsHandshakeTimeout
 	}
 
-	if len(cookies) > 0 && c.Jar == nil {
-		c.Jar = cookie.NewStatelessJar(cookies)
+	transport.ResponseHeaderTimeout = o.responseHeaderTimeout
+
+	if o.maxIdleConns > 0 {
+		transport.MaxIdleConnsPerHost = o.maxIdleConns
+
+		if o.maxIdleConns > transport.MaxIdleConns {
+			transport.MaxIdleConns = o.maxIdleConns
+		}
+	}
+
+	transport.MaxConnsPerHost = o.maxConnsPerHost
+	transport.DisableKeepAlives = o.disableKeepAlives
+	transport.DisableCompression = o.disableCompression
+}
+
+// buildCookieJar returns a jar keeping the cookies set by the target when asked, otherwise a jar always sending
+// the given cookies, nil when there are none.
+func buildCookieJar(useCookieJar bool, cookies []*http.Cookie, u *url.URL) (http.CookieJar, error) {
+	if !useCookieJar {
+		if len(cookies) > 0 {
+			return cookie.NewStatelessJar(cookies), nil
+		}
+
+		return nil, nil
 	}
 
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jar.SetCookies(u, cookies)
+
+	return jar, nil
+}
+
+// useDirectDialer sets the dialer opening the connections to the targets or to the first proxy, and returns it to
+// be used by the proxies.
+func useDirectDialer(transport *http.Transport, o options) (proxy.Dialer, error) {
+	directDialer, err := newDirectDialer(o)
+	if err != nil {
+		return nil, err
+	}
+
+	if directDialer == nil {
+		directDialer = o.dialer
+	}
+
+	if directDialer == nil {
+		return proxy.Direct, nil
+	}
+
+	transport.DialContext = directDialer.DialContext
+
+	return dialerAdapter{Dialer: directDialer}, nil
+}
+
+func useProxies(transport *http.Transport, socks5Url *url.URL, baseDialer proxy.Dialer, o options) error {
 	if socks5Url != nil {
-		tbDialer, err := proxy.FromURL(socks5Url, proxy.Direct)
+		tbDialer, err := proxy.FromURL(socks5Url, baseDialer)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to create socks5 proxy")
+			return errors.Wrap(err, "failed to create socks5 proxy")
 		}
 
 		transport.DialContext = func(ctx context.Context, network, addr string) (conn net.Conn, e error) {
@@ -63,42 +178,272 @@ func NewClientFromConfig(
 		}
 	}
 
+	if len(o.proxyChain) > 0 {
+		if socks5Url != nil {
+			return errors.New("a socks5 proxy and a proxy chain cannot be used together")
+		}
+
+		chainDialer, err := newProxyChainDialer(o.proxyChain, o.proxyAuth, baseDialer)
+		if err != nil {
+			return errors.Wrap(err, "failed to create proxy chain")
+		}
+
+		transport.DialContext = chainDialer.DialContext
+	}
+
+	if o.httpProxy != nil {
+		if err := useHTTPProxy(transport, socks5Url, o); err != nil {
+			return errors.Wrap(err, "failed to set http proxy")
+		}
+	}
+
+	if len(o.proxyList) > 0 && (socks5Url != nil || len(o.proxyChain) > 0 || o.httpProxy != nil) {
+		return errors.New("a proxy rotation cannot be used together with other proxies")
+	}
+
+	if o.pac != nil && (socks5Url != nil || len(o.proxyChain) > 0 || o.httpProxy != nil || len(o.proxyList) > 0) {
+		return errors.New("a proxy auto-config cannot be used together with other proxies")
+	}
+
+	return nil
+}
+
+func buildClientRoundTripper(
+	transport *http.Transport,
+	baseDialer proxy.Dialer,
+	shouldSkipSSLCertificatesValidation bool,
+	o options,
+) (http.RoundTripper, error) {
+	if len(o.insecureSkipVerifyHosts) == 0 || shouldSkipSSLCertificatesValidation {
+		return buildRoundTripper(transport, baseDialer, o)
+	}
+
+	// cloned before being configured, as configuring the round tripper alters the transport
+	insecureTransport := transport.Clone()
+	insecureTransport.TLSClientConfig.InsecureSkipVerify = true
+
+	secure, err := buildRoundTripper(transport, baseDialer, o)
+	if err != nil {
+		return nil, err
+	}
+
+	insecure, err := buildRoundTripper(insecureTransport, baseDialer, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return newInsecureHostsRoundTripper(secure, insecure, o.insecureSkipVerifyHosts), nil
+}
+
+// decorateConnection decorates the transport with what applies to the connection rather than to the requests.
+func decorateConnection(transport http.RoundTripper, o options) (http.RoundTripper, error) {
+	var err error
+
+	// the bytes are counted before being decompressed, as they are received from the network
+	if o.maxBandwidth > 0 {
+		if transport, err = decorateTransportWithBandwidthDecorator(transport, o.maxBandwidth); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	if !o.disableCompression {
+		if transport, err = decorateTransportWithDecompressionDecorator(transport); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	if o.torCircuitRenewal != nil {
+		transport, err = decorateTransportWithTorCircuitRenewalDecorator(transport, *o.torCircuitRenewal)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	for _, decorator := range o.transportDecorators {
+		transport = decorator(transport)
+	}
+
+	// the signature must be computed after every other decorator has altered the request,
+	// so it has to wrap the transport directly
+	if o.awsSigV4 != nil {
+		if transport, err = decorateTransportWithSigV4Decorator(transport, *o.awsSigV4); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	return transport, nil
+}
+
+// decorateRequests decorates the transport with the user agent, the headers and the session of the requests.
+func decorateRequests(
+	transport http.RoundTripper,
+	userAgent string,
+	headers map[string]string,
+	o options,
+) (http.RoundTripper, error) {
 	var err error
 
-	c.Transport, err = decorateTransportWithUserAgentDecorator(c.Transport, userAgent)
+	if len(o.userAgents) > 0 {
+		transport, err = decorateTransportWithRandomUserAgentDecorator(transport, o.userAgents)
+	} else {
+		transport, err = decorateTransportWithUserAgentDecorator(transport, userAgent)
+	}
+
 	if err != nil {
-		return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+		return nil, errors.Wrap(err, "failed to decorate transport")
+	}
+
+	// the dynamic placeholders are expanded after the header pool ones, which take precedence
+	if hasDynamicPlaceholders(headers) {
+		if transport, err = decorateTransportWithHeaderPlaceholdersDecorator(transport); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	// the placeholders are replaced after the headers have been set
+	if o.headerPool != nil {
+		if transport, err = decorateTransportWithHeaderPoolDecorator(transport, o.headerPool); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	// the session replaces the cookies and the headers set by the user
+	if o.login != nil {
+		if transport, err = decorateTransportWithLoginDecorator(transport, o.login); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
 	}
 
 	if len(headers) > 0 {
-		c.Transport, err = decorateTransportWithHeadersDecorator(c.Transport, headers)
+		if transport, err = decorateTransportWithHeadersDecorator(transport, headers); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	return transport, nil
+}
+
+// decorateAuthentication decorates the transport with the authentication of the requests, the tokens are requested
+// with the given client.
+func decorateAuthentication(
+	transport http.RoundTripper,
+	tokenClient *http.Client,
+	o options,
+) (http.RoundTripper, error) {
+	var err error
+
+	if o.oauth2ClientCredentials != nil {
+		tokenSource := newOAuth2ClientCredentialsTokenSource(*o.oauth2ClientCredentials, tokenClient)
+
+		if transport, err = decorateTransportWithBearerTokenDecorator(transport, tokenSource); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	if o.tokenCommand != "" {
+		if o.oauth2ClientCredentials != nil {
+			return nil, errors.New("a token command and oauth2 cannot be used together")
+		}
+
+		transport, err = decorateTransportWithBearerTokenDecorator(transport, newCommandTokenSource(o.tokenCommand))
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+			return nil, errors.Wrap(err, "failed to decorate transport")
 		}
 	}
 
-	if shouldCacheRequests {
-		c.Transport, err = decorateTransportWithRequestCacheDecorator(c.Transport)
+	if o.digestCredentials != nil {
+		if o.oauth2ClientCredentials != nil || o.tokenCommand != "" {
+			return nil, errors.New("digest auth cannot be used together with oauth2 or a token command")
+		}
+
+		if transport, err = decorateTransportWithDigestAuthDecorator(transport, *o.digestCredentials); err != nil {
+			return nil, errors.Wrap(err, "failed to decorate transport")
+		}
+	}
+
+	return transport, nil
+}
+
+func buildRoundTripper(transport *http.Transport, baseDialer proxy.Dialer, o options) (http.RoundTripper, error) {
+	if len(o.proxyList) > 0 {
+		roundTripper, err := newProxyRotator(transport, baseDialer, o)
 		if err != nil {
-			return nil, errors.Wrap(err, "NewClientFromConfig: failed to decorate transport")
+			return nil, errors.Wrap(err, "failed to create proxy rotation")
 		}
+
+		return roundTripper, nil
 	}
 
-	return c, nil
+	if o.pac != nil {
+		roundTripper, err := newPACRoundTripper(transport, baseDialer, o)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create proxy auto-config")
+		}
+
+		return roundTripper, nil
+	}
+
+	return configureRoundTripper(transport, o)
+}
+
+// configureRoundTripper applies the TLS fingerprint and the HTTP version to the transport.
+func configureRoundTripper(transport *http.Transport, o options) (http.RoundTripper, error) {
+	if o.tlsFingerprint != "" {
+		if err := useTLSFingerprint(transport, o.tlsFingerprint, o.tlsJA3); err != nil {
+			return nil, errors.Wrap(err, "failed to set tls fingerprint")
+		}
+	}
+
+	if o.ntlmCredentials != nil {
+		return ntlmRoundTripper(transport, o)
+	}
+
+	roundTripper, err := roundTripperForHTTPVersion(transport, o.httpVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to set http version")
+	}
+
+	return roundTripper, nil
 }
 
-func buildTransport(shouldSkipSSLCertificatesValidation bool) *http.Transport {
+// ntlmRoundTripper authenticates the connections of the transport with NTLM, which requires them to be
+// kept alive and not to be multiplexed: only HTTP/1.1 can be used.
+func ntlmRoundTripper(transport *http.Transport, o options) (http.RoundTripper, error) {
+	if o.httpVersion != "" && o.httpVersion != HTTPVersion11 {
+		return nil, errors.New("ntlm auth can only be used with HTTP/1.1")
+	}
+
+	if transport.DisableKeepAlives {
+		return nil, errors.New("ntlm auth cannot be used with keep-alive disabled")
+	}
+
+	if _, err := roundTripperForHTTPVersion(transport, HTTPVersion11); err != nil {
+		return nil, errors.Wrap(err, "failed to set http version")
+	}
+
+	return newNTLMAuthRoundTripper(transport, *o.ntlmCredentials), nil
+}
+
+func buildTransport(tlsConfig *tls.Config) *http.Transport {
 	transport := http.Transport{
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+		// a custom tls.Config or dialer would otherwise prevent the transport from negotiating HTTP/2
+		ForceAttemptHTTP2: true,
 	}
 
-	if shouldSkipSSLCertificatesValidation {
-		//nolint:gosec
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return &transport
+}
+
+// tlsHandshakeContext bounds the handshakes the transport does not perform itself, like the ones using
+// a custom fingerprint, with the TLS handshake timeout of the transport.
+func tlsHandshakeContext(ctx context.Context, transport *http.Transport) (context.Context, context.CancelFunc) {
+	if transport.TLSHandshakeTimeout <= 0 {
+		return context.WithCancel(ctx)
 	}
 
-	return &transport
+	return context.WithTimeout(ctx, transport.TLSHandshakeTimeout)
 }
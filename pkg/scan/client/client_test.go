@@ -1,8 +1,18 @@
 package client_test
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -261,6 +271,17 @@ func TestShouldNotRepeatTheSameRequestTwice(t *testing.T) {
 	assert.Nil(t, res)
 
 	assert.Equal(t, 1, serverAssertion.Len())
+
+	// the same path with another query is a different request
+	req, err = http.NewRequest(http.MethodGet, u.String()+"?page=2", nil)
+	assert.NoError(t, err)
+
+	res, err = c.Do(req)
+	assert.NoError(t, err)
+
+	res.Body.Close() //nolint:errcheck,gosec
+
+	assert.Equal(t, 2, serverAssertion.Len())
 }
 
 func TestShouldFailToCommunicateWithServerHavingInvalidSSLCertificates(t *testing.T) {
@@ -335,3 +356,973 @@ func TestShouldBeAbleToSkipSSLCertificatesCheck(t *testing.T) {
 	// the request should hit the handler
 	assert.Equal(t, 1, serverAssertion.Len())
 }
+
+func TestShouldAuthenticateUsingOAuth2ClientCredentials(t *testing.T) {
+	tokenCounter := 0
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				tokenCounter++
+
+				assert.NoError(t, r.ParseForm())
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":1}`, tokenCounter)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		u,
+		client.WithOAuth2ClientCredentials(client.OAuth2ClientCredentials{
+			TokenURL:     testServer.URL + "/token",
+			ClientID:     "my-client",
+			ClientSecret: "my-secret",
+			Scopes:       []string{"read", "write"},
+		}),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(testServer.URL + "/home")
+		assert.NoError(t, err)
+		res.Body.Close() //nolint:errcheck,gosec
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	assert.Equal(t, 4, serverAssertion.Len())
+
+	serverAssertion.At(0, func(r http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "my-client", clientID)
+		assert.Equal(t, "my-secret", clientSecret)
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+	})
+
+	// the token expires in 1 second, so it has to be renewed before each request
+	serverAssertion.At(1, func(r http.Request) {
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+	})
+	serverAssertion.At(3, func(r http.Request) {
+		assert.Equal(t, "Bearer token-2", r.Header.Get("Authorization"))
+	})
+}
+
+func TestShouldFailWhenOAuth2TokenCannotBeObtained(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		u,
+		client.WithOAuth2ClientCredentials(client.OAuth2ClientCredentials{
+			TokenURL: testServer.URL + "/token",
+			ClientID: "my-client",
+		}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL + "/home") //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Contains(t, err.Error(), "token endpoint replied with 401")
+
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestShouldRenewTheBearerTokenAndRetryOnceWhenReceiving401(t *testing.T) {
+	tokenCounter := 0
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				tokenCounter++
+
+				_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, tokenCounter)
+
+				return
+			}
+
+			if r.Header.Get("Authorization") == "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		u,
+		client.WithOAuth2ClientCredentials(client.OAuth2ClientCredentials{
+			TokenURL: testServer.URL + "/token",
+			ClientID: "my-client",
+		}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL + "/home")
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, 4, serverAssertion.Len())
+	serverAssertion.At(3, func(r http.Request) {
+		assert.Equal(t, "Bearer token-2", r.Header.Get("Authorization"))
+	})
+}
+
+func TestShouldRenewTheBearerTokenWhenTheJWTExpires(t *testing.T) {
+	tokenCounter := 0
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				tokenCounter++
+
+				claims := fmt.Sprintf(`{"exp":%d,"jti":"%d"}`, time.Now().Add(time.Second).Unix(), tokenCounter)
+				jwt := "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".sig"
+
+				_, _ = fmt.Fprintf(w, `{"access_token":"%s"}`, jwt)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		u,
+		client.WithOAuth2ClientCredentials(client.OAuth2ClientCredentials{
+			TokenURL: testServer.URL + "/token",
+			ClientID: "my-client",
+		}),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(testServer.URL + "/home")
+		assert.NoError(t, err)
+		res.Body.Close() //nolint:errcheck,gosec
+	}
+
+	assert.Equal(t, 2, tokenCounter)
+	assert.Equal(t, 4, serverAssertion.Len())
+}
+
+func TestShouldAuthenticateWithAPKCS12ClientCertificate(t *testing.T) {
+	testServer, serverAssertion := test.NewMTLSServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	certificate, err := client.LoadPKCS12Certificate("testdata/client.p12", "secret")
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		u,
+		client.WithClientCertificate(certificate),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		assert.Equal(t, "dirstalk-test-client", r.TLS.PeerCertificates[0].Subject.CommonName)
+	})
+}
+
+func TestShouldFailToLoadAPKCS12BundleWithTheWrongPassword(t *testing.T) {
+	_, err := client.LoadPKCS12Certificate("testdata/client.p12", "wrong")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode pkcs12 bundle")
+
+	_, err = client.LoadPKCS12Certificate("testdata/not_existing.p12", "secret")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read pkcs12 bundle")
+}
+
+func TestShouldAuthenticateWithAPEMClientCertificate(t *testing.T) {
+	testServer, serverAssertion := test.NewMTLSServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		keyPath    string
+		passphrase string
+	}{
+		{keyPath: "testdata/client.key"},
+		{keyPath: "testdata/client_encrypted.key", passphrase: "secret"},
+	}
+
+	for _, tc := range testCases {
+		certificate, err := client.LoadX509KeyPair("testdata/client.crt", tc.keyPath, tc.passphrase)
+		assert.NoError(t, err)
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			true,
+			test.MustParseURL(t, testServer.URL),
+			client.WithClientCertificate(certificate),
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(testServer.URL)
+		assert.NoError(t, err)
+		res.Body.Close() //nolint:errcheck,gosec
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	assert.Equal(t, 2, serverAssertion.Len())
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		assert.Equal(t, "dirstalk-test-client", r.TLS.PeerCertificates[0].Subject.CommonName)
+	})
+}
+
+func TestShouldFailToLoadAnInvalidPEMClientCertificate(t *testing.T) {
+	testCases := []struct {
+		certPath      string
+		keyPath       string
+		passphrase    string
+		expectedError string
+	}{
+		{
+			certPath:      "testdata/client.crt",
+			keyPath:       "testdata/client_encrypted.key",
+			passphrase:    "wrong",
+			expectedError: "failed to decrypt private key testdata/client_encrypted.key",
+		},
+		{
+			certPath:      "testdata/client.crt",
+			keyPath:       "testdata/client_encrypted.key",
+			expectedError: "failed to load key pair",
+		},
+		{
+			certPath:      "testdata/client.crt",
+			keyPath:       "testdata/client.key",
+			passphrase:    "secret",
+			expectedError: "the key is not encrypted using the legacy PEM encryption",
+		},
+		{
+			certPath:      "testdata/not_existing.crt",
+			keyPath:       "testdata/client.key",
+			expectedError: "failed to read certificate testdata/not_existing.crt",
+		},
+		{
+			certPath:      "testdata/client.crt",
+			keyPath:       "testdata/not_existing.key",
+			expectedError: "failed to read private key testdata/not_existing.key",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := client.LoadX509KeyPair(tc.certPath, tc.keyPath, tc.passphrase)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), tc.expectedError)
+	}
+}
+
+func TestShouldTrustTheCertificatesSignedByACustomCA(t *testing.T) {
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, false, nil)
+	assert.NoError(t, err)
+
+	_, err = c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	pool, err := client.LoadCertPool(writeCABundle(t, testServer))
+	assert.NoError(t, err)
+
+	c, err = client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithRootCAs(pool),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestShouldSkipTheCertificateValidationOnlyForTheGivenHosts(t *testing.T) {
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	u := test.MustParseURL(t, testServer.URL)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		u,
+		client.WithInsecureSkipVerifyHosts([]string{"LOCALHOST"}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get("https://localhost:" + u.Port())
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	_, err = c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestShouldSendTheProvidedServerNameInTheSNIExtension(t *testing.T) {
+	var serverNames []string
+
+	testServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	testServer.TLS = &tls.Config{ //nolint:gosec
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverNames = append(serverNames, hello.ServerName)
+
+			return nil, nil
+		},
+	}
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	pool, err := client.LoadCertPool(writeCABundle(t, testServer))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		serverName    string
+		expectedError string
+	}{
+		{serverName: "example.com"},
+		{serverName: "other.example.org", expectedError: "certificate is valid for"},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			client.WithRootCAs(pool),
+			client.WithServerName(tc.serverName),
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(testServer.URL)
+		if tc.expectedError != "" {
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		res.Body.Close() //nolint:errcheck,gosec
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	assert.Equal(t, []string{"example.com", "other.example.org"}, serverNames)
+}
+
+func TestShouldFailToLoadAnInvalidCABundle(t *testing.T) {
+	_, err := client.LoadCertPool("testdata/client.key")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no certificate found in ca bundle testdata/client.key")
+
+	_, err = client.LoadCertPool("testdata/not_existing.crt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read ca bundle testdata/not_existing.crt")
+}
+
+func TestShouldMimicTheTLSFingerprintOfABrowser(t *testing.T) {
+	var clientHelloCipherSuites []uint16
+
+	testServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	testServer.TLS = &tls.Config{ //nolint:gosec
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			clientHelloCipherSuites = hello.CipherSuites
+
+			return nil, nil
+		},
+	}
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		u,
+		client.WithTLSFingerprint("chrome"),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	// chrome sends a GREASE value (RFC 8701) as first cipher suite, crypto/tls never does
+	assert.NotEmpty(t, clientHelloCipherSuites)
+	assert.Equal(t, uint16(0x0a0a), clientHelloCipherSuites[0]&0x0f0f)
+}
+
+func TestShouldFailToCreateAClientWithAnUnknownTLSFingerprint(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithTLSFingerprint("netscape"),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown tls fingerprint `netscape`")
+}
+
+func TestShouldSendTheClientHelloOfTheCustomJA3(t *testing.T) {
+	var clientHello *tls.ClientHelloInfo
+
+	testServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	testServer.TLS = &tls.Config{ //nolint:gosec
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			clientHello = hello
+
+			return nil, nil
+		},
+	}
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		u,
+		client.WithTLSFingerprint(client.TLSFingerprintCustom),
+		client.WithTLSJA3("771,4865-4866-4867-49195-49199-49196-49200,0-23-65281-10-11-35-16-5-13-18-51-45-43-21,29-23-24,0"),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(testServer.URL)
+		assert.NoError(t, err)
+		res.Body.Close() //nolint:errcheck,gosec
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	assert.Equal(t, []uint16{4865, 4866, 4867, 49195, 49199, 49196, 49200}, clientHello.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, clientHello.SupportedCurves)
+	assert.Equal(t, []uint8{0}, clientHello.SupportedPoints)
+	assert.Contains(t, clientHello.SupportedVersions, uint16(tls.VersionTLS13))
+
+	// the JA3 does not tell which protocols are advertised, the transport only speaks HTTP/1.x
+	assert.Equal(t, []string{"http/1.1"}, clientHello.SupportedProtos)
+}
+
+func TestShouldFailToCreateAClientWithAnInvalidJA3(t *testing.T) {
+	testCases := []struct {
+		opts          []client.Option
+		expectedError string
+	}{
+		{
+			opts:          []client.Option{client.WithTLSFingerprint(client.TLSFingerprintCustom)},
+			expectedError: "a ja3 is required by the `custom` tls fingerprint",
+		},
+		{
+			opts:          []client.Option{client.WithTLSFingerprint("chrome"), client.WithTLSJA3("771,4865,0,29,0")},
+			expectedError: "a ja3 is required by the `custom` tls fingerprint, and only by it",
+		},
+		{
+			opts: []client.Option{
+				client.WithTLSFingerprint(client.TLSFingerprintCustom),
+				client.WithTLSJA3("771,4865,0,29"),
+			},
+			expectedError: "must have 5 comma separated fields",
+		},
+		{
+			opts: []client.Option{
+				client.WithTLSFingerprint(client.TLSFingerprintCustom),
+				client.WithTLSJA3("771,4865-TLS_AES_128,0,29,0"),
+			},
+			expectedError: "`TLS_AES_128` is not a valid value",
+		},
+		{
+			opts: []client.Option{
+				client.WithTLSFingerprint(client.TLSFingerprintCustom),
+				client.WithTLSJA3("771,,0,29,0"),
+			},
+			expectedError: "must have a version and at least a cipher suite",
+		},
+		{
+			opts: []client.Option{
+				client.WithTLSFingerprint(client.TLSFingerprintCustom),
+				client.WithTLSJA3("771,4865,0-41,29,0"),
+			},
+			expectedError: "cannot contain the pre_shared_key extension",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, false, nil, tc.opts...)
+		assert.Nil(t, c)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	}
+}
+
+func TestShouldRefuseServersBelowTheTLSMinVersion(t *testing.T) {
+	testServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	testServer.TLS = &tls.Config{MaxVersion: tls.VersionTLS12} //nolint:gosec
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, true, u)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, tls.VersionTLS12, int(res.TLS.Version))
+
+	c, err = client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		u,
+		client.WithTLSMinVersion("1.3"),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol version")
+}
+
+func TestShouldNotOfferTLSVersionsAboveTheTLSMaxVersion(t *testing.T) {
+	testServer, _ := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		nil,
+		client.WithTLSMaxVersion("1.2"),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, tls.VersionTLS12, int(res.TLS.Version))
+
+	modernServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	modernServer.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	modernServer.StartTLS()
+	defer modernServer.Close()
+
+	_, err = c.Get(modernServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol version")
+}
+
+func TestShouldOfferOnlyTheProvidedTLSCipherSuites(t *testing.T) {
+	var clientHelloCipherSuites []uint16
+
+	testServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	testServer.TLS = &tls.Config{ //nolint:gosec
+		MaxVersion: tls.VersionTLS12,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			clientHelloCipherSuites = hello.CipherSuites
+
+			return nil, nil
+		},
+	}
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		true,
+		u,
+		client.WithTLSMinVersion("1.2"),
+		client.WithTLSCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Contains(t, clientHelloCipherSuites, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384)
+	assert.NotContains(t, clientHelloCipherSuites, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)
+	assert.Equal(t, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, res.TLS.CipherSuite)
+}
+
+func TestShouldFailToCreateAClientWithInvalidTLSSettings(t *testing.T) {
+	testCases := []struct {
+		opts          []client.Option
+		expectedError string
+	}{
+		{
+			opts:          []client.Option{client.WithTLSMinVersion("0.9")},
+			expectedError: "unknown tls version `0.9`",
+		},
+		{
+			opts:          []client.Option{client.WithTLSMaxVersion("1.4")},
+			expectedError: "unknown tls version `1.4`",
+		},
+		{
+			opts:          []client.Option{client.WithTLSMinVersion("1.2"), client.WithTLSMaxVersion("1.1")},
+			expectedError: "the tls max version cannot be lower than the tls min version",
+		},
+		{
+			opts:          []client.Option{client.WithTLSCipherSuites([]string{"TLS_NULL_WITH_NULL_NULL"})},
+			expectedError: "unknown tls cipher suite `TLS_NULL_WITH_NULL_NULL`",
+		},
+		{
+			opts:          []client.Option{client.WithTLSCipherSuites([]string{"TLS_AES_128_GCM_SHA256"})},
+			expectedError: "unknown tls cipher suite `TLS_AES_128_GCM_SHA256`",
+		},
+		{
+			opts: []client.Option{
+				client.WithTLSFingerprint("chrome"),
+				client.WithTLSCipherSuites([]string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}),
+			},
+			expectedError: "cannot be customized when using a tls fingerprint",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.expectedError, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, false, nil, tc.opts...)
+			assert.Nil(t, c)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestTLSVersionsAndCipherSuitesShouldBeListed(t *testing.T) {
+	assert.Equal(t, []string{"1.0", "1.1", "1.2", "1.3"}, client.TLSVersions())
+	assert.Contains(t, client.TLSCipherSuites(), "TLS_RSA_WITH_AES_128_CBC_SHA")
+	assert.NotContains(t, client.TLSCipherSuites(), "TLS_AES_128_GCM_SHA256")
+
+	assert.Equal(t, "1.2", client.TLSVersionName(tls.VersionTLS12))
+}
+
+func TestShouldUseTheProvidedDialerAndTransportDecorators(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	var dials int32
+
+	dialer := countingDialer{dialer: &net.Dialer{}, dials: &dials}
+
+	var decorated []string
+
+	decorator := func(name string) client.TransportDecorator {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				decorated = append(decorated, name)
+				r.Header.Set("X-Decorated-By", name)
+
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		test.MustParseURL(t, testServer.URL),
+		client.WithDialer(dialer),
+		client.WithTransportDecorator(decorator("inner")),
+		client.WithTransportDecorator(decorator("outer")),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials))
+	assert.Equal(t, []string{"outer", "inner"}, decorated)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "inner", r.Header.Get("X-Decorated-By"))
+	})
+}
+
+func TestShouldReachTheProxiesThroughTheProvidedDialer(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	httpProxy, httpProxyAssertion := test.NewHTTPConnectProxyWithAssertion()
+	defer httpProxy.Close()
+
+	var dials int32
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		test.MustParseURL(t, testServer.URL),
+		client.WithDialer(countingDialer{dialer: &net.Dialer{}, dials: &dials}),
+		client.WithProxyChain([]*url.URL{test.MustParseURL(t, httpProxy.URL)}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&dials))
+	assert.Equal(t, 1, httpProxyAssertion.Len())
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+type countingDialer struct {
+	dialer *net.Dialer
+	dials  *int32
+}
+
+func (c countingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	atomic.AddInt32(c.dials, 1)
+
+	return c.dialer.DialContext(ctx, network, addr)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	path := filepath.Join(t.TempDir(), "ca.crt")
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(path, bundle, 0600))
+
+	return path
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptedEncodings is the Accept-Encoding sent when the request does not have one already.
+const acceptedEncodings = "gzip, deflate, br"
+
+func decorateTransportWithDecompressionDecorator(decorated http.RoundTripper) (*decompressionTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	return &decompressionTransportDecorator{decorated: decorated}, nil
+}
+
+// decompressionTransportDecorator asks for compressed responses and decodes them, so that the bodies
+// and their sizes reflect the actual content. The responses decoded are flagged as Uncompressed,
+// their length is unknown.
+type decompressionTransportDecorator struct {
+	decorated http.RoundTripper
+}
+
+func (d *decompressionTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	// an Accept-Encoding chosen by the user is kept and the response is left untouched,
+	// as it happens with the transparent decompression of the transport
+	if r.Header.Get("Accept-Encoding") != "" {
+		return d.decorated.RoundTrip(r)
+	}
+
+	r.Header.Set("Accept-Encoding", acceptedEncodings)
+
+	res, err := d.decorated.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if body, decoded := decodeBody(res.Header.Get("Content-Encoding"), res.Body); decoded {
+		res.Body = body
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+		res.Uncompressed = true
+	}
+
+	return res, nil
+}
+
+// decodeBody returns a reader decoding the body according to its Content-Encoding, decoded is false
+// when the encoding is not supported.
+func decodeBody(contentEncoding string, body io.ReadCloser) (_ io.ReadCloser, decoded bool) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "gzip", "x-gzip":
+		return &lazyReadCloser{
+			body: body,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		}, true
+	case "deflate":
+		return &lazyReadCloser{body: body, newReader: newDeflateReader}, true
+	case "br":
+		return &lazyReadCloser{body: body, reader: brotli.NewReader(body)}, true
+	default:
+		return body, false
+	}
+}
+
+// newDeflateReader decodes a deflate body, the RFC wants it wrapped in the zlib format but some servers
+// send the raw deflate stream.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+
+	header, err := buffered.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+// lazyReadCloser decodes the body while it is read and closes the underlying one, readers needing to
+// read a header, like the gzip one, are only created on the first read, so that an empty body is not
+// an error unless it is actually read.
+type lazyReadCloser struct {
+	body      io.ReadCloser
+	reader    io.Reader
+	newReader func(io.Reader) (io.Reader, error)
+	err       error
+}
+
+func (l *lazyReadCloser) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	if l.reader == nil {
+		if l.reader, l.err = l.newReader(l.body); l.err != nil {
+			return 0, l.err
+		}
+	}
+
+	return l.reader.Read(p)
+}
+
+func (l *lazyReadCloser) Close() error {
+	return l.body.Close()
+}
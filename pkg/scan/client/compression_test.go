@@ -0,0 +1,162 @@
+package client_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+const compressedContent = "<html><body>the decoded content</body></html>"
+
+func TestShouldDecodeTheCompressedResponses(t *testing.T) {
+	encoders := map[string]struct {
+		contentEncoding string
+		newWriter       func(w io.Writer) io.WriteCloser
+	}{
+		"/gzip": {
+			contentEncoding: "gzip",
+			newWriter:       func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		},
+		"/deflate": {
+			contentEncoding: "deflate",
+			newWriter:       func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		},
+		"/raw-deflate": {
+			contentEncoding: "deflate",
+			newWriter: func(w io.Writer) io.WriteCloser {
+				writer, _ := flate.NewWriter(w, flate.DefaultCompression)
+
+				return writer
+			},
+		},
+		"/br": {
+			contentEncoding: "br",
+			newWriter:       func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		},
+	}
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoder, found := encoders[r.URL.Path]
+			if !found {
+				_, _ = w.Write([]byte(compressedContent))
+
+				return
+			}
+
+			buffer := &bytes.Buffer{}
+
+			writer := encoder.newWriter(buffer)
+			_, _ = writer.Write([]byte(compressedContent))
+			_ = writer.Close()
+
+			w.Header().Set("Content-Encoding", encoder.contentEncoding)
+			_, _ = w.Write(buffer.Bytes())
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+	)
+	assert.NoError(t, err)
+
+	for path := range encoders {
+		res, err := c.Get(testServer.URL + path)
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		assert.Equal(t, compressedContent, string(body), path)
+		assert.True(t, res.Uncompressed, path)
+		assert.Equal(t, int64(-1), res.ContentLength, path)
+		assert.Empty(t, res.Header.Get("Content-Encoding"), path)
+	}
+
+	res, err := c.Get(testServer.URL + "/identity")
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	assert.False(t, res.Uncompressed)
+	assert.Equal(t, int64(len(compressedContent)), res.ContentLength)
+
+	assert.Equal(t, len(encoders)+1, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Equal(t, "gzip, deflate, br", r.Header.Get("Accept-Encoding"))
+	})
+}
+
+func TestShouldNotDecodeTheResponsesWhenTheAcceptEncodingIsChosenOrCompressionIsDisabled(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte("not really brotli"))
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		headers                map[string]string
+		opts                   []client.Option
+		expectedAcceptEncoding string
+	}{
+		{
+			headers:                map[string]string{"Accept-Encoding": "br"},
+			expectedAcceptEncoding: "br",
+		},
+		{
+			opts: []client.Option{client.WithDisableCompression()},
+		},
+	}
+
+	for i, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			tc.headers,
+			false,
+			false,
+			nil,
+			tc.opts...,
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(testServer.URL)
+		assert.NoError(t, err)
+
+		body, err := ioutil.ReadAll(res.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+
+		assert.Equal(t, "not really brotli", string(body))
+		assert.Equal(t, "br", res.Header.Get("Content-Encoding"))
+
+		serverAssertion.At(i, func(r http.Request) {
+			assert.Equal(t, tc.expectedAcceptEncoding, r.Header.Get("Accept-Encoding"))
+		})
+	}
+}
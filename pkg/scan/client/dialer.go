@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	dialTimeout   = 30 * time.Second
+	dialKeepAlive = 30 * time.Second
+)
+
+// InterfaceAddr returns the address of the given network interface to use with WithLocalAddr,
+// IPv4 addresses are preferred over IPv6 ones.
+func InterfaceAddr(name string) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find network interface %s", name)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the addresses of network interface %s", name)
+	}
+
+	var candidate net.IP
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+
+		if candidate == nil {
+			candidate = ipNet.IP
+		}
+	}
+
+	if candidate == nil {
+		return nil, errors.Errorf("network interface %s has no usable address", name)
+	}
+
+	return candidate, nil
+}
+
+// newDirectDialer returns the dialer establishing the connections to the targets and to the proxies,
+// or nil when the default one of the transport can be used.
+func newDirectDialer(o options) (Dialer, error) {
+	customized := o.localAddr != nil ||
+		o.ipVersion != 0 ||
+		len(o.resolve) > 0 ||
+		o.dohResolver != nil ||
+		o.dnsCacheTTL > 0 ||
+		o.connectTimeout > 0
+	if !customized {
+		return nil, nil
+	}
+
+	if o.dialer != nil {
+		return nil, errors.New("the direct connections cannot be customized when using a custom dialer")
+	}
+
+	d := &directDialer{
+		dialer:  &net.Dialer{Timeout: dialTimeout, KeepAlive: dialKeepAlive},
+		resolve: make(map[string]string, len(o.resolve)),
+	}
+
+	for hostPort, ip := range o.resolve {
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid address to resolve `%s`", hostPort)
+		}
+
+		if net.ParseIP(ip) == nil {
+			return nil, errors.Errorf("invalid ip `%s` for the address `%s`", ip, hostPort)
+		}
+
+		d.resolve[net.JoinHostPort(strings.ToLower(host), port)] = net.JoinHostPort(ip, port)
+	}
+
+	if o.localAddr != nil {
+		d.dialer.LocalAddr = &net.TCPAddr{IP: o.localAddr}
+	}
+
+	if o.connectTimeout > 0 {
+		d.dialer.Timeout = o.connectTimeout
+	}
+
+	switch o.ipVersion {
+	case 0:
+	case 4:
+		d.network = "tcp4"
+	case 6:
+		d.network = "tcp6"
+	default:
+		return nil, errors.Errorf("unsupported ip version `%d`, valid values are 4 and 6", o.ipVersion)
+	}
+
+	if o.dohResolver != nil {
+		// the host of the endpoint cannot be resolved through the endpoint itself: it is resolved by the system,
+		// unless pinned with WithResolve
+		endpointDialer := *d
+
+		resolver, err := newDoHResolver(o.dohResolver, &endpointDialer, o.rootCAs)
+		if err != nil {
+			return nil, err
+		}
+
+		d.resolver = resolver
+	}
+
+	if o.dnsCacheTTL > 0 {
+		if d.resolver == nil {
+			d.resolver = systemResolver{}
+		}
+
+		d.resolver = newCachingResolver(d.resolver, o.dnsCacheTTL)
+	}
+
+	return d, nil
+}
+
+type directDialer struct {
+	dialer *net.Dialer
+	// network restricts the resolved addresses to a single family, eg: tcp4
+	network string
+	// resolve maps the host:port to connect to the ip:port to use instead of resolving the host
+	resolve map[string]string
+	// resolver resolves the hosts instead of the dialer when not nil
+	resolver hostResolver
+}
+
+func (d *directDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.network != "" && network == "tcp" {
+		network = d.network
+	}
+
+	if resolved, found := d.resolve[strings.ToLower(addr)]; found {
+		addr = resolved
+	}
+
+	if d.resolver != nil {
+		return d.dialResolved(ctx, network, addr)
+	}
+
+	return d.dialer.DialContext(ctx, network, addr)
+}
+
+// dialResolved connects to the first reachable address of the host returned by the resolver.
+func (d *directDialer) dialResolved(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address `%s`", addr)
+	}
+
+	if net.ParseIP(host) != nil {
+		return d.dialer.DialContext(ctx, network, addr)
+	}
+
+	ips, err := d.resolver.lookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+
+	for _, ip := range ips {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,232 @@
+package client_test
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldOriginateTheConnectionsFromTheLocalAddr(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithLocalAddr(net.ParseIP("127.0.0.1")),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		assert.NoError(t, err)
+		assert.Equal(t, "127.0.0.1", host)
+	})
+
+	// an address not assigned to any interface of the host cannot be used as source
+	c, err = client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithLocalAddr(net.ParseIP("192.0.2.1")),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestShouldFailToCreateAClientWithALocalAddrAndACustomDialer(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithLocalAddr(net.ParseIP("127.0.0.2")),
+		client.WithDialer(&net.Dialer{}),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the direct connections cannot be customized when using a custom dialer")
+}
+
+func TestInterfaceAddr(t *testing.T) {
+	ip, err := client.InterfaceAddr(test.LoopbackInterface(t))
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", ip.String())
+
+	_, err = client.InterfaceAddr("not-existing0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to find network interface not-existing0")
+}
+
+func TestShouldConnectOnlyToTheAddressesOfTheGivenIPVersion(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		ipVersion     int
+		expectedError string
+	}{
+		{ipVersion: 4},
+		{ipVersion: 6, expectedError: "no suitable address found"},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			client.WithIPVersion(tc.ipVersion),
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(testServer.URL)
+		if tc.expectedError != "" {
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+	}
+
+	assert.Equal(t, 1, serverAssertion.Len())
+}
+
+func TestShouldFailToCreateAClientWithAnUnsupportedIPVersion(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithIPVersion(5),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported ip version `5`")
+}
+
+func TestShouldConnectToTheResolvedAddressKeepingTheHostAndTheSNI(t *testing.T) {
+	testServer, serverAssertion := test.NewTSLServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Server-Name", r.TLS.ServerName)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	pool, err := client.LoadCertPool(writeCABundle(t, testServer))
+	assert.NoError(t, err)
+
+	port := test.MustParseURL(t, testServer.URL).Port()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithRootCAs(pool),
+		client.WithResolve(map[string]string{"EXAMPLE.com:" + port: "127.0.0.1"}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get("https://example.com:" + port + "/")
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "example.com", res.Header.Get("X-Server-Name"))
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "example.com:"+port, r.Host)
+	})
+}
+
+func TestShouldFailToCreateAClientWithAnInvalidResolve(t *testing.T) {
+	testCases := []struct {
+		resolve       map[string]string
+		expectedError string
+	}{
+		{
+			resolve:       map[string]string{"example.com": "127.0.0.1"},
+			expectedError: "invalid address to resolve `example.com`",
+		},
+		{
+			resolve:       map[string]string{"example.com:443": "localhost"},
+			expectedError: "invalid ip `localhost` for the address `example.com:443`",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			client.WithResolve(tc.resolve),
+		)
+		assert.Nil(t, c)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), tc.expectedError)
+	}
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"crypto/md5" //nolint:gosec
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DigestCredentials are the credentials used to answer the HTTP Digest authentication challenges (RFC 7616).
+type DigestCredentials struct {
+	Username string
+	Password string
+}
+
+func decorateTransportWithDigestAuthDecorator(
+	decorated http.RoundTripper,
+	credentials DigestCredentials,
+) (*digestAuthTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	return &digestAuthTransportDecorator{decorated: decorated, credentials: credentials}, nil
+}
+
+// digestAuthTransportDecorator answers the Digest challenges of the server, the last challenge is
+// reused for the following requests so that the server is not asked for a new one every time.
+type digestAuthTransportDecorator struct {
+	decorated   http.RoundTripper
+	credentials DigestCredentials
+
+	mx         sync.Mutex
+	challenge  *digestChallenge
+	nonceCount int
+}
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// RoundTrip authenticates the request with the current challenge, if the server replies with a new
+// challenge the request is retried once answering it.
+func (d *digestAuthTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if authorization, ok := d.authorization(r); ok {
+		r.Header.Set("Authorization", authorization)
+	}
+
+	res, err := d.decorated.RoundTrip(r)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || !canBeReplayed(r) {
+		return res, err
+	}
+
+	challenge, ok := parseDigestChallenge(res.Header.Values("WWW-Authenticate"))
+	if !ok {
+		return res, nil
+	}
+
+	d.mx.Lock()
+	d.challenge, d.nonceCount = challenge, 0
+	d.mx.Unlock()
+
+	authorization, ok := d.authorization(r)
+	if !ok {
+		return res, nil
+	}
+
+	retry := r.Clone(r.Context())
+	if r.GetBody != nil {
+		if retry.Body, err = r.GetBody(); err != nil {
+			return res, nil //nolint:nilerr
+		}
+	}
+
+	_ = res.Body.Close()
+
+	retry.Header.Set("Authorization", authorization)
+
+	return d.decorated.RoundTrip(retry)
+}
+
+// authorization returns the Authorization header answering the current challenge, ok is false when
+// no challenge was received yet or when it cannot be answered.
+func (d *digestAuthTransportDecorator) authorization(r *http.Request) (authorization string, ok bool) {
+	d.mx.Lock()
+	challenge := d.challenge
+	d.nonceCount++
+	nonceCount := d.nonceCount
+	d.mx.Unlock()
+
+	if challenge == nil {
+		return "", false
+	}
+
+	newHash := digestHash(challenge.algorithm)
+	if newHash == nil {
+		return "", false
+	}
+
+	hashHex := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return "", false
+	}
+
+	nc := fmt.Sprintf("%08x", nonceCount)
+	uri := r.URL.RequestURI()
+
+	ha1 := hashHex(d.credentials.Username, challenge.realm, d.credentials.Password)
+	if strings.HasSuffix(strings.ToLower(challenge.algorithm), "-sess") {
+		ha1 = hashHex(ha1, challenge.nonce, cnonce)
+	}
+
+	ha2 := hashHex(r.Method, uri)
+
+	fields := []string{
+		fmt.Sprintf("username=%q", d.credentials.Username),
+		fmt.Sprintf("realm=%q", challenge.realm),
+		fmt.Sprintf("nonce=%q", challenge.nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+
+	switch {
+	case challenge.qop == "":
+		// RFC 2069 compatibility
+		fields = append(fields, fmt.Sprintf("response=%q", hashHex(ha1, challenge.nonce, ha2)))
+	case hasToken(challenge.qop, "auth"):
+		fields = append(
+			fields,
+			"qop=auth",
+			"nc="+nc,
+			fmt.Sprintf("cnonce=%q", cnonce),
+			fmt.Sprintf("response=%q", hashHex(ha1, challenge.nonce, nc, cnonce, "auth", ha2)),
+		)
+	default:
+		// auth-int is the only other qop, it is not supported
+		return "", false
+	}
+
+	if challenge.algorithm != "" {
+		fields = append(fields, "algorithm="+challenge.algorithm)
+	}
+
+	if challenge.opaque != "" {
+		fields = append(fields, fmt.Sprintf("opaque=%q", challenge.opaque))
+	}
+
+	return "Digest " + strings.Join(fields, ", "), true
+}
+
+func digestHash(algorithm string) func() hash.Hash {
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "", "MD5":
+		return md5.New
+	case "SHA-256":
+		return sha256.New
+	default:
+		return nil
+	}
+}
+
+func newCnonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// parseDigestChallenge returns the first Digest challenge found in the WWW-Authenticate headers.
+func parseDigestChallenge(headers []string) (*digestChallenge, bool) {
+	for _, header := range headers {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(header), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+
+		values := parseAuthParams(params)
+
+		challenge := &digestChallenge{
+			realm:     values["realm"],
+			nonce:     values["nonce"],
+			opaque:    values["opaque"],
+			algorithm: values["algorithm"],
+			qop:       values["qop"],
+		}
+
+		if challenge.nonce == "" {
+			continue
+		}
+
+		return challenge, true
+	}
+
+	return nil, false
+}
+
+// parseAuthParams parses comma separated key=value pairs, the values can be quoted strings containing commas.
+func parseAuthParams(params string) map[string]string {
+	values := make(map[string]string)
+
+	for len(params) > 0 {
+		params = strings.TrimLeft(params, " ,")
+
+		key, rest, found := strings.Cut(params, "=")
+		if !found {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " ")
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			value, rest = readQuotedString(rest[1:])
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+
+		values[key] = value
+		params = rest
+	}
+
+	return values
+}
+
+// readQuotedString reads a quoted string up to its closing quote, the opening one being already consumed.
+func readQuotedString(s string) (value string, rest string) {
+	builder := strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				builder.WriteByte(s[i])
+			}
+		case '"':
+			return builder.String(), s[i+1:]
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+
+	return builder.String(), ""
+}
+
+func hasToken(list, token string) bool {
+	for _, t := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), token) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package client_test
+
+import (
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+var digestParamRegex = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|([^,\s]+))`)
+
+// newDigestHandler verifies the Digest authorization of the requests, replying with a challenge
+// when it is missing or wrong.
+func newDigestHandler(t *testing.T, algorithm string, newHash func() hash.Hash) http.HandlerFunc {
+	const (
+		realm    = "dirstalk, the realm"
+		nonce    = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+		opaque   = "5ccc069c403ebaf9f0171e9517f40e41"
+		password = "secret"
+	)
+
+	hashHex := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := make(map[string]string)
+		for _, match := range digestParamRegex.FindAllStringSubmatch(r.Header.Get("Authorization"), -1) {
+			params[match[1]] = match[2] + match[3]
+		}
+
+		ha1 := hashHex(params["username"], realm, password)
+		if strings.HasSuffix(algorithm, "-sess") {
+			ha1 = hashHex(ha1, nonce, params["cnonce"])
+		}
+
+		ha2 := hashHex(r.Method, r.URL.RequestURI())
+		expected := hashHex(ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2)
+
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Digest ") || params["response"] != expected {
+			w.Header().Set(
+				"WWW-Authenticate",
+				`Digest realm="`+realm+`", qop="auth,auth-int", algorithm=`+algorithm+
+					`, nonce="`+nonce+`", opaque="`+opaque+`"`,
+			)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		assert.Equal(t, opaque, params["opaque"])
+		assert.Equal(t, r.URL.RequestURI(), params["uri"])
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestShouldAnswerTheDigestChallenges(t *testing.T) {
+	testCases := []struct {
+		algorithm string
+		newHash   func() hash.Hash
+	}{
+		{algorithm: "MD5", newHash: md5.New},
+		{algorithm: "MD5-sess", newHash: md5.New},
+		{algorithm: "SHA-256", newHash: sha256.New},
+	}
+
+	for _, tc := range testCases {
+		testServer, serverAssertion := test.NewServerWithAssertion(newDigestHandler(t, tc.algorithm, tc.newHash))
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			true,
+			false,
+			nil,
+			client.WithDigestAuth(client.DigestCredentials{Username: "admin", Password: "secret"}),
+		)
+		assert.NoError(t, err)
+
+		for _, path := range []string{"/home", "/admin?debug=1"} {
+			res, err := c.Get(testServer.URL + path)
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+			assert.Equal(t, http.StatusNoContent, res.StatusCode, tc.algorithm)
+		}
+
+		// the challenge is asked only once and then reused, increasing the nonce count
+		assert.Equal(t, 3, serverAssertion.Len(), tc.algorithm)
+
+		serverAssertion.At(0, func(r http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"))
+		})
+		serverAssertion.At(1, func(r http.Request) {
+			assert.Contains(t, r.Header.Get("Authorization"), "nc=00000001")
+		})
+		serverAssertion.At(2, func(r http.Request) {
+			assert.Contains(t, r.Header.Get("Authorization"), "nc=00000002")
+		})
+
+		testServer.Close()
+	}
+}
+
+func TestShouldReturnTheChallengeWhenTheDigestCredentialsAreWrong(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(newDigestHandler(t, "MD5", md5.New))
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithDigestAuth(client.DigestCredentials{Username: "admin", Password: "wrong"}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL + "/home")
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, 2, serverAssertion.Len())
+}
+
+func TestShouldFailToCreateAClientWithDigestAuthAndOAuth2(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithDigestAuth(client.DigestCredentials{Username: "admin", Password: "secret"}),
+		client.WithOAuth2ClientCredentials(client.OAuth2ClientCredentials{TokenURL: "https://auth.example.com"}),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "digest auth cannot be used together with oauth2")
+}
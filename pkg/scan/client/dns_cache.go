@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostResolver returns the addresses of a host suitable for the given network, eg: tcp4.
+type hostResolver interface {
+	lookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+type systemResolver struct{}
+
+func (systemResolver) lookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	ipNetwork := "ip"
+
+	switch network {
+	case "tcp4":
+		ipNetwork = "ip4"
+	case "tcp6":
+		ipNetwork = "ip6"
+	}
+
+	return net.DefaultResolver.LookupIP(ctx, ipNetwork, host)
+}
+
+func newCachingResolver(resolver hostResolver, ttl time.Duration) *cachingResolver {
+	return &cachingResolver{resolver: resolver, ttl: ttl, entries: make(map[string]*dnsCacheEntry)}
+}
+
+// cachingResolver keeps the addresses returned by the decorated resolver for a fixed amount of time,
+// concurrent lookups of the same host wait for the first one instead of hitting the resolver again.
+type cachingResolver struct {
+	resolver hostResolver
+	ttl      time.Duration
+	mu       sync.Mutex
+	entries  map[string]*dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	// ready is closed once the lookup is completed, the other fields must not be read before
+	ready     chan struct{}
+	ips       []net.IP
+	err       error
+	expiresAt time.Time
+}
+
+func (c *cachingResolver) lookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	key := network + " " + strings.ToLower(host)
+
+	c.mu.Lock()
+
+	entry, found := c.entries[key]
+	if found && entry.isExpired() {
+		found = false
+	}
+
+	if found {
+		c.mu.Unlock()
+
+		select {
+		case <-entry.ready:
+			return entry.ips, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	entry = &dnsCacheEntry{ready: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.ips, entry.err = c.resolver.lookupIP(ctx, network, host)
+	entry.expiresAt = time.Now().Add(c.ttl)
+
+	close(entry.ready)
+
+	// the failures are not cached, the next lookup tries again
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	return entry.ips, entry.err
+}
+
+func (e *dnsCacheEntry) isExpired() bool {
+	select {
+	case <-e.ready:
+		return time.Now().After(e.expiresAt)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type resolverFunc func(ctx context.Context, network, host string) ([]net.IP, error)
+
+func (f resolverFunc) lookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return f(ctx, network, host)
+}
+
+func TestCachingResolverShouldResolveEveryHostOnceUntilExpired(t *testing.T) {
+	var lookups int32
+
+	resolver := newCachingResolver(
+		resolverFunc(func(ctx context.Context, network, host string) ([]net.IP, error) {
+			atomic.AddInt32(&lookups, 1)
+			time.Sleep(10 * time.Millisecond)
+
+			return []net.IP{net.ParseIP("127.0.0.1")}, nil
+		}),
+		50*time.Millisecond,
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ips, err := resolver.lookupIP(context.Background(), "tcp", "Example.com")
+			assert.NoError(t, err)
+			assert.Equal(t, "127.0.0.1", ips[0].String())
+		}()
+	}
+
+	wg.Wait()
+
+	_, err := resolver.lookupIP(context.Background(), "tcp", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lookups))
+
+	_, err = resolver.lookupIP(context.Background(), "tcp4", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&lookups))
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = resolver.lookupIP(context.Background(), "tcp", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&lookups))
+}
+
+func TestCachingResolverShouldNotCacheTheFailures(t *testing.T) {
+	lookups := 0
+
+	resolver := newCachingResolver(
+		resolverFunc(func(ctx context.Context, network, host string) ([]net.IP, error) {
+			lookups++
+
+			return nil, errors.New("no such host")
+		}),
+		time.Minute,
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := resolver.lookupIP(context.Background(), "tcp", "example.com")
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 2, lookups)
+}
@@ -0,0 +1,245 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	dohTimeout = 10 * time.Second
+	// dohMaxMessageSize is the maximum size of a DNS message, see RFC 8484.
+	dohMaxMessageSize = 65535
+)
+
+// dohResolver resolves host names through a DNS over HTTPS endpoint (RFC 8484), so that the queries
+// do not leak to the DNS servers of the system.
+type dohResolver struct {
+	endpoint *url.URL
+	client   *http.Client
+	mu       sync.Mutex
+	cache    map[string]dohCacheEntry
+}
+
+type dohCacheEntry struct {
+	ips       []net.IP
+	expiresAt time.Time
+}
+
+func newDoHResolver(endpoint *url.URL, dialer Dialer, rootCAs *x509.CertPool) (*dohResolver, error) {
+	if endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, errors.Errorf("the doh resolver must be an https url, got `%s`", endpoint.Redacted())
+	}
+
+	transport := buildTransport(&tls.Config{RootCAs: rootCAs}) //nolint:gosec
+	transport.DialContext = dialer.DialContext
+
+	return &dohResolver{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: dohTimeout, Transport: transport},
+		cache:    make(map[string]dohCacheEntry),
+	}, nil
+}
+
+// lookupIP returns the addresses of the host suitable for the given network, the IPv4 ones first.
+func (r *dohResolver) lookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	types := []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+
+	switch network {
+	case "tcp4":
+		types = types[:1]
+	case "tcp6":
+		types = types[1:]
+	}
+
+	var (
+		ips     []net.IP
+		lastErr error
+	)
+
+	for _, qtype := range types {
+		found, err := r.cachedLookup(ctx, host, qtype)
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		ips = append(ips, found...)
+	}
+
+	if len(ips) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+
+		return nil, errors.Errorf("doh resolver: no address found for %s", host)
+	}
+
+	return ips, nil
+}
+
+func (r *dohResolver) cachedLookup(ctx context.Context, host string, qtype dnsmessage.Type) ([]net.IP, error) {
+	key := qtype.String() + " " + strings.ToLower(host)
+
+	r.mu.Lock()
+	entry, found := r.cache[key]
+	r.mu.Unlock()
+
+	if found && time.Now().Before(entry.expiresAt) {
+		return entry.ips, nil
+	}
+
+	ips, ttl, err := r.lookup(ctx, host, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = dohCacheEntry{ips: ips, expiresAt: time.Now().Add(ttl)}
+	r.mu.Unlock()
+
+	return ips, nil
+}
+
+func (r *dohResolver) lookup(ctx context.Context, host string, qtype dnsmessage.Type) ([]net.IP, time.Duration, error) {
+	query, err := buildDNSQuery(host, qtype)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "doh resolver: failed to build the query for %s", host)
+	}
+
+	u := *r.endpoint
+	values := u.Query()
+	values.Set("dns", base64.RawURLEncoding.EncodeToString(query))
+	u.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "doh resolver: failed to build the request")
+	}
+
+	req.Header.Set("Accept", "application/dns-message")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "doh resolver: failed to resolve %s", host)
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, errors.Errorf("doh resolver: failed to resolve %s, endpoint replied with %d", host, res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, dohMaxMessageSize))
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "doh resolver: failed to read the answer for %s", host)
+	}
+
+	ips, ttl, err := parseDNSAnswer(body)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "doh resolver: failed to resolve %s", host)
+	}
+
+	return ips, ttl, nil
+}
+
+func buildDNSQuery(host string, qtype dnsmessage.Type) ([]byte, error) {
+	if !strings.HasSuffix(host, ".") {
+		host += "."
+	}
+
+	name, err := dnsmessage.NewName(host)
+	if err != nil {
+		return nil, err
+	}
+
+	// the ID is zero to make the answers cacheable by HTTP caches, see RFC 8484
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{RecursionDesired: true})
+	builder.EnableCompression()
+
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+
+	if err := builder.Question(dnsmessage.Question{Name: name, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+
+	return builder.Finish()
+}
+
+// parseDNSAnswer returns the addresses found in the answer, together with the time they can be cached for.
+func parseDNSAnswer(answer []byte) ([]net.IP, time.Duration, error) {
+	var parser dnsmessage.Parser
+
+	header, err := parser.Start(answer)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "invalid dns answer")
+	}
+
+	if header.RCode != dnsmessage.RCodeSuccess {
+		return nil, 0, errors.Errorf("dns answer code %s", header.RCode)
+	}
+
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, 0, errors.Wrap(err, "invalid dns answer")
+	}
+
+	var (
+		ips []net.IP
+		ttl uint32
+	)
+
+	for {
+		resourceHeader, err := parser.AnswerHeader()
+		if errors.Is(err, dnsmessage.ErrSectionDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "invalid dns answer")
+		}
+
+		switch resourceHeader.Type {
+		case dnsmessage.TypeA:
+			resource, err := parser.AResource()
+			if err != nil {
+				return nil, 0, errors.Wrap(err, "invalid dns answer")
+			}
+
+			ips = append(ips, net.IP(resource.A[:]))
+		case dnsmessage.TypeAAAA:
+			resource, err := parser.AAAAResource()
+			if err != nil {
+				return nil, 0, errors.Wrap(err, "invalid dns answer")
+			}
+
+			ips = append(ips, net.IP(resource.AAAA[:]))
+		default:
+			// eg: the CNAME records preceding the addresses
+			if err := parser.SkipAnswer(); err != nil {
+				return nil, 0, errors.Wrap(err, "invalid dns answer")
+			}
+
+			continue
+		}
+
+		if ttl == 0 || resourceHeader.TTL < ttl {
+			ttl = resourceHeader.TTL
+		}
+	}
+
+	return ips, time.Duration(ttl) * time.Second, nil
+}
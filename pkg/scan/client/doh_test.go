@@ -0,0 +1,120 @@
+package client_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldResolveTheHostsThroughTheDoHResolver(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	dohServer := test.NewDoHServer(t, map[string][]string{"scan.dirstalk.test": {"127.0.0.1"}})
+	defer dohServer.Close()
+
+	pool, err := client.LoadCertPool(writeCABundle(t, dohServer.Server))
+	assert.NoError(t, err)
+
+	port := test.MustParseURL(t, testServer.URL).Port()
+
+	testCases := []struct {
+		opts            []client.Option
+		expectedQueries []string
+	}{
+		{
+			expectedQueries: []string{"A scan.dirstalk.test.", "AAAA scan.dirstalk.test."},
+		},
+		{
+			opts:            []client.Option{client.WithIPVersion(4)},
+			expectedQueries: []string{"A scan.dirstalk.test."},
+		},
+	}
+
+	for _, tc := range testCases {
+		caseDoHServer := test.NewDoHServer(t, map[string][]string{"scan.dirstalk.test": {"127.0.0.1"}})
+
+		casePool, err := client.LoadCertPool(writeCABundle(t, caseDoHServer.Server))
+		assert.NoError(t, err)
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			append(
+				[]client.Option{
+					client.WithRootCAs(casePool),
+					client.WithDoHResolver(test.MustParseURL(t, caseDoHServer.URL())),
+				},
+				tc.opts...,
+			)...,
+		)
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			res, err := c.Get("http://scan.dirstalk.test:" + port + "/")
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+
+			// the connections are not reused, so that the host has to be resolved again
+			c.CloseIdleConnections()
+		}
+
+		// the answers are cached
+		assert.Equal(t, tc.expectedQueries, caseDoHServer.Queries())
+
+		caseDoHServer.Close()
+	}
+
+	assert.Equal(t, 4, serverAssertion.Len())
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithRootCAs(pool),
+		client.WithDoHResolver(test.MustParseURL(t, dohServer.URL())),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.Get("http://unknown.dirstalk.test:" + port + "/") //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "doh resolver: failed to resolve unknown.dirstalk.test")
+}
+
+func TestShouldFailToCreateAClientWithAnInvalidDoHResolver(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithDoHResolver(test.MustParseURL(t, "http://1.1.1.1/dns-query")),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the doh resolver must be an https url")
+}
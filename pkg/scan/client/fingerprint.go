@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+	utls "github.com/refraction-networking/utls"
+)
+
+// TLSFingerprintCustom sends the ClientHello described by the JA3 given with WithTLSJA3.
+const TLSFingerprintCustom = "custom"
+
+var tlsFingerprints = map[string]utls.ClientHelloID{
+	"chrome":             utls.HelloChrome_Auto,
+	"firefox":            utls.HelloFirefox_Auto,
+	"safari":             utls.HelloSafari_Auto,
+	"edge":               utls.HelloEdge_Auto,
+	"ios":                utls.HelloIOS_Auto,
+	"random":             utls.HelloRandomizedNoALPN,
+	TLSFingerprintCustom: utls.HelloCustom,
+}
+
+// TLSFingerprints returns the names of the TLS fingerprints that can be mimicked.
+func TLSFingerprints() []string {
+	names := make([]string, 0, len(tlsFingerprints))
+	for name := range tlsFingerprints {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// useTLSFingerprint makes the transport perform TLS handshakes sending the same ClientHello
+// a real browser would send, instead of the one produced by crypto/tls which is trivial to identify.
+// The ClientHello only differs from the browser's by the protocols advertised in the ALPN extension,
+// see forceHTTP1ALPN.
+func useTLSFingerprint(transport *http.Transport, fingerprint string, rawJA3 string) error {
+	clientHelloID, found := tlsFingerprints[fingerprint]
+	if !found {
+		return errors.Errorf("unknown tls fingerprint `%s`, valid values are %v", fingerprint, TLSFingerprints())
+	}
+
+	if (fingerprint == TLSFingerprintCustom) != (rawJA3 != "") {
+		return errors.Errorf("a ja3 is required by the `%s` tls fingerprint, and only by it", TLSFingerprintCustom)
+	}
+
+	var customHello ja3
+
+	if rawJA3 != "" {
+		var err error
+		if customHello, err = parseJA3(rawJA3); err != nil {
+			return err
+		}
+	}
+
+	transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialContext(transport)(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		uConn := utls.UClient(conn, buildUTLSConfig(transport.TLSClientConfig, addr), clientHelloID)
+
+		if rawJA3 != "" {
+			if err := uConn.ApplyPreset(customHello.clientHelloSpec()); err != nil {
+				_ = conn.Close()
+
+				return nil, errors.Wrap(err, "failed to build the tls client hello of the ja3")
+			}
+		}
+
+		if err := forceHTTP1ALPN(uConn); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		handshakeCtx, cancel := tlsHandshakeContext(ctx, transport)
+		defer cancel()
+
+		if err := uConn.HandshakeContext(handshakeCtx); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		return uConn, nil
+	}
+
+	return nil
+}
+
+func buildUTLSConfig(tlsConfig *tls.Config, addr string) *utls.Config {
+	serverName, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		serverName = addr
+	}
+
+	config := &utls.Config{ServerName: serverName}
+
+	if tlsConfig == nil {
+		return config
+	}
+
+	if tlsConfig.ServerName != "" {
+		config.ServerName = tlsConfig.ServerName
+	}
+
+	config.InsecureSkipVerify = tlsConfig.InsecureSkipVerify
+	config.MinVersion = tlsConfig.MinVersion
+	config.MaxVersion = tlsConfig.MaxVersion
+	config.RootCAs = tlsConfig.RootCAs
+
+	for _, certificate := range tlsConfig.Certificates {
+		config.Certificates = append(
+			config.Certificates,
+			utls.Certificate{
+				Certificate: certificate.Certificate,
+				PrivateKey:  certificate.PrivateKey,
+				Leaf:        certificate.Leaf,
+			},
+		)
+	}
+
+	return config
+}
+
+// forceHTTP1ALPN restricts the protocols advertised by the ClientHello to http/1.1: browsers advertise h2
+// too, but the connection is handed over to a transport that only speaks HTTP/1.x.
+// The JA3 is left unchanged, as it only tells which extensions are sent, but the fingerprints taking the
+// protocols advertised into account, eg: JA4, differ from the browser's.
+func forceHTTP1ALPN(uConn *utls.UConn) error {
+	if err := uConn.BuildHandshakeState(); err != nil {
+		return errors.Wrap(err, "failed to build the tls client hello")
+	}
+
+	for _, extension := range uConn.Extensions {
+		if alpn, ok := extension.(*utls.ALPNExtension); ok {
+			alpn.AlpnProtocols = []string{"http/1.1"}
+		}
+	}
+
+	return errors.Wrap(uConn.BuildHandshakeState(), "failed to rebuild the tls client hello")
+}
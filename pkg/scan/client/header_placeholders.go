@@ -0,0 +1,111 @@
+package client
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maxRandomStringLength bounds the length of the strings generated by the {{randstr:N}} placeholder.
+const maxRandomStringLength = 1024
+
+const randomStringAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// dynamicPlaceholderRegex matches the placeholders expanded for every request:
+// {{uuid}} a random UUID v4, {{timestamp}} the current unix time in seconds,
+// {{counter}} the number of the request starting from 1, {{randstr:N}} a random alphanumeric string of length N.
+var dynamicPlaceholderRegex = regexp.MustCompile(`{{(uuid|timestamp|counter|randstr:(\d+))}}`)
+
+// hasDynamicPlaceholders tells whether the given header values contain placeholders to be expanded for
+// every request, eg: {{uuid}}.
+func hasDynamicPlaceholders(headers map[string]string) bool {
+	for _, value := range headers {
+		if dynamicPlaceholderRegex.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decorateTransportWithHeaderPlaceholdersDecorator(
+	decorated http.RoundTripper,
+) (*headerPlaceholdersTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	return &headerPlaceholdersTransportDecorator{decorated: decorated}, nil
+}
+
+// headerPlaceholdersTransportDecorator expands the dynamic placeholders found in the request headers,
+// see dynamicPlaceholderRegex.
+type headerPlaceholdersTransportDecorator struct {
+	decorated http.RoundTripper
+	counter   uint64
+}
+
+func (h *headerPlaceholdersTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	counter := strconv.FormatUint(atomic.AddUint64(&h.counter, 1), 10)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	expand := func(placeholder string) string {
+		match := dynamicPlaceholderRegex.FindStringSubmatch(placeholder)
+
+		switch match[1] {
+		case "uuid":
+			return randomUUID()
+		case "timestamp":
+			return timestamp
+		case "counter":
+			return counter
+		default:
+			length, err := strconv.Atoi(match[2])
+			if err != nil || length > maxRandomStringLength {
+				return placeholder
+			}
+
+			return randomString(length)
+		}
+	}
+
+	for key, values := range r.Header {
+		for i, value := range values {
+			r.Header[key][i] = dynamicPlaceholderRegex.ReplaceAllStringFunc(value, expand)
+		}
+	}
+
+	return h.decorated.RoundTrip(r)
+}
+
+func randomUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func randomString(length int) string {
+	b := make([]byte, length)
+	max := big.NewInt(int64(len(randomStringAlphabet)))
+
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return string(b[:i])
+		}
+
+		b[i] = randomStringAlphabet[n.Int64()]
+	}
+
+	return string(b)
+}
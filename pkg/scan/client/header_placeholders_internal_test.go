@@ -0,0 +1,91 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportHeaderPlaceholdersShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithHeaderPlaceholdersDecorator(nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestHasDynamicPlaceholders(t *testing.T) {
+	assert.True(t, hasDynamicPlaceholders(map[string]string{"X-Request-Id": "req-{{uuid}}"}))
+	assert.True(t, hasDynamicPlaceholders(map[string]string{"X-Nonce": "{{randstr:12}}"}))
+	assert.False(t, hasDynamicPlaceholders(map[string]string{"X-Api-Key": "{{api_key}}", "X-Nonce": "{{randstr}}"}))
+	assert.False(t, hasDynamicPlaceholders(nil))
+}
+
+func TestHeaderPlaceholdersShouldBeExpandedForEveryRequest(t *testing.T) {
+	var sent []http.Header
+
+	transport, err := decorateTransportWithHeaderPlaceholdersDecorator(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			sent = append(sent, r.Header.Clone())
+
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	before := time.Now().Unix()
+
+	for i := 0; i < 2; i++ {
+		r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		assert.NoError(t, err)
+
+		r.Header.Set("X-Request-Id", "{{uuid}}")
+		r.Header.Set("X-Nonce", "{{randstr:12}}-{{randstr:3}}")
+		r.Header.Set("X-Sequence", "{{counter}}@{{timestamp}}")
+		r.Header.Set("X-Api-Key", "{{api_key}}")
+
+		_, err = transport.RoundTrip(r)
+		assert.NoError(t, err)
+	}
+
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	for i, headers := range sent {
+		assert.Regexp(t, uuidRegex, headers.Get("X-Request-Id"))
+		assert.Regexp(t, `^[a-zA-Z0-9]{12}-[a-zA-Z0-9]{3}$`, headers.Get("X-Nonce"))
+		assert.Equal(t, "{{api_key}}", headers.Get("X-Api-Key"))
+
+		counter, timestamp, found := strings.Cut(headers.Get("X-Sequence"), "@")
+		assert.True(t, found)
+		assert.Equal(t, strconv.Itoa(i+1), counter)
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, unix, before)
+	}
+
+	assert.NotEqual(t, sent[0].Get("X-Request-Id"), sent[1].Get("X-Request-Id"))
+	assert.NotEqual(t, sent[0].Get("X-Nonce"), sent[1].Get("X-Nonce"))
+}
+
+func TestHeaderPlaceholdersShouldNotGenerateTooLongStrings(t *testing.T) {
+	transport, err := decorateTransportWithHeaderPlaceholdersDecorator(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			assert.Equal(t, "{{randstr:100000}}", r.Header.Get("X-Nonce"))
+
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+	)
+	assert.NoError(t, err)
+
+	r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+	assert.NoError(t, err)
+
+	r.Header.Set("X-Nonce", "{{randstr:100000}}")
+
+	_, err = transport.RoundTrip(r) //nolint:bodyclose
+	assert.NoError(t, err)
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/csv"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderPool holds rows of values used to populate the `{{column}}` placeholders found in the request
+// headers: every request uses the next row, starting again from the first one once all have been used.
+type HeaderPool struct {
+	// next is accessed atomically: it must stay the first field to be 64-bit aligned on 32-bit platforms
+	next      uint64
+	replacers []*strings.Replacer
+}
+
+// LoadHeaderPool reads a header pool from a CSV file, the first row of which contains the column names.
+func LoadHeaderPool(path string) (*HeaderPool, error) {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open header pool %s", path)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	pool, err := NewHeaderPool(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load header pool %s", path)
+	}
+
+	return pool, nil
+}
+
+// NewHeaderPool reads a header pool from CSV, the first row of which contains the column names.
+func NewHeaderPool(r io.Reader) (*HeaderPool, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("the header pool must contain the column names and at least one row")
+	}
+
+	columns := records[0]
+	pool := &HeaderPool{replacers: make([]*strings.Replacer, 0, len(records)-1)}
+
+	for _, row := range records[1:] {
+		oldNew := make([]string, 0, len(columns)*2)
+		for i, column := range columns {
+			oldNew = append(oldNew, "{{"+strings.TrimSpace(column)+"}}", row[i])
+		}
+
+		pool.replacers = append(pool.replacers, strings.NewReplacer(oldNew...))
+	}
+
+	return pool, nil
+}
+
+// Len returns the number of rows in the pool.
+func (p *HeaderPool) Len() int {
+	return len(p.replacers)
+}
+
+func (p *HeaderPool) nextReplacer() *strings.Replacer {
+	i := atomic.AddUint64(&p.next, 1) - 1
+
+	return p.replacers[i%uint64(len(p.replacers))]
+}
+
+func decorateTransportWithHeaderPoolDecorator(decorated http.RoundTripper, pool *HeaderPool) (*headerPoolTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if pool == nil || pool.Len() == 0 {
+		return nil, errors.New("header pool is empty")
+	}
+
+	return &headerPoolTransportDecorator{decorated: decorated, pool: pool}, nil
+}
+
+type headerPoolTransportDecorator struct {
+	decorated http.RoundTripper
+	pool      *HeaderPool
+}
+
+func (h *headerPoolTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	replacer := h.pool.nextReplacer()
+
+	for key, values := range r.Header {
+		for i, value := range values {
+			r.Header[key][i] = replacer.Replace(value)
+		}
+	}
+
+	return h.decorated.RoundTrip(r)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportHeaderPoolShouldFailWithNilDecorated(t *testing.T) {
+	pool, err := NewHeaderPool(strings.NewReader("key\nvalue"))
+	assert.NoError(t, err)
+
+	transport, err := decorateTransportWithHeaderPoolDecorator(nil, pool)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportHeaderPoolShouldFailWithNilPool(t *testing.T) {
+	transport, err := decorateTransportWithHeaderPoolDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestHeaderPoolShouldRequireAtLeastOneRow(t *testing.T) {
+	pool, err := NewHeaderPool(strings.NewReader("key,session\n"))
+	assert.Nil(t, pool)
+	assert.Error(t, err)
+}
+
+func TestHeaderPoolShouldFailForRowsWithADifferentNumberOfColumns(t *testing.T) {
+	pool, err := NewHeaderPool(strings.NewReader("key,session\nvalue\n"))
+	assert.Nil(t, pool)
+	assert.Error(t, err)
+}
+
+func TestHeaderPoolShouldRotateThroughTheRows(t *testing.T) {
+	pool, err := LoadHeaderPool("testdata/header_pool.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pool.Len())
+
+	var sent []string
+
+	transport, err := decorateTransportWithHeaderPoolDecorator(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			sent = append(sent, r.Header.Get("X-Api-Key")+"|"+r.Header.Get("Cookie"))
+
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		pool,
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		r, err := http.NewRequest(http.MethodGet, "http://localhost/", nil)
+		assert.NoError(t, err)
+
+		r.Header.Set("X-Api-Key", "{{api_key}}")
+		r.Header.Set("Cookie", "sid={{session}}; {{unknown}}")
+
+		res, err := transport.RoundTrip(r)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+	}
+
+	assert.Equal(
+		t,
+		[]string{
+			"key-1|sid=session-1; {{unknown}}",
+			"key-2|sid=session-2; {{unknown}}",
+			"key-1|sid=session-1; {{unknown}}",
+		},
+		sent,
+	)
+}
+
+func TestLoadHeaderPoolShouldFailForMissingFile(t *testing.T) {
+	pool, err := LoadHeaderPool("testdata/missing.csv")
+	assert.Nil(t, pool)
+	assert.Error(t, err)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
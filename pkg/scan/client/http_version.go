@@ -0,0 +1,222 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http2"
+)
+
+const (
+	HTTPVersion10 = "1.0"
+	HTTPVersion11 = "1.1"
+	HTTPVersion2  = "2"
+)
+
+// HTTPVersions returns the HTTP versions that can be forced.
+func HTTPVersions() []string {
+	return []string{HTTPVersion10, HTTPVersion11, HTTPVersion2}
+}
+
+// roundTripperForHTTPVersion returns a round tripper that uses exclusively the given HTTP version,
+// when the version is empty the transport is returned untouched.
+func roundTripperForHTTPVersion(transport *http.Transport, version string) (http.RoundTripper, error) {
+	switch version {
+	case "":
+		return transport, nil
+	case HTTPVersion10:
+		return &http10RoundTripper{transport: transport}, nil
+	case HTTPVersion11:
+		// a non-nil empty map disables HTTP/2
+		transport.TLSNextProto = map[string]func(authority string, c *tls.Conn) http.RoundTripper{}
+		transport.ForceAttemptHTTP2 = false
+
+		return transport, nil
+	case HTTPVersion2:
+		return buildHTTP2RoundTripper(transport)
+	default:
+		return nil, errors.Errorf("unsupported http version `%s`, valid values are %v", version, HTTPVersions())
+	}
+}
+
+func buildHTTP2RoundTripper(transport *http.Transport) (http.RoundTripper, error) {
+	if transport.DialTLSContext != nil {
+		return nil, errors.New("HTTP/2 cannot be forced when using a custom tls fingerprint")
+	}
+
+	if _, err := http2.ConfigureTransports(transport); err != nil {
+		return nil, errors.Wrap(err, "failed to configure HTTP/2")
+	}
+
+	// only advertising h2 makes the handshake fail against servers not supporting it,
+	// instead of silently falling back to HTTP/1.1
+	transport.TLSClientConfig.NextProtos = []string{http2.NextProtoTLS}
+
+	// HTTP/2 over cleartext (h2c) with prior knowledge
+	h2c := &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialContext(transport)(context.Background(), network, addr)
+		},
+	}
+
+	return &schemeRoundTripper{secure: transport, insecure: h2c}, nil
+}
+
+// schemeRoundTripper dispatches requests to a different round tripper depending on the URL scheme.
+type schemeRoundTripper struct {
+	secure   http.RoundTripper
+	insecure http.RoundTripper
+}
+
+func (s *schemeRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.URL.Scheme == "http" {
+		return s.insecure.RoundTrip(r)
+	}
+
+	return s.secure.RoundTrip(r)
+}
+
+// http10RoundTripper performs HTTP/1.0 requests, which net/http is not able to send, using a new
+// connection for each request.
+type http10RoundTripper struct {
+	transport *http.Transport
+}
+
+func (h *http10RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	conn, err := h.dial(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := r.Context().Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := writeHTTP10Request(conn, r); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(conn), r)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	res.Body = &connClosingBody{ReadCloser: res.Body, conn: conn}
+
+	return res, nil
+}
+
+func (h *http10RoundTripper) dial(r *http.Request) (net.Conn, error) {
+	addr := canonicalAddr(r)
+
+	if r.URL.Scheme != "https" {
+		return dialContext(h.transport)(r.Context(), "tcp", addr)
+	}
+
+	if h.transport.DialTLSContext != nil {
+		return h.transport.DialTLSContext(r.Context(), "tcp", addr)
+	}
+
+	conn, err := dialContext(h.transport)(r.Context(), "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{} //nolint:gosec
+	if h.transport.TLSClientConfig != nil {
+		tlsConfig = h.transport.TLSClientConfig.Clone()
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = r.URL.Hostname()
+	}
+
+	handshakeCtx, cancel := tlsHandshakeContext(r.Context(), h.transport)
+	defer cancel()
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func writeHTTP10Request(conn net.Conn, r *http.Request) error {
+	w := bufio.NewWriter(conn)
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s HTTP/1.0\r\nHost: %s\r\n", r.Method, r.URL.RequestURI(), host)
+
+	if err := r.Header.Write(w); err != nil {
+		return err
+	}
+
+	if r.ContentLength > 0 {
+		_, _ = fmt.Fprintf(w, "Content-Length: %d\r\n", r.ContentLength)
+	}
+
+	_, _ = w.WriteString("\r\n")
+
+	if r.Body != nil {
+		if _, err := w.ReadFrom(r.Body); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// connClosingBody closes the underlying connection once the body is closed.
+type connClosingBody struct {
+	io.ReadCloser
+	conn net.Conn
+}
+
+func (c *connClosingBody) Close() error {
+	err := c.ReadCloser.Close()
+
+	if connErr := c.conn.Close(); err == nil {
+		err = connErr
+	}
+
+	return err
+}
+
+func dialContext(transport *http.Transport) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if transport.DialContext != nil {
+		return transport.DialContext
+	}
+
+	return (&net.Dialer{}).DialContext
+}
+
+func canonicalAddr(r *http.Request) string {
+	if r.URL.Port() != "" {
+		return r.URL.Host
+	}
+
+	if strings.EqualFold(r.URL.Scheme, "https") {
+		return net.JoinHostPort(r.URL.Hostname(), "443")
+	}
+
+	return net.JoinHostPort(r.URL.Hostname(), "80")
+}
@@ -0,0 +1,104 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestShouldForceTheHTTPVersion(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	plainServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer plainServer.Close()
+
+	tlsServer := httptest.NewUnstartedServer(handler)
+	tlsServer.EnableHTTP2 = true
+	tlsServer.StartTLS()
+
+	defer tlsServer.Close()
+
+	testCases := []struct {
+		version       string
+		serverURL     string
+		expectedProto string
+	}{
+		{version: "", serverURL: plainServer.URL, expectedProto: "HTTP/1.1"},
+		{version: "", serverURL: tlsServer.URL, expectedProto: "HTTP/2.0"},
+		{version: client.HTTPVersion10, serverURL: plainServer.URL, expectedProto: "HTTP/1.0"},
+		{version: client.HTTPVersion10, serverURL: tlsServer.URL, expectedProto: "HTTP/1.0"},
+		{version: client.HTTPVersion11, serverURL: tlsServer.URL, expectedProto: "HTTP/1.1"},
+		{version: client.HTTPVersion2, serverURL: tlsServer.URL, expectedProto: "HTTP/2.0"},
+		{version: client.HTTPVersion2, serverURL: plainServer.URL, expectedProto: "HTTP/2.0"},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			true,
+			test.MustParseURL(t, tc.serverURL),
+			client.WithHTTPVersion(tc.version),
+		)
+		assert.NoError(t, err)
+
+		res, err := c.Get(tc.serverURL + "/home")
+		if !assert.NoError(t, err, tc.version, tc.serverURL) {
+			continue
+		}
+
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, tc.expectedProto, res.Header.Get("X-Proto"), tc.version, tc.serverURL)
+	}
+}
+
+func TestShouldFailToCreateAClientWithAnUnsupportedHTTPVersion(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithHTTPVersion("3"),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported http version `3`")
+}
+
+func TestShouldFailToForceHTTP2WithATLSFingerprint(t *testing.T) {
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithHTTPVersion(client.HTTPVersion2),
+		client.WithTLSFingerprint("chrome"),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	utls "github.com/refraction-networking/utls"
+)
+
+const (
+	ja3ExtensionSupportedVersions = 43
+	ja3ExtensionPreSharedKey      = 41
+)
+
+// ja3SignatureAlgorithms are sent in the signature algorithms extensions, the JA3 only tells whether they are sent.
+var ja3SignatureAlgorithms = []utls.SignatureScheme{
+	utls.ECDSAWithP256AndSHA256,
+	utls.PSSWithSHA256,
+	utls.PKCS1WithSHA256,
+	utls.ECDSAWithP384AndSHA384,
+	utls.PSSWithSHA384,
+	utls.PKCS1WithSHA384,
+	utls.PSSWithSHA512,
+	utls.PKCS1WithSHA512,
+}
+
+// ja3Extensions build the extensions of a JA3 by their ID, the other ones are sent empty.
+var ja3Extensions = map[uint16]func(j ja3) utls.TLSExtension{
+	0: func(ja3) utls.TLSExtension { return &utls.SNIExtension{} },
+	5: func(ja3) utls.TLSExtension { return &utls.StatusRequestExtension{} },
+	// the GREASE curves are replaced in place, so the curves are copied
+	10: func(j ja3) utls.TLSExtension {
+		return &utls.SupportedCurvesExtension{Curves: append([]utls.CurveID(nil), j.curves...)}
+	},
+	11: func(j ja3) utls.TLSExtension { return &utls.SupportedPointsExtension{SupportedPoints: j.pointFormats} },
+	13: func(ja3) utls.TLSExtension {
+		return &utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: ja3SignatureAlgorithms}
+	},
+	16: func(ja3) utls.TLSExtension { return &utls.ALPNExtension{AlpnProtocols: []string{"http/1.1"}} },
+	17: func(ja3) utls.TLSExtension { return &utls.StatusRequestV2Extension{} },
+	18: func(ja3) utls.TLSExtension { return &utls.SCTExtension{} },
+	21: func(ja3) utls.TLSExtension { return &utls.UtlsPaddingExtension{GetPaddingLen: utls.BoringPaddingStyle} },
+	23: func(ja3) utls.TLSExtension { return &utls.UtlsExtendedMasterSecretExtension{} },
+	27: func(ja3) utls.TLSExtension {
+		return &utls.UtlsCompressCertExtension{Algorithms: []utls.CertCompressionAlgo{utls.CertCompressionBrotli}}
+	},
+	28: func(ja3) utls.TLSExtension { return &utls.FakeRecordSizeLimitExtension{Limit: 0x4001} },
+	34: func(ja3) utls.TLSExtension {
+		return &utls.DelegatedCredentialsExtension{AlgorithmsSignature: ja3SignatureAlgorithms}
+	},
+	35: func(ja3) utls.TLSExtension { return &utls.SessionTicketExtension{} },
+	ja3ExtensionSupportedVersions: func(ja3) utls.TLSExtension {
+		return &utls.SupportedVersionsExtension{Versions: []uint16{utls.VersionTLS13, utls.VersionTLS12}}
+	},
+	45: func(ja3) utls.TLSExtension {
+		return &utls.PSKKeyExchangeModesExtension{Modes: []uint8{utls.PskModeDHE}}
+	},
+	50: func(ja3) utls.TLSExtension {
+		return &utls.SignatureAlgorithmsCertExtension{SupportedSignatureAlgorithms: ja3SignatureAlgorithms}
+	},
+	51: func(j ja3) utls.TLSExtension {
+		return &utls.KeyShareExtension{KeyShares: []utls.KeyShare{{Group: j.keyShareGroup()}}}
+	},
+	13172: func(ja3) utls.TLSExtension { return &utls.NPNExtension{} },
+	17513: func(ja3) utls.TLSExtension {
+		return &utls.ApplicationSettingsExtension{SupportedProtocols: []string{"http/1.1"}}
+	},
+	30032: func(ja3) utls.TLSExtension { return &utls.FakeChannelIDExtension{} },
+	65281: func(ja3) utls.TLSExtension {
+		return &utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateOnceAsClient}
+	},
+}
+
+// ja3 is a ClientHello described in the JA3 format: version,ciphers,extensions,curves,point formats, the values of
+// each field separated by dashes, eg: 771,4865-4866-4867,0-10-11-13-43-51,29-23-24,0.
+type ja3 struct {
+	version      uint16
+	cipherSuites []uint16
+	extensions   []uint16
+	curves       []utls.CurveID
+	pointFormats []uint8
+}
+
+func parseJA3(raw string) (ja3, error) {
+	fields := strings.Split(strings.TrimSpace(raw), ",")
+	if len(fields) != 5 {
+		return ja3{}, errors.Errorf("the ja3 `%s` must have 5 comma separated fields", raw)
+	}
+
+	values := make([][]uint16, 0, len(fields))
+
+	for _, field := range fields {
+		fieldValues, err := parseJA3Field(field)
+		if err != nil {
+			return ja3{}, errors.Wrapf(err, "invalid ja3 `%s`", raw)
+		}
+
+		values = append(values, fieldValues)
+	}
+
+	if len(values[0]) != 1 || len(values[1]) == 0 {
+		return ja3{}, errors.Errorf("the ja3 `%s` must have a version and at least a cipher suite", raw)
+	}
+
+	j := ja3{version: values[0][0], cipherSuites: values[1], extensions: values[2]}
+
+	for _, extension := range j.extensions {
+		// the resumption of a session cannot be mimicked without a session to resume
+		if extension == ja3ExtensionPreSharedKey {
+			return ja3{}, errors.Errorf("the ja3 `%s` cannot contain the pre_shared_key extension (41)", raw)
+		}
+	}
+
+	for _, curve := range values[3] {
+		if isGREASE(curve) {
+			curve = utls.GREASE_PLACEHOLDER
+		}
+
+		j.curves = append(j.curves, utls.CurveID(curve))
+	}
+
+	for _, pointFormat := range values[4] {
+		if pointFormat > 0xff {
+			return ja3{}, errors.Errorf("invalid point format %d in the ja3 `%s`", pointFormat, raw)
+		}
+
+		j.pointFormats = append(j.pointFormats, uint8(pointFormat))
+	}
+
+	return j, nil
+}
+
+func parseJA3Field(field string) ([]uint16, error) {
+	if field == "" {
+		return nil, nil
+	}
+
+	rawValues := strings.Split(field, "-")
+	values := make([]uint16, 0, len(rawValues))
+
+	for _, rawValue := range rawValues {
+		value, err := strconv.ParseUint(rawValue, 10, 16)
+		if err != nil {
+			return nil, errors.Errorf("`%s` is not a valid value", rawValue)
+		}
+
+		values = append(values, uint16(value))
+	}
+
+	return values, nil
+}
+
+// clientHelloSpec builds the ClientHello of the JA3, the extensions are stateful: every connection needs its own.
+func (j ja3) clientHelloSpec() *utls.ClientHelloSpec {
+	spec := &utls.ClientHelloSpec{
+		CipherSuites:       make([]uint16, 0, len(j.cipherSuites)),
+		CompressionMethods: []uint8{0},
+		Extensions:         make([]utls.TLSExtension, 0, len(j.extensions)),
+		TLSVersMin:         utls.VersionTLS10,
+		TLSVersMax:         j.version,
+	}
+
+	for _, cipherSuite := range j.cipherSuites {
+		if isGREASE(cipherSuite) {
+			cipherSuite = utls.GREASE_PLACEHOLDER
+		}
+
+		spec.CipherSuites = append(spec.CipherSuites, cipherSuite)
+	}
+
+	for _, extension := range j.extensions {
+		switch build, found := ja3Extensions[extension]; {
+		case isGREASE(extension):
+			spec.Extensions = append(spec.Extensions, &utls.UtlsGREASEExtension{})
+		case found:
+			spec.Extensions = append(spec.Extensions, build(j))
+		default:
+			spec.Extensions = append(spec.Extensions, &utls.GenericExtension{Id: extension})
+		}
+
+		// the versions are advertised by the extension, the one of the JA3 is the legacy version of the ClientHello
+		if extension == ja3ExtensionSupportedVersions {
+			spec.TLSVersMin, spec.TLSVersMax = 0, 0
+		}
+	}
+
+	return spec
+}
+
+// keyShareGroup is the curve the TLS 1.3 key share is sent for, X25519 unless the JA3 does not support it.
+func (j ja3) keyShareGroup() utls.CurveID {
+	for _, curve := range j.curves {
+		if curve == utls.X25519 {
+			return curve
+		}
+	}
+
+	for _, curve := range j.curves {
+		if !isGREASE(uint16(curve)) {
+			return curve
+		}
+	}
+
+	return utls.X25519
+}
+
+// isGREASE tells whether the value is one of the reserved by RFC 8701 to keep the servers tolerant, eg: 0x0a0a.
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTExpiry(t *testing.T) {
+	t.Parallel()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+	testCases := []struct {
+		token          string
+		expectedExpiry time.Time
+		expectedFound  bool
+	}{
+		{
+			token:          header + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":1700000000}`)) + ".sig",
+			expectedExpiry: time.Unix(1700000000, 0),
+			expectedFound:  true,
+		},
+		{
+			token: header + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"me"}`)) + ".sig",
+		},
+		{
+			token: header + ".not-base64!.sig",
+		},
+		{
+			token: "opaque-token",
+		},
+	}
+
+	for _, tc := range testCases {
+		expiry, found := jwtExpiry(tc.token)
+		assert.Equal(t, tc.expectedFound, found, tc.token)
+		assert.Equal(t, tc.expectedExpiry, expiry, tc.token)
+	}
+}
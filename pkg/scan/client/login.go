@@ -0,0 +1,422 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// loginMinInterval prevents the login from being performed for every request when the sessions it provides
+// do not work, eg: because the credentials are wrong.
+const loginMinInterval = time.Second
+
+// Login describes how to authenticate against the target and how to recognize the responses telling that
+// the session expired: when one of them is received the login is performed again and the request retried
+// with the new session.
+type Login struct {
+	method  string
+	url     *url.URL
+	headers map[string]string
+	body    string
+
+	// cookies are the names of the cookies set by the login to reuse, empty to reuse all of them
+	cookies          []string
+	headerExtractors []loginHeaderExtractor
+
+	expiredStatuses []int
+	expiredLocation *regexp.Regexp
+	expiredBody     *regexp.Regexp
+}
+
+// loginHeaderExtractor sets a header using what the regex captures from the body of the login response.
+type loginHeaderExtractor struct {
+	name     string
+	regex    *regexp.Regexp
+	template string
+}
+
+type rawLogin struct {
+	Request struct {
+		Method  string            `yaml:"method"`
+		URL     string            `yaml:"url"`
+		Headers map[string]string `yaml:"headers"`
+		Body    string            `yaml:"body"`
+	} `yaml:"request"`
+	Extract struct {
+		Cookies []string `yaml:"cookies"`
+		Headers map[string]struct {
+			Body  string `yaml:"body"`
+			Value string `yaml:"value"`
+		} `yaml:"headers"`
+	} `yaml:"extract"`
+	Expired struct {
+		Status   []int  `yaml:"status"`
+		Location string `yaml:"location"`
+		Body     string `yaml:"body"`
+	} `yaml:"expired"`
+}
+
+// LoadLogin reads a login definition from a yaml file, see NewLogin.
+func LoadLogin(path string) (*Login, error) {
+	raw, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read login %s", path)
+	}
+
+	login, err := NewLogin(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load login %s", path)
+	}
+
+	return login, nil
+}
+
+// NewLogin parses a login definition, eg:
+//
+//	request:
+//	  method: POST
+//	  url: https://example.com/login
+//	  headers:
+//	    Content-Type: application/x-www-form-urlencoded
+//	  body: username=admin&password=secret
+//	extract:
+//	  # the cookies set by the login to send with the requests, all of them when omitted
+//	  cookies: [PHPSESSID]
+//	  # the headers built out of the body of the login response, ${1} being the first group captured
+//	  headers:
+//	    Authorization:
+//	      body: '"token":"([^"]+)"'
+//	      value: Bearer ${1}
+//	expired:
+//	  # the session is expired when the response meets all the conditions specified
+//	  status: [302]
+//	  location: /login
+//	  body: '(?i)session expired'
+func NewLogin(raw []byte) (*Login, error) {
+	r := rawLogin{}
+	if err := yaml.Unmarshal(raw, &r); err != nil {
+		return nil, errors.Wrap(err, "failed to parse login")
+	}
+
+	u, err := url.Parse(r.Request.URL)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("the login url must be absolute: `%s`", r.Request.URL)
+	}
+
+	login := &Login{
+		method:          strings.ToUpper(r.Request.Method),
+		url:             u,
+		headers:         r.Request.Headers,
+		body:            r.Request.Body,
+		cookies:         r.Extract.Cookies,
+		expiredStatuses: r.Expired.Status,
+	}
+
+	if login.method == "" {
+		login.method = http.MethodPost
+	}
+
+	for name, extractor := range r.Extract.Headers {
+		regex, err := regexp.Compile(extractor.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regex to extract the header %s", name)
+		}
+
+		template := extractor.Value
+		if template == "" {
+			template = "${1}"
+		}
+
+		login.headerExtractors = append(
+			login.headerExtractors,
+			loginHeaderExtractor{name: http.CanonicalHeaderKey(name), regex: regex, template: template},
+		)
+	}
+
+	if r.Expired.Location != "" {
+		if login.expiredLocation, err = regexp.Compile(r.Expired.Location); err != nil {
+			return nil, errors.Wrap(err, "invalid regex for the location of the expired session")
+		}
+	}
+
+	if r.Expired.Body != "" {
+		if login.expiredBody, err = regexp.Compile(r.Expired.Body); err != nil {
+			return nil, errors.Wrap(err, "invalid regex for the body of the expired session")
+		}
+	}
+
+	if len(login.expiredStatuses) == 0 && login.expiredLocation == nil && login.expiredBody == nil {
+		return nil, errors.New("at least one condition telling that the session expired is required")
+	}
+
+	return login, nil
+}
+
+// isExpired checks whether the response tells that the session expired, the body being read only when
+// a condition on it is set.
+func (l *Login) isExpired(res *http.Response) bool {
+	if len(l.expiredStatuses) > 0 && !containsStatus(l.expiredStatuses, res.StatusCode) {
+		return false
+	}
+
+	if l.expiredLocation != nil && !l.expiredLocation.MatchString(res.Header.Get("Location")) {
+		return false
+	}
+
+	if l.expiredBody == nil {
+		return true
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxLoginBodySize))
+
+	// what was read is given back to the caller
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+
+	return err == nil && l.expiredBody.Match(body)
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxLoginBodySize bounds what is read from the login responses and from the responses inspected to
+// recognize the expired sessions.
+const maxLoginBodySize = 1 << 20
+
+// loginSession is what the login provided to authenticate the requests.
+type loginSession struct {
+	cookies []*http.Cookie
+	headers map[string]string
+	// worked is set once a response did not tell that the session expired
+	worked int32
+}
+
+func decorateTransportWithLoginDecorator(decorated http.RoundTripper, login *Login) (*loginTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if login == nil {
+		return nil, errors.New("login is nil")
+	}
+
+	return &loginTransportDecorator{decorated: decorated, login: login, session: &loginSession{}}, nil
+}
+
+// loginTransportDecorator authenticates the requests with the session obtained through the login, which
+// is performed again once the session expires.
+type loginTransportDecorator struct {
+	decorated http.RoundTripper
+	login     *Login
+
+	mx        sync.Mutex
+	session   *loginSession
+	loggedAt  time.Time
+	loginLock sync.Mutex
+}
+
+func (d *loginTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	session := d.currentSession()
+
+	res, err := d.decorated.RoundTrip(d.authenticate(r, session))
+	if err != nil || !canBeReplayed(r) {
+		return res, err
+	}
+
+	if !d.login.isExpired(res) {
+		atomic.StoreInt32(&session.worked, 1)
+
+		return res, nil
+	}
+
+	renewed, err := d.renew(r, session)
+	if err != nil {
+		_ = res.Body.Close()
+
+		return nil, err
+	}
+
+	if renewed == session {
+		return res, nil
+	}
+
+	retry := r.Clone(r.Context())
+	if r.GetBody != nil {
+		if retry.Body, err = r.GetBody(); err != nil {
+			return res, nil //nolint:nilerr
+		}
+	}
+
+	_ = res.Body.Close()
+
+	return d.decorated.RoundTrip(d.authenticate(retry, renewed))
+}
+
+func (d *loginTransportDecorator) currentSession() *loginSession {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	return d.session
+}
+
+// renew performs the login unless the expired session was already replaced by a concurrent request,
+// the session returned is the same as the expired one when the login was performed too recently.
+func (d *loginTransportDecorator) renew(r *http.Request, expired *loginSession) (*loginSession, error) {
+	d.loginLock.Lock()
+	defer d.loginLock.Unlock()
+
+	d.mx.Lock()
+	current, loggedAt := d.session, d.loggedAt
+	d.mx.Unlock()
+
+	if current != expired {
+		return current, nil
+	}
+
+	if atomic.LoadInt32(&expired.worked) == 0 && time.Since(loggedAt) < loginMinInterval {
+		return current, nil
+	}
+
+	session, err := d.performLogin(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mx.Lock()
+	d.session, d.loggedAt = session, time.Now()
+	d.mx.Unlock()
+
+	return session, nil
+}
+
+func (d *loginTransportDecorator) performLogin(r *http.Request) (*loginSession, error) {
+	var body io.Reader
+	if d.login.body != "" {
+		body = strings.NewReader(d.login.body)
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), d.login.method, d.login.url.String(), body)
+	if err != nil {
+		return nil, errors.Wrap(err, "login: failed to build the request")
+	}
+
+	for name, value := range d.login.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := d.decorated.RoundTrip(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "login: failed to perform the request to %s", d.login.url)
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	resBody, err := ioutil.ReadAll(io.LimitReader(res.Body, maxLoginBodySize))
+	if err != nil {
+		return nil, errors.Wrapf(err, "login: failed to read the response from %s", d.login.url)
+	}
+
+	session := &loginSession{headers: make(map[string]string, len(d.login.headerExtractors))}
+
+	for _, cookie := range res.Cookies() {
+		if len(d.login.cookies) == 0 || containsString(d.login.cookies, cookie.Name) {
+			session.cookies = append(session.cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+
+	for _, extractor := range d.login.headerExtractors {
+		match := extractor.regex.FindSubmatchIndex(resBody)
+		if match == nil {
+			return nil, errors.Errorf(
+				"login: the response from %s (%d) does not contain the value of the header %s",
+				d.login.url,
+				res.StatusCode,
+				extractor.name,
+			)
+		}
+
+		value := extractor.regex.Expand(nil, []byte(extractor.template), resBody, match)
+		session.headers[extractor.name] = string(value)
+	}
+
+	if len(session.cookies) == 0 && len(session.headers) == 0 {
+		return nil, errors.Errorf(
+			"login: the response from %s (%d) did not provide any session cookie",
+			d.login.url,
+			res.StatusCode,
+		)
+	}
+
+	return session, nil
+}
+
+// authenticate returns the request carrying the cookies and the headers of the session, the cookies
+// replace the ones already set having the same name.
+func (d *loginTransportDecorator) authenticate(r *http.Request, session *loginSession) *http.Request {
+	if len(session.cookies) == 0 && len(session.headers) == 0 {
+		return r
+	}
+
+	for name, value := range session.headers {
+		r.Header.Set(name, value)
+	}
+
+	if len(session.cookies) == 0 {
+		return r
+	}
+
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+
+	for _, cookie := range cookies {
+		if !hasCookie(session.cookies, cookie.Name) {
+			r.AddCookie(cookie)
+		}
+	}
+
+	for _, cookie := range session.cookies {
+		r.AddCookie(cookie)
+	}
+
+	return r
+}
+
+func hasCookie(cookies []*http.Cookie, name string) bool {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
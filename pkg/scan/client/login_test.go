@@ -0,0 +1,255 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSessionHandler serves the login, creating a new session every time, and redirects the other requests
+// to the login unless they carry the latest session; the session expires after the given amount of requests.
+func newSessionHandler(requestsPerSession int) http.HandlerFunc {
+	var (
+		mx       sync.Mutex
+		sessions int
+		served   int
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		if r.URL.Path == "/login" {
+			if r.Method != http.MethodPost || r.FormValue("password") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			sessions++
+			served = 0
+
+			http.SetCookie(w, &http.Cookie{Name: "tracking", Value: "abc"})
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: fmt.Sprint(sessions)})
+			_, _ = fmt.Fprintf(w, `{"token":"token-%d"}`, sessions)
+
+			return
+		}
+
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != fmt.Sprint(sessions) || served >= requestsPerSession {
+			w.Header().Set("Location", "/login?next="+r.URL.Path)
+			w.WriteHeader(http.StatusFound)
+
+			return
+		}
+
+		served++
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newLogin(t *testing.T, serverURL string, extract string) *client.Login {
+	login, err := client.NewLogin([]byte(`
+request:
+  url: ` + serverURL + `/login
+  headers:
+    Content-Type: application/x-www-form-urlencoded
+  body: username=admin&password=secret
+` + extract + `
+expired:
+  status: [302]
+  location: ^/login
+`))
+	assert.NoError(t, err)
+
+	return login
+}
+
+func TestShouldLoginAgainWhenTheSessionExpires(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(newSessionHandler(2))
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		map[string]string{"Cookie": "preferences=dark"},
+		false,
+		false,
+		nil,
+		client.WithLogin(newLogin(t, testServer.URL, "extract:\n  cookies: [session]")),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		res, err := c.Get(testServer.URL + "/admin")
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	// 3 logins, each one following a redirect to the login page, plus the requests served
+	assert.Equal(t, 3+3+5, serverAssertion.Len())
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		if r.URL.Path == "/login" {
+			return
+		}
+
+		assert.Contains(t, r.Header.Get("Cookie"), "preferences=dark")
+		assert.NotContains(t, r.Header.Get("Cookie"), "tracking")
+	})
+}
+
+func TestShouldAuthenticateWithTheHeadersExtractedByTheLogin(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/login" {
+				_, _ = w.Write([]byte(`{"token":"abc.def"}`))
+
+				return
+			}
+
+			if r.Header.Get("Authorization") != "Bearer abc.def" {
+				w.Header().Set("Location", "/login")
+				w.WriteHeader(http.StatusFound)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	extract := `
+extract:
+  headers:
+    Authorization:
+      body: '"token":"([^"]+)"'
+      value: Bearer ${1}`
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithLogin(newLogin(t, testServer.URL, extract)),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(testServer.URL + "/api")
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	}
+
+	assert.Equal(t, 4, serverAssertion.Len())
+}
+
+func TestShouldNotLoginForEveryRequestWhenTheLoginDoesNotWork(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(newSessionHandler(0))
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithLogin(newLogin(t, testServer.URL, "")),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		res, err := c.Get(testServer.URL + "/admin")
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusFound, res.StatusCode)
+	}
+
+	logins := 0
+
+	serverAssertion.Range(func(_ int, r http.Request) {
+		if r.URL.Path == "/login" {
+			logins++
+		}
+	})
+
+	assert.Equal(t, 1, logins)
+}
+
+func TestShouldFailTheRequestsWhenTheLoginFails(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(newSessionHandler(1))
+	defer testServer.Close()
+
+	login, err := client.NewLogin([]byte(`
+request:
+  url: ` + testServer.URL + `/login
+  body: password=wrong
+expired:
+  location: ^/login
+`))
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, false, nil, client.WithLogin(login))
+	assert.NoError(t, err)
+
+	_, err = c.Get(testServer.URL + "/admin") //nolint:bodyclose
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "did not provide any session cookie")
+	}
+}
+
+func TestNewLoginShouldFailForAnInvalidDefinition(t *testing.T) {
+	testCases := []struct {
+		definition    string
+		expectedError string
+	}{
+		{
+			definition:    "request:\n  url: /login\nexpired:\n  status: [302]",
+			expectedError: "the login url must be absolute",
+		},
+		{
+			definition:    "request:\n  url: http://localhost/login",
+			expectedError: "at least one condition telling that the session expired is required",
+		},
+		{
+			definition:    "request:\n  url: http://localhost/login\nexpired:\n  body: '('",
+			expectedError: "invalid regex for the body of the expired session",
+		},
+		{
+			definition:    "request: [",
+			expectedError: "failed to parse login",
+		},
+	}
+
+	for _, tc := range testCases {
+		login, err := client.NewLogin([]byte(tc.definition))
+		assert.Nil(t, login)
+
+		if assert.Error(t, err) {
+			assert.True(t, strings.Contains(err.Error(), tc.expectedError), err.Error())
+		}
+	}
+}
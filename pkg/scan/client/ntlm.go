@@ -0,0 +1,200 @@
+package client
+
+import (
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	ntlmssp "github.com/Azure/go-ntlmssp"
+	"github.com/pkg/errors"
+)
+
+// NTLMCredentials are the credentials used to answer the NTLM and Negotiate authentication challenges
+// of the server, eg: IIS using Windows authentication. The username can be in the DOMAIN\user or in the
+// user@domain format.
+// Negotiate is answered with NTLM messages, Kerberos is not supported.
+type NTLMCredentials struct {
+	Username string
+	Password string
+}
+
+const (
+	authSchemeNTLM      = "NTLM"
+	authSchemeNegotiate = "Negotiate"
+)
+
+// newNTLMAuthRoundTripper builds a round tripper authenticating the requests with NTLM.
+// NTLM authenticates the connection rather than the single request: every request is pinned to a copy of
+// the transport holding a single connection, so that the handshake messages are exchanged over the same
+// connection and the authenticated connection is kept alive for the following requests.
+func newNTLMAuthRoundTripper(transport *http.Transport, credentials NTLMCredentials) *ntlmAuthRoundTripper {
+	// the idle connections are kept up to the limit of the transport, as dropping an authenticated
+	// connection means performing the handshake again
+	size := transport.MaxIdleConnsPerHost
+	if size <= 0 {
+		size = transport.MaxIdleConns
+	}
+
+	if size <= 0 {
+		size = http.DefaultMaxIdleConnsPerHost
+	}
+
+	return &ntlmAuthRoundTripper{
+		transport:   transport,
+		credentials: credentials,
+		idle:        make(chan *http.Transport, size),
+	}
+}
+
+type ntlmAuthRoundTripper struct {
+	transport   *http.Transport
+	credentials NTLMCredentials
+	// idle holds the transports whose connection is not used by any request
+	idle chan *http.Transport
+}
+
+func (n *ntlmAuthRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport := n.acquire()
+
+	res, err := n.roundTrip(transport, r)
+	if err != nil {
+		n.release(transport)
+
+		return nil, err
+	}
+
+	// the connection can be used by another request only once the response has been consumed
+	res.Body = &releasingReadCloser{ReadCloser: res.Body, release: func() { n.release(transport) }}
+
+	return res, nil
+}
+
+// roundTrip performs the request, if the server asks for NTLM authentication the handshake is performed
+// and the request is sent again.
+func (n *ntlmAuthRoundTripper) roundTrip(transport *http.Transport, r *http.Request) (*http.Response, error) {
+	res, err := transport.RoundTrip(r)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || !canBeReplayed(r) {
+		return res, err
+	}
+
+	scheme, _, found := ntlmChallengeFromServer(res.Header.Values("WWW-Authenticate"))
+	if !found {
+		return res, nil
+	}
+
+	discard(res)
+
+	user, domain, domainNeeded := ntlmssp.GetDomain(n.credentials.Username)
+
+	negotiate, err := ntlmssp.NewNegotiateMessage(domain, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create NTLM negotiate message")
+	}
+
+	res, err = n.send(transport, r, scheme+" "+base64.StdEncoding.EncodeToString(negotiate))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	_, challenge, found := ntlmChallengeFromServer(res.Header.Values("WWW-Authenticate"))
+	if !found || len(challenge) == 0 {
+		return res, nil
+	}
+
+	discard(res)
+
+	authenticate, err := ntlmssp.ProcessChallenge(challenge, user, n.credentials.Password, domainNeeded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to answer the NTLM challenge from %s", r.URL.Host)
+	}
+
+	return n.send(transport, r, scheme+" "+base64.StdEncoding.EncodeToString(authenticate))
+}
+
+func (n *ntlmAuthRoundTripper) send(
+	transport *http.Transport,
+	r *http.Request,
+	authorization string,
+) (*http.Response, error) {
+	req := r.Clone(r.Context())
+
+	if r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to replay the request body")
+		}
+
+		req.Body = body
+	}
+
+	req.Header.Set("Authorization", authorization)
+
+	return transport.RoundTrip(req)
+}
+
+func (n *ntlmAuthRoundTripper) acquire() *http.Transport {
+	select {
+	case transport := <-n.idle:
+		return transport
+	default:
+		transport := n.transport.Clone()
+		transport.MaxConnsPerHost = 1
+		transport.MaxIdleConnsPerHost = 1
+
+		return transport
+	}
+}
+
+func (n *ntlmAuthRoundTripper) release(transport *http.Transport) {
+	select {
+	case n.idle <- transport:
+	default:
+		transport.CloseIdleConnections()
+	}
+}
+
+// ntlmChallengeFromServer looks for the NTLM or Negotiate schemes in the WWW-Authenticate headers,
+// the NTLM one being preferred; challenge is empty when the server only announced the scheme.
+func ntlmChallengeFromServer(headers []string) (scheme string, challenge []byte, found bool) {
+	for _, candidate := range []string{authSchemeNTLM, authSchemeNegotiate} {
+		for _, header := range headers {
+			name, data, _ := strings.Cut(strings.TrimSpace(header), " ")
+			if !strings.EqualFold(name, candidate) {
+				continue
+			}
+
+			challenge, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+			if err != nil {
+				continue
+			}
+
+			return candidate, challenge, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// discard consumes and closes the body, so that the connection can be reused for the next message.
+func discard(res *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+}
+
+type releasingReadCloser struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+func (r *releasingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+
+	r.once.Do(r.release)
+
+	return err
+}
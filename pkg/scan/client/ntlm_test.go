@@ -0,0 +1,150 @@
+package client_test
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldAuthenticateTheConnectionsWithNTLM(t *testing.T) {
+	for _, scheme := range []string{"NTLM", "Negotiate"} {
+		testServer, serverAssertion := test.NewNTLMServerWithAssertion(
+			scheme,
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+		)
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			client.WithNTLMAuth(client.NTLMCredentials{Username: `CORP\admin`, Password: "secret"}),
+		)
+		assert.NoError(t, err)
+
+		for _, path := range []string{"/home", "/admin", "/login"} {
+			res, err := c.Get(testServer.URL + path)
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+			assert.Equal(t, http.StatusNoContent, res.StatusCode, scheme)
+		}
+
+		// the handshake happens once, the following requests reuse the authenticated connection
+		assert.Equal(t, 5, serverAssertion.Len(), scheme)
+
+		serverAssertion.At(1, func(r http.Request) {
+			assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), scheme+" "), scheme)
+		})
+		serverAssertion.At(4, func(r http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"), scheme)
+		})
+
+		testServer.Close()
+	}
+}
+
+func TestShouldKeepTheNTLMHandshakesOnTheirConnectionWithConcurrentRequests(t *testing.T) {
+	const (
+		workers  = 10
+		requests = 200
+	)
+
+	testServer, serverAssertion := test.NewNTLMServerWithAssertion(
+		"NTLM",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		5000,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithNTLMAuth(client.NTLMCredentials{Username: "admin@corp.local", Password: "secret"}),
+	)
+	assert.NoError(t, err)
+
+	jobs := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range jobs {
+				res, err := c.Get(testServer.URL)
+				if !assert.NoError(t, err) {
+					continue
+				}
+
+				assert.NoError(t, res.Body.Close())
+				assert.Equal(t, http.StatusNoContent, res.StatusCode)
+			}
+		}()
+	}
+
+	for i := 0; i < requests; i++ {
+		jobs <- struct{}{}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	// at most one handshake per connection, a connection per worker
+	assert.LessOrEqual(t, serverAssertion.Len(), requests+2*workers)
+}
+
+func TestShouldFailToCreateAClientWithNTLMAuthAndAnUnsupportedConfiguration(t *testing.T) {
+	testCases := []struct {
+		opts          []client.Option
+		expectedError string
+	}{
+		{
+			opts:          []client.Option{client.WithHTTPVersion(client.HTTPVersion2)},
+			expectedError: "ntlm auth can only be used with HTTP/1.1",
+		},
+		{
+			opts:          []client.Option{client.WithDisableKeepAlives()},
+			expectedError: "ntlm auth cannot be used with keep-alive disabled",
+		},
+		{
+			opts:          []client.Option{client.WithDigestAuth(client.DigestCredentials{Username: "admin"})},
+			expectedError: "ntlm auth cannot be used together with oauth2, digest auth",
+		},
+	}
+
+	for _, tc := range testCases {
+		opts := append(
+			[]client.Option{client.WithNTLMAuth(client.NTLMCredentials{Username: "admin", Password: "secret"})},
+			tc.opts...,
+		)
+
+		c, err := client.NewClientFromConfig(1500, nil, "", false, nil, nil, false, false, nil, opts...)
+		assert.Nil(t, c)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	}
+}
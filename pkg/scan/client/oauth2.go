@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OAuth2ClientCredentials contains what is needed to obtain an access token
+// through the OAuth2 client credentials grant (RFC 6749, section 4.4).
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+func newOAuth2ClientCredentialsTokenSource(
+	credentials OAuth2ClientCredentials,
+	doer *http.Client,
+) *oauth2ClientCredentialsTokenSource {
+	return &oauth2ClientCredentialsTokenSource{credentials: credentials, doer: doer}
+}
+
+type oauth2ClientCredentialsTokenSource struct {
+	credentials OAuth2ClientCredentials
+	doer        *http.Client
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (o *oauth2ClientCredentialsTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	if len(o.credentials.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.credentials.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		o.credentials.TokenURL,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "oauth2: failed to build token request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(o.credentials.ClientID), url.QueryEscape(o.credentials.ClientSecret))
+
+	res, err := o.doer.Do(req)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "oauth2: failed to fetch token")
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "oauth2: failed to read token response")
+	}
+
+	if res.StatusCode/100 != 2 {
+		return "", time.Time{}, errors.Errorf("oauth2: token endpoint replied with %d: %s", res.StatusCode, body)
+	}
+
+	tokenResponse := oauth2TokenResponse{}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", time.Time{}, errors.Wrap(err, "oauth2: failed to decode token response")
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return "", time.Time{}, errors.New("oauth2: token response does not contain an access_token")
+	}
+
+	var expiry time.Time
+	if tokenResponse.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return tokenResponse.AccessToken, expiry, nil
+}
@@ -0,0 +1,404 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
+)
+
+// Option allows to customize the client built by NewClientFromConfig.
+type Option func(*options)
+
+// Scope decides whether a URL can be requested by the client.
+type Scope interface {
+	InScope(u *url.URL) bool
+}
+
+// Dialer establishes the network connections used by the client.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// TransportDecorator wraps the round tripper performing the requests, eg: to add instrumentation.
+type TransportDecorator func(http.RoundTripper) http.RoundTripper
+
+type options struct {
+	scope                   Scope
+	oauth2ClientCredentials *OAuth2ClientCredentials
+	tokenCommand            string
+	digestCredentials       *DigestCredentials
+	ntlmCredentials         *NTLMCredentials
+	login                   *Login
+	awsSigV4                *AWSSigV4
+	clientCertificates      []tls.Certificate
+	rootCAs                 *x509.CertPool
+	insecureSkipVerifyHosts []string
+	serverName              string
+	tlsFingerprint          string
+	tlsJA3                  string
+	tlsMinVersion           string
+	tlsMaxVersion           string
+	tlsCipherSuites         []string
+	httpVersion             string
+	proxyChain              []*url.URL
+	httpProxy               *url.URL
+	proxyList               []*url.URL
+	proxyRotation           string
+	pac                     *PAC
+	torCircuitRenewal       *TorCircuitRenewal
+	proxyAuth               string
+	headerPool              *HeaderPool
+	userAgents              []string
+	maxBandwidth            int64
+	dialer                  Dialer
+	localAddr               net.IP
+	ipVersion               int
+	resolve                 map[string]string
+	dohResolver             *url.URL
+	dnsCacheTTL             time.Duration
+	connectTimeout          time.Duration
+	tlsHandshakeTimeout     time.Duration
+	responseHeaderTimeout   time.Duration
+	maxIdleConns            int
+	maxConnsPerHost         int
+	disableKeepAlives       bool
+	disableCompression      bool
+	transportDecorators     []TransportDecorator
+	requestCacheBloomFilter *bloom.Filter
+}
+
+func buildOptions(opts []Option) options {
+	o := options{}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithScope restricts every request performed by the client to the URLs accepted by the given scope,
+// requests pointing outside of it fail with ErrRequestOutOfScope.
+func WithScope(scope Scope) Option {
+	return func(o *options) {
+		o.scope = scope
+	}
+}
+
+// WithRequestCacheBloomFilter remembers the requests already performed, when caching them, with a bloom filter
+// sized for the given amount of requests instead of keeping all of them in memory. A request not performed
+// yet is skipped with the given probability, eg: 0.001, as it is wrongly told to be redundant.
+func WithRequestCacheBloomFilter(capacity uint, errorRate float64) Option {
+	return func(o *options) {
+		o.requestCacheBloomFilter = bloom.New(capacity, errorRate)
+	}
+}
+
+// WithOAuth2ClientCredentials makes the client obtain a bearer token through the OAuth2 client credentials
+// flow before performing requests, the token is renewed automatically once expired.
+func WithOAuth2ClientCredentials(credentials OAuth2ClientCredentials) Option {
+	return func(o *options) {
+		o.oauth2ClientCredentials = &credentials
+	}
+}
+
+// WithTokenCommand authenticates the requests with the bearer token printed by the given shell command,
+// the command is executed again to obtain a fresh token when the server replies with 401.
+func WithTokenCommand(command string) Option {
+	return func(o *options) {
+		o.tokenCommand = command
+	}
+}
+
+// WithDigestAuth answers the HTTP Digest authentication challenges of the server with the given credentials.
+func WithDigestAuth(credentials DigestCredentials) Option {
+	return func(o *options) {
+		o.digestCredentials = &credentials
+	}
+}
+
+// WithNTLMAuth answers the NTLM and Negotiate authentication challenges of the server with the given
+// credentials, see NTLMCredentials.
+func WithNTLMAuth(credentials NTLMCredentials) Option {
+	return func(o *options) {
+		o.ntlmCredentials = &credentials
+	}
+}
+
+// WithLogin authenticates the requests with the session obtained through the given login, which is
+// performed again whenever a response tells that the session expired, see NewLogin.
+func WithLogin(login *Login) Option {
+	return func(o *options) {
+		o.login = login
+	}
+}
+
+// WithAWSSigV4 signs every request performed by the client using AWS Signature Version 4.
+func WithAWSSigV4(sigV4 AWSSigV4) Option {
+	return func(o *options) {
+		o.awsSigV4 = &sigV4
+	}
+}
+
+// WithClientCertificate makes the client present the given certificate to servers requesting mutual TLS.
+func WithClientCertificate(certificate tls.Certificate) Option {
+	return func(o *options) {
+		o.clientCertificates = append(o.clientCertificates, certificate)
+	}
+}
+
+// WithRootCAs makes the client trust the servers presenting certificates signed by the given authorities,
+// see LoadCertPool.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(o *options) {
+		o.rootCAs = pool
+	}
+}
+
+// WithInsecureSkipVerifyHosts skips checking the validity of the certificates presented by the given hosts,
+// while still checking the ones of every other host.
+func WithInsecureSkipVerifyHosts(hosts []string) Option {
+	return func(o *options) {
+		o.insecureSkipVerifyHosts = hosts
+	}
+}
+
+// WithServerName sends the given name in the TLS SNI extension instead of the host of the request, eg: to
+// scan by IP a host behind a CDN. The certificates presented by the servers are verified against it.
+func WithServerName(serverName string) Option {
+	return func(o *options) {
+		o.serverName = serverName
+	}
+}
+
+// WithTLSFingerprint makes the client mimic the TLS ClientHello of the given browser, see TLSFingerprints.
+func WithTLSFingerprint(fingerprint string) Option {
+	return func(o *options) {
+		o.tlsFingerprint = fingerprint
+	}
+}
+
+// WithTLSJA3 sets the ClientHello sent with the TLSFingerprintCustom fingerprint, described in the JA3 format, eg:
+// 771,4865-4866-4867-49195-49199,0-10-11-13-16-43-45-51,29-23-24,0.
+func WithTLSJA3(ja3 string) Option {
+	return func(o *options) {
+		o.tlsJA3 = ja3
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version accepted by the client, see TLSVersions.
+func WithTLSMinVersion(version string) Option {
+	return func(o *options) {
+		o.tlsMinVersion = version
+	}
+}
+
+// WithTLSMaxVersion sets the maximum TLS version offered by the client, see TLSVersions.
+func WithTLSMaxVersion(version string) Option {
+	return func(o *options) {
+		o.tlsMaxVersion = version
+	}
+}
+
+// WithTLSCipherSuites restricts the cipher suites offered by the client for TLS 1.0-1.2, see TLSCipherSuites.
+func WithTLSCipherSuites(suites []string) Option {
+	return func(o *options) {
+		o.tlsCipherSuites = suites
+	}
+}
+
+// WithHTTPVersion forces the HTTP version used by the client, see HTTPVersions.
+func WithHTTPVersion(version string) Option {
+	return func(o *options) {
+		o.httpVersion = version
+	}
+}
+
+// WithProxyChain tunnels every connection through the given proxies, in order.
+func WithProxyChain(proxies []*url.URL) Option {
+	return func(o *options) {
+		o.proxyChain = proxies
+	}
+}
+
+// WithHTTPProxy sends every request through the given HTTP or HTTPS forward proxy, eg: an intercepting proxy
+// like Burp or ZAP. Credentials in the URL are sent using basic authentication.
+func WithHTTPProxy(proxyURL *url.URL) Option {
+	return func(o *options) {
+		o.httpProxy = proxyURL
+	}
+}
+
+// WithProxyRotation sends every request through one of the given proxies, picked using the given strategy
+// (see ProxyRotations). The proxies failing repeatedly are evicted from the rotation.
+func WithProxyRotation(proxies []*url.URL, strategy string) Option {
+	return func(o *options) {
+		o.proxyList = proxies
+		o.proxyRotation = strategy
+	}
+}
+
+// WithTorCircuitRenewal requests a new Tor circuit according to the given renewal, so that the requests
+// leave the Tor network from a different exit node.
+func WithTorCircuitRenewal(renewal TorCircuitRenewal) Option {
+	return func(o *options) {
+		o.torCircuitRenewal = &renewal
+	}
+}
+
+// WithPAC makes every request go through the proxies the proxy auto-config script returns for its URL.
+func WithPAC(pac *PAC) Option {
+	return func(o *options) {
+		o.pac = pac
+	}
+}
+
+// WithProxyAuth sets the scheme used to authenticate against HTTP proxies having credentials in their URL,
+// see ProxyAuths. Basic authentication is used by default.
+func WithProxyAuth(auth string) Option {
+	return func(o *options) {
+		o.proxyAuth = auth
+	}
+}
+
+// WithHeaderPool populates the `{{column}}` placeholders in the request headers with the next row of the pool.
+func WithHeaderPool(pool *HeaderPool) Option {
+	return func(o *options) {
+		o.headerPool = pool
+	}
+}
+
+// WithRandomUserAgents makes every request use a user agent picked at random from the given ones, instead of
+// the user agent passed to NewClientFromConfig; see DefaultUserAgents and LoadUserAgents.
+func WithRandomUserAgents(userAgents []string) Option {
+	return func(o *options) {
+		o.userAgents = userAgents
+	}
+}
+
+// WithMaxBandwidth caps the download rate of all the requests together to the given amount of bytes
+// per second.
+func WithMaxBandwidth(bytesPerSecond int64) Option {
+	return func(o *options) {
+		o.maxBandwidth = bytesPerSecond
+	}
+}
+
+// WithDialer makes the client establish every connection using the given dialer, eg: to route the traffic
+// through an overlay network. When proxies are configured, they are reached through the dialer.
+func WithDialer(dialer Dialer) Option {
+	return func(o *options) {
+		o.dialer = dialer
+	}
+}
+
+// WithLocalAddr makes every connection originate from the given address, eg: to choose the network interface
+// of a multi-homed host, see InterfaceAddr.
+func WithLocalAddr(ip net.IP) Option {
+	return func(o *options) {
+		o.localAddr = ip
+	}
+}
+
+// WithIPVersion makes the client connect only to the IPv4 (4) or IPv6 (6) addresses of the hosts, eg: when
+// the A and AAAA records of a dual-stack target point to different servers.
+func WithIPVersion(version int) Option {
+	return func(o *options) {
+		o.ipVersion = version
+	}
+}
+
+// WithResolve makes the client connect to the given IPs instead of resolving the host:port used as keys,
+// like curl --resolve: the Host header and the TLS SNI are not altered, eg: to reach an origin server behind a CDN.
+// The addresses reached through proxies are not affected.
+func WithResolve(resolve map[string]string) Option {
+	return func(o *options) {
+		o.resolve = resolve
+	}
+}
+
+// WithDoHResolver resolves the host names through the given DNS over HTTPS endpoint, eg: https://1.1.1.1/dns-query,
+// instead of the resolver of the system, avoiding DNS leaks. The host of the endpoint itself is resolved by the
+// system, unless its IP is used or it is pinned with WithResolve.
+func WithDoHResolver(endpoint *url.URL) Option {
+	return func(o *options) {
+		o.dohResolver = endpoint
+	}
+}
+
+// WithDNSCache keeps the addresses of the resolved hosts for the given amount of time, eg: to avoid resolving
+// the target for every connection when the keep-alive is disabled.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(o *options) {
+		o.dnsCacheTTL = ttl
+	}
+}
+
+// WithConnectTimeout limits the time spent establishing every connection, including the ones to the proxies.
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.connectTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout limits the time spent performing every TLS handshake, 10 seconds by default.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.tlsHandshakeTimeout = timeout
+	}
+}
+
+// WithResponseHeaderTimeout limits the time spent waiting for the headers of every response once the request
+// has been sent, the time spent reading the body is not affected.
+func WithResponseHeaderTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.responseHeaderTimeout = timeout
+	}
+}
+
+// WithMaxIdleConns sets how many idle connections are kept open for reuse for every host: the scans target
+// a single host, so a limit lower than the threads closes most of the connections once used, leaving
+// sockets in TIME_WAIT. By default only 2 connections for every host are reused.
+func WithMaxIdleConns(maxIdleConns int) Option {
+	return func(o *options) {
+		o.maxIdleConns = maxIdleConns
+	}
+}
+
+// WithMaxConnsPerHost limits the connections opened to every host, the requests exceeding it wait
+// for a connection to be available.
+func WithMaxConnsPerHost(maxConnsPerHost int) Option {
+	return func(o *options) {
+		o.maxConnsPerHost = maxConnsPerHost
+	}
+}
+
+// WithDisableKeepAlives uses every connection for a single request.
+func WithDisableKeepAlives() Option {
+	return func(o *options) {
+		o.disableKeepAlives = true
+	}
+}
+
+// WithDisableCompression does not ask for compressed responses, by default the gzip, deflate and brotli
+// ones are requested and decoded.
+func WithDisableCompression() Option {
+	return func(o *options) {
+		o.disableCompression = true
+	}
+}
+
+// WithTransportDecorator wraps the round tripper sending the requests with the given decorator.
+// Decorators are applied in order, before the ones used internally by the client, so they see the
+// requests exactly as they are sent: they must not alter requests signed with WithAWSSigV4.
+func WithTransportDecorator(decorator TransportDecorator) Option {
+	return func(o *options) {
+		o.transportDecorators = append(o.transportDecorators, decorator)
+	}
+}
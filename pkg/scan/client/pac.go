@@ -0,0 +1,303 @@
+package client
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robertkrimen/otto"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	// pacDownloadTimeout bounds the download of the proxy auto-config files served over http.
+	pacDownloadTimeout = 30 * time.Second
+	// maxPACSize bounds the size of the proxy auto-config files.
+	maxPACSize = 1 << 20
+	// pacEvaluationTimeout bounds the evaluation of the scripts, which could otherwise loop forever.
+	pacEvaluationTimeout = 5 * time.Second
+	// maxPACCallDepth prevents the recursive functions from exhausting the stack.
+	maxPACCallDepth = 64
+)
+
+var errPACEvaluationTimeout = errors.Errorf("the evaluation took longer than %s", pacEvaluationTimeout)
+
+// PAC is a proxy auto-config script, telling through its FindProxyForURL function which proxy has to be
+// used for every URL, eg: to route the traffic the way the browsers of a corporate network do.
+// The script is evaluated by otto, an ECMAScript 5 interpreter, while the PAC functions are implemented in Go.
+type PAC struct {
+	// the interpreter is not safe for concurrent use
+	mu sync.Mutex
+	vm *otto.Otto
+}
+
+// LoadPAC reads a proxy auto-config script from a file or from an http(s) URL.
+func LoadPAC(location string) (*PAC, error) {
+	var (
+		raw []byte
+		err error
+	)
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		raw, err = downloadPAC(location)
+	} else {
+		raw, err = os.ReadFile(location) // #nosec
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read proxy auto-config %s", location)
+	}
+
+	pac, err := NewPAC(string(raw))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load proxy auto-config %s", location)
+	}
+
+	return pac, nil
+}
+
+func downloadPAC(location string) ([]byte, error) {
+	c := &http.Client{Timeout: pacDownloadTimeout}
+
+	res, err := c.Get(location) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(res.Body, maxPACSize))
+}
+
+// NewPAC evaluates a proxy auto-config script, which must define the FindProxyForURL(url, host) function.
+func NewPAC(script string) (*PAC, error) {
+	vm := otto.New()
+	vm.Interrupt = make(chan func(), 1)
+	vm.SetStackDepthLimit(maxPACCallDepth)
+
+	for name, builtin := range pacBuiltins {
+		if err := vm.Set(name, builtin); err != nil {
+			return nil, errors.Wrapf(err, "failed to define %s", name)
+		}
+	}
+
+	p := &PAC{vm: vm}
+
+	if _, err := p.evaluate(func() (otto.Value, error) { return vm.Run(script) }); err != nil {
+		return nil, errors.Wrap(err, "invalid proxy auto-config")
+	}
+
+	findProxyForURL, err := vm.Get("FindProxyForURL")
+	if err != nil || !findProxyForURL.IsFunction() {
+		return nil, errors.New("the FindProxyForURL function is not defined")
+	}
+
+	return p, nil
+}
+
+// FindProxies returns the proxies to use for the given URL, in order of preference; a nil entry means that
+// the URL has to be requested directly. The PROXY and HTTPS proxies are used through the CONNECT method.
+func (p *PAC) FindProxies(u *url.URL) ([]*url.URL, error) {
+	value, err := p.evaluate(func() (otto.Value, error) {
+		return p.vm.Call("FindProxyForURL", nil, u.String(), u.Hostname())
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "FindProxyForURL failed for %s", u.Redacted())
+	}
+
+	if !value.IsString() {
+		return nil, errors.Errorf("FindProxyForURL did not return a string for %s", u.Redacted())
+	}
+
+	result := value.String()
+
+	proxies := make([]*url.URL, 0)
+
+	for _, entry := range strings.Split(result, ";") {
+		kind, address, _ := strings.Cut(strings.TrimSpace(entry), " ")
+		address = strings.TrimSpace(address)
+
+		var scheme string
+
+		switch strings.ToUpper(kind) {
+		case "":
+			continue
+		case "DIRECT":
+			proxies = append(proxies, nil)
+
+			continue
+		case "PROXY", "HTTP":
+			scheme = "http"
+		case "HTTPS":
+			scheme = "https"
+		case "SOCKS", "SOCKS5":
+			scheme = "socks5"
+		default:
+			// unsupported proxy types, eg: SOCKS4, are skipped as the browsers do
+			continue
+		}
+
+		if address == "" {
+			return nil, errors.Errorf("FindProxyForURL returned a proxy without address: `%s`", entry)
+		}
+
+		proxies = append(proxies, &url.URL{Scheme: scheme, Host: address})
+	}
+
+	if len(proxies) == 0 {
+		return nil, errors.Errorf(
+			"FindProxyForURL did not return any usable proxy for %s: `%s`",
+			u.Redacted(),
+			result,
+		)
+	}
+
+	return proxies, nil
+}
+
+// evaluate runs the given evaluation, interrupting it once pacEvaluationTimeout is elapsed.
+func (p *PAC) evaluate(evaluation func() (otto.Value, error)) (value otto.Value, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	done := make(chan struct{})
+	interrupted := make(chan struct{})
+
+	go func() {
+		defer close(interrupted)
+
+		select {
+		case <-done:
+		case <-time.After(pacEvaluationTimeout):
+			p.vm.Interrupt <- func() {
+				panic(errPACEvaluationTimeout)
+			}
+		}
+	}()
+
+	defer func() {
+		close(done)
+		<-interrupted
+
+		// an interruption sent while the evaluation was completing must not halt the next one
+		select {
+		case <-p.vm.Interrupt:
+		default:
+		}
+
+		if caught := recover(); caught != nil {
+			if caught != errPACEvaluationTimeout { //nolint:errorlint
+				panic(caught)
+			}
+
+			err = errPACEvaluationTimeout
+		}
+	}()
+
+	return evaluation()
+}
+
+// newPACRoundTripper builds a round tripper sending every request through the proxies the PAC script returns
+// for its URL, falling back on the next one when a proxy cannot be reached. Every proxy has its own transport,
+// created when the proxy is used for the first time.
+func newPACRoundTripper(transport *http.Transport, dialer proxy.Dialer, o options) (http.RoundTripper, error) {
+	direct, err := configureRoundTripper(transport.Clone(), o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pacRoundTripper{
+		pac:       o.pac,
+		transport: transport,
+		dialer:    dialer,
+		options:   o,
+		direct:    direct,
+		proxies:   make(map[string]http.RoundTripper),
+	}, nil
+}
+
+type pacRoundTripper struct {
+	pac       *PAC
+	transport *http.Transport
+	dialer    proxy.Dialer
+	options   options
+	direct    http.RoundTripper
+
+	mu      sync.Mutex
+	proxies map[string]http.RoundTripper
+}
+
+func (p *pacRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	proxies, err := p.pac.FindProxies(r.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, proxyURL := range proxies {
+		roundTripper, err := p.roundTripperFor(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+
+		req := r
+		if i > 0 && r.GetBody != nil {
+			req = r.Clone(r.Context())
+			if req.Body, err = r.GetBody(); err != nil {
+				return nil, errors.Wrap(err, "failed to replay the request body")
+			}
+		}
+
+		res, err := roundTripper.RoundTrip(req)
+
+		last := i == len(proxies)-1 || r.Context().Err() != nil || !canBeReplayed(r)
+		if err == nil || last {
+			if err != nil && proxyURL != nil {
+				return nil, errors.Wrapf(err, "request through proxy %s failed", proxyURL.Redacted())
+			}
+
+			return res, err
+		}
+	}
+
+	return nil, errors.New("no proxy available")
+}
+
+func (p *pacRoundTripper) roundTripperFor(proxyURL *url.URL) (http.RoundTripper, error) {
+	if proxyURL == nil {
+		return p.direct, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if roundTripper, found := p.proxies[proxyURL.String()]; found {
+		return roundTripper, nil
+	}
+
+	proxyDialer, err := newProxyChainDialer([]*url.URL{proxyURL}, p.options.proxyAuth, p.dialer)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyTransport := p.transport.Clone()
+	proxyTransport.DialContext = proxyDialer.DialContext
+
+	roundTripper, err := configureRoundTripper(proxyTransport, p.options)
+	if err != nil {
+		return nil, err
+	}
+
+	p.proxies[proxyURL.String()] = roundTripper
+
+	return roundTripper, nil
+}
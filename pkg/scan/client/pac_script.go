@@ -0,0 +1,129 @@
+package client
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/robertkrimen/otto"
+)
+
+// pacBuiltins are the functions every proxy auto-config script can use, the rest of the script is
+// evaluated by otto.
+var pacBuiltins = map[string]func(call otto.FunctionCall) otto.Value{
+	"isPlainHostName": func(call otto.FunctionCall) otto.Value {
+		return pacValue(!strings.Contains(call.Argument(0).String(), "."))
+	},
+	"dnsDomainIs": func(call otto.FunctionCall) otto.Value {
+		host, domain := strings.ToLower(call.Argument(0).String()), strings.ToLower(call.Argument(1).String())
+
+		return pacValue(strings.HasSuffix(host, domain))
+	},
+	"localHostOrDomainIs": func(call otto.FunctionCall) otto.Value {
+		host, hostDomain := strings.ToLower(call.Argument(0).String()), strings.ToLower(call.Argument(1).String())
+		if strings.Contains(host, ".") {
+			return pacValue(host == hostDomain)
+		}
+
+		return pacValue(strings.HasPrefix(hostDomain, host+".") || host == hostDomain)
+	},
+	"isResolvable": func(call otto.FunctionCall) otto.Value {
+		return pacValue(pacResolve(call.Argument(0).String()) != nil)
+	},
+	"dnsResolve": func(call otto.FunctionCall) otto.Value {
+		if ip := pacResolve(call.Argument(0).String()); ip != nil {
+			return pacValue(ip.String())
+		}
+
+		return otto.NullValue()
+	},
+	"isInNet": func(call otto.FunctionCall) otto.Value {
+		ip := pacResolve(call.Argument(0).String())
+		pattern, mask := net.ParseIP(call.Argument(1).String()).To4(), net.ParseIP(call.Argument(2).String()).To4()
+
+		if ip == nil || pattern == nil || mask == nil {
+			return otto.FalseValue()
+		}
+
+		return pacValue(ip.Mask(net.IPMask(mask)).Equal(pattern.Mask(net.IPMask(mask))))
+	},
+	"myIpAddress": func(call otto.FunctionCall) otto.Value {
+		return pacValue(myIPAddress())
+	},
+	"dnsDomainLevels": func(call otto.FunctionCall) otto.Value {
+		return pacValue(strings.Count(call.Argument(0).String(), "."))
+	},
+	"shExpMatch": func(call otto.FunctionCall) otto.Value {
+		return pacValue(shellExpressionRegex(call.Argument(1).String()).MatchString(call.Argument(0).String()))
+	},
+	"alert": func(call otto.FunctionCall) otto.Value {
+		return otto.UndefinedValue()
+	},
+}
+
+// pacValue converts the values returned by the builtins, which are all supported by otto.
+func pacValue(value interface{}) otto.Value {
+	v, err := otto.ToValue(value)
+	if err != nil {
+		return otto.UndefinedValue()
+	}
+
+	return v
+}
+
+// pacResolve returns the IPv4 address of the host, as the PAC functions are not IPv6 aware.
+func pacResolve(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4()
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil
+	}
+
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4
+		}
+	}
+
+	return nil
+}
+
+// myIPAddress returns the address of the interface used to reach the internet, without sending any packet.
+func myIPAddress() string {
+	conn, err := net.Dial("udp4", "198.51.100.1:53")
+	if err != nil {
+		return "127.0.0.1"
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		return addr.IP.String()
+	}
+
+	return "127.0.0.1"
+}
+
+// shellExpressionRegex converts the shell expressions used by shExpMatch, eg: *.example.com, to a regex.
+func shellExpressionRegex(expression string) *regexp.Regexp {
+	pattern := strings.Builder{}
+	pattern.WriteString("^")
+
+	for _, r := range expression {
+		switch r {
+		case '*':
+			pattern.WriteString(".*")
+		case '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String())
+}
@@ -0,0 +1,226 @@
+package client_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldFindTheProxiesUsingThePAC(t *testing.T) {
+	pac, err := client.LoadPAC("testdata/proxy.pac")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		url     string
+		proxies []string
+	}{
+		{url: "http://wiki/home", proxies: []string{"DIRECT"}},
+		{url: "https://HR.intranet.example.com/", proxies: []string{"DIRECT"}},
+		{url: "http://10.1.2.3:8080/", proxies: []string{"DIRECT"}},
+		{url: "http://legacy-01.example.com/", proxies: []string{"socks5://socks.example.com:1080"}},
+		{
+			url:     "https://legacy-01.example.com/",
+			proxies: []string{"http://proxy1.example.com:3128", "http://proxy2.example.com:3128"},
+		},
+		{
+			url:     "https://example.com/download/file.zip",
+			proxies: []string{"http://proxy1.example.com:3128", "http://proxy2.example.com:3128", "DIRECT"},
+		},
+	}
+
+	for _, tc := range testCases {
+		proxies, err := pac.FindProxies(test.MustParseURL(t, tc.url))
+		assert.NoError(t, err, tc.url)
+
+		actual := make([]string, 0, len(proxies))
+		for _, proxyURL := range proxies {
+			if proxyURL == nil {
+				actual = append(actual, "DIRECT")
+
+				continue
+			}
+
+			actual = append(actual, proxyURL.String())
+		}
+
+		assert.Equal(t, tc.proxies, actual, tc.url)
+	}
+}
+
+func TestShouldFailToLoadAnInvalidPAC(t *testing.T) {
+	testCases := []struct {
+		script string
+		err    string
+	}{
+		{script: `function findProxy(url, host) { return "DIRECT"; }`, err: "FindProxyForURL function is not defined"},
+		{script: `function FindProxyForURL(url, host) { return "DIRECT" + ; }`, err: "Line 1:"},
+		{script: `var proxy = "DIRECT`, err: "invalid proxy auto-config"},
+		{script: `var proxy = undefinedFunction();`, err: "'undefinedFunction' is not defined"},
+		{script: `var FindProxyForURL = "DIRECT";`, err: "FindProxyForURL function is not defined"},
+	}
+
+	for _, tc := range testCases {
+		pac, err := client.NewPAC(tc.script)
+		assert.Nil(t, pac, tc.script)
+		assert.Error(t, err, tc.script)
+		assert.Contains(t, err.Error(), tc.err, tc.script)
+	}
+
+	_, err := client.LoadPAC("testdata/not_existing.pac")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read proxy auto-config")
+}
+
+func TestShouldEvaluateThePACUsingTheWholeLanguage(t *testing.T) {
+	pac, err := client.NewPAC(`
+		var bypass = ["wiki", "hr.example.com"];
+		var routes = {internal: "DIRECT", external: "PROXY proxy.example.com:3128"};
+
+		function FindProxyForURL(url, host) {
+			for (var i = 0; i < bypass.length; i++) {
+				if (host === bypass[i]) {
+					return routes.internal;
+				}
+			}
+
+			if (/^[0-9.]+$/.test(host) && dnsDomainLevels(host) * 2 / 3 === 2) {
+				return routes.internal;
+			}
+
+			switch (url.split(":")[0]) {
+			case "https":
+				return routes.external;
+			default:
+				return "SOCKS5 socks.example.com:1080";
+			}
+		}
+	`)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		url   string
+		proxy string
+	}{
+		{url: "http://wiki/home", proxy: "DIRECT"},
+		{url: "https://hr.example.com/", proxy: "DIRECT"},
+		{url: "http://10.1.2.3/", proxy: "DIRECT"},
+		{url: "https://example.com/", proxy: "http://proxy.example.com:3128"},
+		{url: "http://example.com/", proxy: "socks5://socks.example.com:1080"},
+	}
+
+	for _, tc := range testCases {
+		proxies, err := pac.FindProxies(test.MustParseURL(t, tc.url))
+		assert.NoError(t, err, tc.url)
+		assert.Len(t, proxies, 1, tc.url)
+
+		actual := "DIRECT"
+		if proxies[0] != nil {
+			actual = proxies[0].String()
+		}
+
+		assert.Equal(t, tc.proxy, actual, tc.url)
+	}
+}
+
+func TestShouldFailWhenThePACThrows(t *testing.T) {
+	pac, err := client.NewPAC(`function FindProxyForURL(url, host) { throw new Error("no route for " + host); }`)
+	assert.NoError(t, err)
+
+	_, err = pac.FindProxies(test.MustParseURL(t, "http://example.com/"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no route for example.com")
+}
+
+func TestShouldFailWhenThePACReturnsNoUsableProxy(t *testing.T) {
+	pac, err := client.NewPAC(`function FindProxyForURL(url, host) { return host == "a" ? "SOCKS4 a:1080" : 42; }`)
+	assert.NoError(t, err)
+
+	_, err = pac.FindProxies(test.MustParseURL(t, "http://a/"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "did not return any usable proxy")
+
+	_, err = pac.FindProxies(test.MustParseURL(t, "http://b/"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "did not return a string")
+}
+
+func TestShouldSendTheRequestsThroughTheProxiesOfThePAC(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	httpProxy, proxyAssertion := test.NewHTTPConnectProxyWithAssertion()
+	defer httpProxy.Close()
+
+	pacServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ns-proxy-autoconfig")
+			_, _ = w.Write([]byte(`
+				function FindProxyForURL(url, host) {
+					if (shExpMatch(url, "*/direct")) {
+						return "DIRECT";
+					}
+
+					// the first proxy is not reachable, the second one is used instead
+					return "PROXY 127.0.0.1:9556; PROXY ` + test.MustParseURL(t, httpProxy.URL).Host + `";
+				}
+			`))
+		}),
+	)
+	defer pacServer.Close()
+
+	pac, err := client.LoadPAC(pacServer.URL + "/proxy.pac")
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		test.MustParseURL(t, testServer.URL),
+		client.WithPAC(pac),
+	)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/home", "/direct", "/admin"} {
+		res, err := c.Get(testServer.URL + path)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+	}
+
+	assert.Equal(t, 3, serverAssertion.Len())
+	// the connection to the target established through the proxy is reused
+	assert.Equal(t, 1, proxyAssertion.Len())
+}
+
+func TestShouldFailToCreateAClientWithAPACAndOtherProxies(t *testing.T) {
+	pac, err := client.NewPAC(`function FindProxyForURL(url, host) { return "DIRECT"; }`)
+	assert.NoError(t, err)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithPAC(pac),
+		client.WithHTTPProxy(test.MustParseURL(t, "http://127.0.0.1:8080")),
+	)
+	assert.Nil(t, c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "a proxy auto-config cannot be used together with other proxies")
+}
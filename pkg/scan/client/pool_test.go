@@ -0,0 +1,130 @@
+package client_test
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldReuseTheConnectionsUnlessKeepAlivesAreDisabled(t *testing.T) {
+	testCases := []struct {
+		opts                []client.Option
+		expectedConnections int
+	}{
+		{
+			expectedConnections: 1,
+		},
+		{
+			opts:                []client.Option{client.WithMaxIdleConns(1)},
+			expectedConnections: 1,
+		},
+		{
+			opts:                []client.Option{client.WithDisableKeepAlives()},
+			expectedConnections: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		var (
+			mu          sync.Mutex
+			remoteAddrs = make(map[string]struct{})
+		)
+
+		testServer, _ := test.NewServerWithAssertion(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				remoteAddrs[r.RemoteAddr] = struct{}{}
+				mu.Unlock()
+
+				w.WriteHeader(http.StatusNoContent)
+			}),
+		)
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			tc.opts...,
+		)
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			res, err := c.Get(testServer.URL)
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+		}
+
+		testServer.Close()
+
+		mu.Lock()
+		assert.Len(t, remoteAddrs, tc.expectedConnections)
+		mu.Unlock()
+	}
+}
+
+func TestShouldNotOpenMoreConnectionsThanTheMaxConnsPerHost(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithMaxConnsPerHost(2),
+	)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := c.Get(testServer.URL)
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 6, serverAssertion.Len())
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ntlmssp "github.com/Azure/go-ntlmssp"
+	"github.com/pkg/errors"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	ProxyAuthBasic = "basic"
+	ProxyAuthNTLM  = "ntlm"
+)
+
+// ProxyAuths returns the authentication schemes supported for HTTP proxies.
+func ProxyAuths() []string {
+	return []string{ProxyAuthBasic, ProxyAuthNTLM}
+}
+
+// useHTTPProxy makes the transport send the requests to the given forward proxy: plain HTTP requests are sent
+// in absolute form, HTTPS ones are tunneled using the CONNECT method.
+func useHTTPProxy(transport *http.Transport, socks5Url *url.URL, o options) error {
+	switch strings.ToLower(o.httpProxy.Scheme) {
+	case "http", "https":
+	default:
+		return errors.Errorf("http proxy must be in the format http(s)://host:port: %s", o.httpProxy.Redacted())
+	}
+
+	if socks5Url != nil || len(o.proxyChain) > 0 {
+		return errors.New("an http proxy cannot be used together with a socks5 proxy or a proxy chain")
+	}
+
+	// the custom round trippers and tls dialers establish their connections without going through the proxy
+	if o.tlsFingerprint != "" || (o.httpVersion != "" && o.httpVersion != HTTPVersion11) {
+		return errors.New("an http proxy can only be used with HTTP/1.1 and without a tls fingerprint")
+	}
+
+	// net/http only authenticates against the proxy using the basic scheme
+	if o.proxyAuth == ProxyAuthNTLM {
+		return errors.New("ntlm proxy auth cannot be used with an http proxy, use a proxy chain instead")
+	}
+
+	transport.Proxy = http.ProxyURL(o.httpProxy)
+
+	return nil
+}
+
+// newProxyChainDialer builds a dialer tunneling connections through the given proxies, in order:
+// the first proxy is dialed directly, every following one is reached through the previous ones.
+// Supported schemes are socks5, socks5h, http and https (the latter two using the CONNECT method).
+// HTTP proxies having credentials in their URL are authenticated using the given scheme, see ProxyAuths.
+func newProxyChainDialer(proxies []*url.URL, auth string, dialer proxy.Dialer) (proxy.ContextDialer, error) {
+	switch auth {
+	case "", ProxyAuthBasic, ProxyAuthNTLM:
+	default:
+		return nil, errors.Errorf("unsupported proxy authentication `%s`, valid values are %v", auth, ProxyAuths())
+	}
+
+	for _, proxyURL := range proxies {
+		switch strings.ToLower(proxyURL.Scheme) {
+		case "http", "https":
+			dialer = newHTTPConnectDialer(proxyURL, auth, dialer)
+		default:
+			d, err := proxy.FromURL(proxyURL, dialer)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to create proxy dialer for %s", proxyURL.Redacted())
+			}
+
+			dialer = d
+		}
+	}
+
+	return contextDialer{dialer: dialer}, nil
+}
+
+// contextDialer adapts dialers not supporting contexts.
+type contextDialer struct {
+	dialer proxy.Dialer
+}
+
+func (c contextDialer) Dial(network, addr string) (net.Conn, error) {
+	return c.DialContext(context.Background(), network, addr)
+}
+
+func (c contextDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d, ok := c.dialer.(proxy.ContextDialer); ok {
+		return d.DialContext(ctx, network, addr)
+	}
+
+	return c.dialer.Dial(network, addr)
+}
+
+// dialerAdapter allows to use a Dialer where a proxy.Dialer is expected.
+type dialerAdapter struct {
+	Dialer
+}
+
+func (d dialerAdapter) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+func newHTTPConnectDialer(proxyURL *url.URL, auth string, forward proxy.Dialer) *httpConnectDialer {
+	return &httpConnectDialer{proxyURL: proxyURL, auth: auth, forward: contextDialer{dialer: forward}}
+}
+
+// httpConnectDialer establishes tunnels through an HTTP proxy using the CONNECT method.
+type httpConnectDialer struct {
+	proxyURL *url.URL
+	auth     string
+	forward  proxy.ContextDialer
+}
+
+func (h *httpConnectDialer) Dial(network, addr string) (net.Conn, error) {
+	return h.DialContext(context.Background(), network, addr)
+}
+
+func (h *httpConnectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := h.forward.DialContext(ctx, network, h.proxyAddr())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to proxy %s", h.proxyURL.Host)
+	}
+
+	if strings.EqualFold(h.proxyURL.Scheme, "https") {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: h.proxyURL.Hostname()}) //nolint:gosec
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrapf(err, "tls handshake with proxy %s failed", h.proxyURL.Host)
+		}
+
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{}) //nolint:errcheck
+	}
+
+	if err := h.connect(conn, addr); err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (h *httpConnectDialer) connect(conn net.Conn, addr string) error {
+	reader := bufio.NewReader(conn)
+
+	var (
+		res *http.Response
+		err error
+	)
+
+	switch {
+	case h.proxyURL.User == nil:
+		res, err = h.sendConnect(conn, reader, addr, "")
+	case h.auth == ProxyAuthNTLM:
+		res, err = h.connectWithNTLM(conn, reader, addr)
+	default:
+		password, _ := h.proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(h.proxyURL.User.Username() + ":" + password))
+
+		res, err = h.sendConnect(conn, reader, addr, "Basic "+credentials)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("proxy %s refused to CONNECT to %s: %s", h.proxyURL.Host, addr, res.Status)
+	}
+
+	return nil
+}
+
+// connectWithNTLM performs the NTLM handshake, which authenticates the connection rather than the
+// single request: every message has to be exchanged over the same connection.
+func (h *httpConnectDialer) connectWithNTLM(conn net.Conn, reader *bufio.Reader, addr string) (*http.Response, error) {
+	user, domain, domainNeeded := ntlmssp.GetDomain(h.proxyURL.User.Username())
+	password, _ := h.proxyURL.User.Password()
+
+	negotiate, err := ntlmssp.NewNegotiateMessage(domain, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create NTLM negotiate message")
+	}
+
+	res, err := h.sendConnect(conn, reader, addr, "NTLM "+base64.StdEncoding.EncodeToString(negotiate))
+	if err != nil || res.StatusCode != http.StatusProxyAuthRequired {
+		return res, err
+	}
+
+	challenge, err := ntlmChallenge(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid NTLM challenge from proxy %s", h.proxyURL.Host)
+	}
+
+	authenticate, err := ntlmssp.ProcessChallenge(challenge, user, password, domainNeeded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to answer the NTLM challenge from proxy %s", h.proxyURL.Host)
+	}
+
+	return h.sendConnect(conn, reader, addr, "NTLM "+base64.StdEncoding.EncodeToString(authenticate))
+}
+
+func (h *httpConnectDialer) sendConnect(
+	conn net.Conn,
+	reader *bufio.Reader,
+	addr string,
+	authorization string,
+) (*http.Response, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+
+	if authorization != "" {
+		req.Header.Set("Proxy-Authorization", authorization)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, errors.Wrapf(err, "failed to send CONNECT to proxy %s", h.proxyURL.Host)
+	}
+
+	res, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read CONNECT response from proxy %s", h.proxyURL.Host)
+	}
+
+	// once established the tunnel takes over the connection, otherwise the body has to be consumed
+	// to be able to reuse the connection for the next message
+	if res.StatusCode != http.StatusOK {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		_ = res.Body.Close()
+	}
+
+	return res, nil
+}
+
+func ntlmChallenge(res *http.Response) ([]byte, error) {
+	for _, value := range res.Header.Values("Proxy-Authenticate") {
+		if strings.HasPrefix(value, "NTLM ") {
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(strings.TrimPrefix(value, "NTLM ")))
+		}
+	}
+
+	return nil, errors.New("the proxy did not reply with an NTLM challenge")
+}
+
+func (h *httpConnectDialer) proxyAddr() string {
+	if h.proxyURL.Port() != "" {
+		return h.proxyURL.Host
+	}
+
+	if strings.EqualFold(h.proxyURL.Scheme, "https") {
+		return net.JoinHostPort(h.proxyURL.Hostname(), "443")
+	}
+
+	return net.JoinHostPort(h.proxyURL.Hostname(), "80")
+}
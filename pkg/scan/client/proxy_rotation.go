@@ -0,0 +1,182 @@
+package client
+
+import (
+	"bufio"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/proxy"
+)
+
+const (
+	ProxyRotationRoundRobin = "round-robin"
+	ProxyRotationRandom     = "random"
+
+	// maxProxyFailures is the number of consecutive failures after which a proxy is evicted from the rotation.
+	maxProxyFailures = 3
+)
+
+// ErrNoProxyAvailable is returned when every proxy of the rotation has been evicted.
+var ErrNoProxyAvailable = errors.New("every proxy of the rotation has been evicted")
+
+// ProxyRotations returns the strategies that can be used to pick the proxy of every request.
+func ProxyRotations() []string {
+	return []string{ProxyRotationRoundRobin, ProxyRotationRandom}
+}
+
+// LoadProxyList reads a file containing one proxy URL per line, empty lines and lines starting with # are ignored.
+func LoadProxyList(path string) ([]*url.URL, error) {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open proxy list %s", path)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	proxies := make([]*url.URL, 0)
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		rawProxy := strings.TrimSpace(scanner.Text())
+		if rawProxy == "" || strings.HasPrefix(rawProxy, "#") {
+			continue
+		}
+
+		u, err := url.Parse(rawProxy)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.Errorf("proxy list %s line %d: proxy must be in the format scheme://host:port", path, line)
+		}
+
+		proxies = append(proxies, u)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read proxy list %s", path)
+	}
+
+	if len(proxies) == 0 {
+		return nil, errors.Errorf("proxy list %s is empty", path)
+	}
+
+	return proxies, nil
+}
+
+// newProxyRotator builds a round tripper sending every request through one of the given proxies, each one
+// having its own transport so that reused connections do not defeat the rotation.
+func newProxyRotator(
+	transport *http.Transport,
+	dialer proxy.Dialer,
+	o options,
+) (http.RoundTripper, error) {
+	switch o.proxyRotation {
+	case ProxyRotationRoundRobin, ProxyRotationRandom:
+	default:
+		return nil, errors.Errorf(
+			"unsupported proxy rotation `%s`, valid values are %v",
+			o.proxyRotation,
+			ProxyRotations(),
+		)
+	}
+
+	rotator := &proxyRotator{strategy: o.proxyRotation}
+
+	for _, proxyURL := range o.proxyList {
+		proxyDialer, err := newProxyChainDialer([]*url.URL{proxyURL}, o.proxyAuth, dialer)
+		if err != nil {
+			return nil, err
+		}
+
+		proxyTransport := transport.Clone()
+		proxyTransport.DialContext = proxyDialer.DialContext
+
+		roundTripper, err := configureRoundTripper(proxyTransport, o)
+		if err != nil {
+			return nil, err
+		}
+
+		rotator.proxies = append(rotator.proxies, &rotatedProxy{url: proxyURL, roundTripper: roundTripper})
+	}
+
+	return rotator, nil
+}
+
+type rotatedProxy struct {
+	url          *url.URL
+	roundTripper http.RoundTripper
+	failures     int
+}
+
+type proxyRotator struct {
+	strategy string
+	mu       sync.Mutex
+	proxies  []*rotatedProxy
+	next     int
+}
+
+func (p *proxyRotator) RoundTrip(r *http.Request) (*http.Response, error) {
+	picked, err := p.pick()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := picked.roundTripper.RoundTrip(r)
+
+	// the requests cancelled by the caller say nothing about the health of the proxy
+	if r.Context().Err() == nil {
+		p.track(picked, err == nil)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "request through proxy %s failed", picked.url.Redacted())
+	}
+
+	return res, nil
+}
+
+func (p *proxyRotator) pick() (*rotatedProxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.proxies) == 0 {
+		return nil, ErrNoProxyAvailable
+	}
+
+	if p.strategy == ProxyRotationRandom {
+		return p.proxies[rand.Intn(len(p.proxies))], nil // #nosec
+	}
+
+	p.next %= len(p.proxies)
+	picked := p.proxies[p.next]
+	p.next++
+
+	return picked, nil
+}
+
+func (p *proxyRotator) track(tracked *rotatedProxy, succeeded bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if succeeded {
+		tracked.failures = 0
+
+		return
+	}
+
+	tracked.failures++
+	if tracked.failures < maxProxyFailures {
+		return
+	}
+
+	for i, candidate := range p.proxies {
+		if candidate == tracked {
+			p.proxies = append(p.proxies[:i], p.proxies[i+1:]...)
+
+			return
+		}
+	}
+}
@@ -1,44 +1,89 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"sync"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
 )
 
 var (
 	// ErrRequestRedundant this error is returned when trying to perform the
-	// same request (method, host, path) more than one time.
+	// same request (method, host, path, query) more than one time.
 	ErrRequestRedundant = errors.New("this request has been made already")
 )
 
-func decorateTransportWithRequestCacheDecorator(decorated http.RoundTripper) (*requestCacheTransportDecorator, error) {
+type skipRequestCacheKey struct{}
+
+// SkipRequestCache marks the request to be performed even when the same method, host, path and query
+// were already requested, eg because it differs only by its headers.
+func SkipRequestCache(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), skipRequestCacheKey{}, true))
+}
+
+// requestSet remembers the requests already performed.
+type requestSet interface {
+	// add marks the request as performed, telling whether it already was.
+	add(key string) bool
+}
+
+// exactRequestSet keeps every request performed in memory.
+type exactRequestSet struct {
+	requests sync.Map
+}
+
+func (s *exactRequestSet) add(key string) bool {
+	_, found := s.requests.LoadOrStore(key, struct{}{})
+
+	return found
+}
+
+// bloomRequestSet remembers the requests performed in a fixed amount of memory, see WithRequestCacheBloomFilter.
+type bloomRequestSet struct {
+	filter *bloom.Filter
+}
+
+func (s bloomRequestSet) add(key string) bool {
+	return s.filter.Add(key)
+}
+
+func decorateTransportWithRequestCacheDecorator(
+	decorated http.RoundTripper,
+	requests requestSet,
+) (*requestCacheTransportDecorator, error) {
 	if decorated == nil {
 		return nil, errors.New("decorated round tripper is nil")
 	}
 
-	return &requestCacheTransportDecorator{decorated: decorated}, nil
+	if requests == nil {
+		requests = &exactRequestSet{}
+	}
+
+	return &requestCacheTransportDecorator{decorated: decorated, requests: requests}, nil
 }
 
 type requestCacheTransportDecorator struct {
-	decorated  http.RoundTripper
-	requestMap sync.Map
+	decorated http.RoundTripper
+	requests  requestSet
 }
 
 func (u *requestCacheTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if skip, _ := r.Context().Value(skipRequestCacheKey{}).(bool); skip {
+		return u.decorated.RoundTrip(r)
+	}
+
 	key := u.keyForRequest(r)
 
-	_, found := u.requestMap.Load(key)
-	if found {
+	if u.requests.add(key) {
 		return nil, ErrRequestRedundant
 	}
 
-	u.requestMap.Store(key, struct{}{})
-
 	return u.decorated.RoundTrip(r)
 }
 
 func (u *requestCacheTransportDecorator) keyForRequest(r *http.Request) string {
-	return fmt.Sprintf("%s~%s~%s", r.Method, r.Host, r.URL.Path)
+	return fmt.Sprintf("%s~%s~%s~%s", r.Method, r.Host, r.URL.Path, r.URL.RawQuery)
 }
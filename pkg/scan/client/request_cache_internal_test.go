@@ -1,13 +1,66 @@
 package client
 
 import (
+	"net/http"
 	"testing"
 
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRequestCacheTransportDecorator(t *testing.T) {
-	transport, err := decorateTransportWithRequestCacheDecorator(nil)
+	transport, err := decorateTransportWithRequestCacheDecorator(nil, nil)
 	assert.Nil(t, transport)
 	assert.Error(t, err)
 }
+
+func TestRequestCacheTransportDecoratorShouldPerformTheRequestsSkippingTheCache(t *testing.T) {
+	performed := 0
+
+	transport, err := decorateTransportWithRequestCacheDecorator(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			performed++
+
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		nil,
+	)
+	assert.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/admin", nil)
+	assert.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	assert.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	assert.ErrorIs(t, err, ErrRequestRedundant)
+
+	_, err = transport.RoundTrip(SkipRequestCache(req))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, performed)
+}
+
+func TestRequestCacheTransportDecoratorWithBloomFilterShouldSkipTheRedundantRequests(t *testing.T) {
+	performed := 0
+
+	transport, err := decorateTransportWithRequestCacheDecorator(
+		roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			performed++
+
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+		bloomRequestSet{filter: bloom.New(100, 0.001)},
+	)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/admin", "/login", "/admin"} {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		assert.NoError(t, err)
+
+		_, _ = transport.RoundTrip(req) //nolint:bodyclose
+	}
+
+	assert.Equal(t, 2, performed)
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	// ErrRequestOutOfScope this error is returned when trying to perform
+	// a request pointing to a URL which is not in the configured scope.
+	ErrRequestOutOfScope = errors.New("this request is out of scope")
+)
+
+func decorateTransportWithScopeDecorator(decorated http.RoundTripper, scope Scope) (*scopeTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if scope == nil {
+		return nil, errors.New("scope is nil")
+	}
+
+	return &scopeTransportDecorator{decorated: decorated, scope: scope}, nil
+}
+
+type scopeTransportDecorator struct {
+	decorated http.RoundTripper
+	scope     Scope
+}
+
+func (s *scopeTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !s.scope.InScope(r.URL) {
+		return nil, ErrRequestOutOfScope
+	}
+
+	return s.decorated.RoundTrip(r)
+}
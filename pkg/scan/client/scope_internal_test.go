@@ -0,0 +1,27 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scopeFunc func(u *url.URL) bool
+
+func (f scopeFunc) InScope(u *url.URL) bool {
+	return f(u)
+}
+
+func TestDecorateTransportScopeShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithScopeDecorator(nil, scopeFunc(func(u *url.URL) bool { return true }))
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportScopeShouldFailWithNilScope(t *testing.T) {
+	transport, err := decorateTransportWithScopeDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
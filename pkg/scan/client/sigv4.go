@@ -0,0 +1,251 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// AWSSigV4 contains what is needed to sign requests using AWS Signature Version 4.
+type AWSSigV4 struct {
+	Region      string
+	Service     string
+	Credentials AWSCredentialsProvider
+}
+
+func decorateTransportWithSigV4Decorator(decorated http.RoundTripper, sigV4 AWSSigV4) (*sigV4TransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if sigV4.Region == "" || sigV4.Service == "" {
+		return nil, errors.New("region and service are required to sign requests")
+	}
+
+	if sigV4.Credentials == nil {
+		return nil, errors.New("aws credentials are required to sign requests")
+	}
+
+	if static, ok := sigV4.Credentials.(AWSCredentials); ok {
+		if _, err := static.Retrieve(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &sigV4TransportDecorator{decorated: decorated, sigV4: sigV4, now: time.Now}, nil
+}
+
+type sigV4TransportDecorator struct {
+	decorated http.RoundTripper
+	sigV4     AWSSigV4
+	now       func() time.Time
+}
+
+func (s *sigV4TransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := s.sign(r); err != nil {
+		return nil, err
+	}
+
+	return s.decorated.RoundTrip(r)
+}
+
+func (s *sigV4TransportDecorator) sign(r *http.Request) error {
+	credentials, err := s.sigV4.Credentials.Retrieve(r.Context())
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the aws credentials: %w", err)
+	}
+
+	payloadHash, err := hashPayload(r)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	credentialScope := strings.Join(
+		[]string{now.Format(sigV4DateFormat), s.sigV4.Region, s.sigV4.Service, "aws4_request"},
+		"/",
+	)
+
+	r.Header.Set("X-Amz-Date", amzDate)
+
+	if credentials.SessionToken != "" {
+		r.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	if s.sigV4.Service == "s3" {
+		r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	canonicalHeaders, signedHeaders := s.canonicalHeaders(r)
+
+	canonicalRequest := strings.Join(
+		[]string{
+			r.Method,
+			s.canonicalURI(r),
+			canonicalQueryString(r),
+			canonicalHeaders,
+			signedHeaders,
+			payloadHash,
+		},
+		"\n",
+	)
+
+	stringToSign := strings.Join(
+		[]string{sigV4Algorithm, amzDate, credentialScope, hashSHA256([]byte(canonicalRequest))},
+		"\n",
+	)
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), now.Format(sigV4DateFormat))
+	signingKey = hmacSHA256(signingKey, s.sigV4.Region)
+	signingKey = hmacSHA256(signingKey, s.sigV4.Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	r.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			sigV4Algorithm,
+			credentials.AccessKeyID,
+			credentialScope,
+			signedHeaders,
+			hex.EncodeToString(hmacSHA256(signingKey, stringToSign)),
+		),
+	)
+
+	return nil
+}
+
+func (s *sigV4TransportDecorator) canonicalURI(r *http.Request) string {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	// S3 is the only service not requiring the path to be encoded twice
+	if s.sigV4.Service == "s3" {
+		return path
+	}
+
+	return awsURIEncode(path, false)
+}
+
+func (s *sigV4TransportDecorator) canonicalHeaders(r *http.Request) (canonical string, signed string) {
+	headers := map[string]string{"host": requestHost(r)}
+
+	for name, values := range r.Header {
+		lowerName := strings.ToLower(name)
+		if lowerName == "content-type" || strings.HasPrefix(lowerName, "x-amz-") {
+			headers[lowerName] = strings.Join(values, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	builder := strings.Builder{}
+	for _, name := range names {
+		builder.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+
+	return builder.String(), strings.Join(names, ";")
+}
+
+func canonicalQueryString(r *http.Request) string {
+	query := r.URL.Query()
+
+	pairs := make([]string, 0, len(query))
+
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+func requestHost(r *http.Request) string {
+	if r.Host != "" {
+		return r.Host
+	}
+
+	return r.URL.Host
+}
+
+func hashPayload(r *http.Request) (string, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return hashSHA256(nil), nil
+	}
+
+	if r.GetBody == nil {
+		return "", errors.New("unable to sign a request having a body that cannot be read twice")
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return "", err
+	}
+
+	defer body.Close() //nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// awsURIEncode encodes every byte except the unreserved characters, as required by SigV4.
+func awsURIEncode(s string, encodeSlash bool) string {
+	builder := strings.Builder{}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			builder.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			builder.WriteByte(c)
+		default:
+			builder.WriteString(fmt.Sprintf("%%%02X", c))
+		}
+	}
+
+	return builder.String()
+}
+
+func hashSHA256(data []byte) string {
+	h := sha256.Sum256(data)
+
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
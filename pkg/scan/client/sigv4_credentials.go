@@ -0,0 +1,164 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AWSCredentialsProvider retrieves the AWS credentials used to sign the requests, see LoadAWSCredentials.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// AWSCredentials represents a set of AWS credentials used to sign requests.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expiration is when temporary credentials expire, it is zero for the long-term ones.
+	Expiration time.Time
+}
+
+// Retrieve returns the credentials themselves, making them a provider of static credentials.
+func (c AWSCredentials) Retrieve(_ context.Context) (AWSCredentials, error) {
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("aws credentials are required to sign requests")
+	}
+
+	return c, nil
+}
+
+// LoadAWSCredentials looks up the AWS credentials in the following sources, in order:
+//   - the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables
+//   - the keys of the AWS_PROFILE (or `default`) profile of the shared credentials file, AWS_SHARED_CREDENTIALS_FILE
+//     or ~/.aws/credentials; when AWS_PROFILE is set the lookup stops there
+//   - the container credentials endpoint of ECS and EKS, AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or
+//     AWS_CONTAINER_CREDENTIALS_FULL_URI, authorized with AWS_CONTAINER_AUTHORIZATION_TOKEN(_FILE)
+//   - the role of the EC2 instance, through the instance metadata service (IMDSv2), unless
+//     AWS_EC2_METADATA_DISABLED is true; AWS_EC2_METADATA_SERVICE_ENDPOINT overrides its address
+//
+// The credentials of the last two sources are temporary: they are renewed before they expire.
+// SSO, credential_process, web identity tokens and the role_arn of the profiles are not supported.
+func LoadAWSCredentials(ctx context.Context) (AWSCredentialsProvider, error) {
+	credentials := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if credentials.AccessKeyID != "" && credentials.SecretAccessKey != "" {
+		return credentials, nil
+	}
+
+	credentials, err := loadAWSSharedCredentials()
+	if err == nil {
+		return credentials, nil
+	}
+
+	if os.Getenv("AWS_PROFILE") != "" {
+		return nil, err
+	}
+
+	retrieve, err := awsRemoteCredentialsSource()
+	if err != nil {
+		return nil, err
+	}
+
+	if retrieve == nil {
+		return nil, errors.New(
+			"no aws credentials found in the environment, the shared credentials file, the container " +
+				"credentials endpoint nor the instance metadata service",
+		)
+	}
+
+	provider := &refreshingAWSCredentials{retrieve: retrieve, now: time.Now}
+
+	// the credentials are retrieved upfront to fail before the scan starts
+	if _, err := provider.Retrieve(ctx); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func loadAWSSharedCredentials() (AWSCredentials, error) {
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to find the aws shared credentials file")
+		}
+
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+
+	return loadAWSCredentialsFromFile(path, profile)
+}
+
+func loadAWSCredentialsFromFile(path, profile string) (AWSCredentials, error) {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return AWSCredentials{}, errors.Wrap(err, "failed to open the aws shared credentials file")
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	credentials := AWSCredentials{}
+	currentProfile := ""
+
+	fileScanner := bufio.NewScanner(file)
+	for fileScanner.Scan() {
+		line := strings.TrimSpace(fileScanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			currentProfile = strings.TrimSpace(line[1 : len(line)-1])
+
+			continue
+		}
+
+		if currentProfile != profile {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+
+		switch strings.TrimSpace(parts[0]) {
+		case "aws_access_key_id":
+			credentials.AccessKeyID = value
+		case "aws_secret_access_key":
+			credentials.SecretAccessKey = value
+		case "aws_session_token":
+			credentials.SessionToken = value
+		}
+	}
+
+	if err := fileScanner.Err(); err != nil {
+		return AWSCredentials{}, errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.Errorf("profile `%s` not found or incomplete in %s", profile, path)
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigV4ShouldSignRequestsAccordingToTheAWSTestSuite(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		url               string
+		expectedSignature string
+	}{
+		{
+			url:               "https://example.amazonaws.com/",
+			expectedSignature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			url:               "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expectedSignature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+	}
+
+	for _, tc := range testCases {
+		sut, err := decorateTransportWithSigV4Decorator(
+			http.DefaultTransport,
+			AWSSigV4{
+				Region:  "us-east-1",
+				Service: "service",
+				Credentials: AWSCredentials{
+					AccessKeyID:     "AKIDEXAMPLE",
+					SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+				},
+			},
+		)
+		assert.NoError(t, err)
+
+		sut.now = func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		assert.NoError(t, err)
+
+		assert.NoError(t, sut.sign(req))
+
+		assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(
+			t,
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, Signature="+tc.expectedSignature,
+			req.Header.Get("Authorization"),
+			tc.url,
+		)
+	}
+}
+
+func TestDecorateTransportSigV4ShouldFailWithInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	transport, err := decorateTransportWithSigV4Decorator(nil, AWSSigV4{})
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+
+	transport, err = decorateTransportWithSigV4Decorator(http.DefaultTransport, AWSSigV4{})
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+
+	transport, err = decorateTransportWithSigV4Decorator(
+		http.DefaultTransport,
+		AWSSigV4{Region: "eu-west-1", Service: "execute-api"},
+	)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestLoadAWSCredentialsFromFile(t *testing.T) {
+	t.Parallel()
+
+	credentials, err := loadAWSCredentialsFromFile("testdata/aws_credentials", "default")
+	assert.NoError(t, err)
+	assert.Equal(t, AWSCredentials{AccessKeyID: "AKIDDEFAULT", SecretAccessKey: "default-secret"}, credentials)
+
+	credentials, err = loadAWSCredentialsFromFile("testdata/aws_credentials", "scanner")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		AWSCredentials{AccessKeyID: "AKIDSCANNER", SecretAccessKey: "scanner-secret", SessionToken: "scanner-token"},
+		credentials,
+	)
+
+	_, err = loadAWSCredentialsFromFile("testdata/aws_credentials", "missing")
+	assert.Error(t, err)
+
+	_, err = loadAWSCredentialsFromFile("testdata/not_existing", "default")
+	assert.Error(t, err)
+}
+
+func TestLoadAWSCredentialsFromTheContainerEndpoint(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "container-token", r.Header.Get("Authorization"))
+
+		_, _ = fmt.Fprintf(
+			w,
+			`{"AccessKeyId":"AKIDTASK","SecretAccessKey":"task-secret","Token":"task-token","Expiration":"%s"}`,
+			expiration.Format(time.RFC3339),
+		)
+	}))
+	defer server.Close()
+
+	unsetAWSCredentialsEnv(t)
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL+"/v2/credentials")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "container-token")
+
+	provider, err := LoadAWSCredentials(context.Background())
+	assert.NoError(t, err)
+
+	credentials, err := provider.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		AWSCredentials{
+			AccessKeyID:     "AKIDTASK",
+			SecretAccessKey: "task-secret",
+			SessionToken:    "task-token",
+			Expiration:      expiration,
+		},
+		credentials,
+	)
+}
+
+func TestLoadAWSCredentialsFromTheInstanceMetadataService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest/api/token" {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "21600", r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+
+			_, _ = w.Write([]byte("imds-token"))
+
+			return
+		}
+
+		if r.Header.Get("X-aws-ec2-metadata-token") != "imds-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/latest/meta-data/iam/security-credentials/":
+			_, _ = w.Write([]byte("scanner-role\n"))
+		case "/latest/meta-data/iam/security-credentials/scanner-role":
+			_, _ = w.Write([]byte(
+				`{"Code":"Success","AccessKeyId":"AKIDINSTANCE","SecretAccessKey":"instance-secret",` +
+					`"Token":"instance-token","Expiration":"2030-01-01T00:00:00Z"}`,
+			))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	unsetAWSCredentialsEnv(t)
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", server.URL+"/")
+
+	provider, err := LoadAWSCredentials(context.Background())
+	assert.NoError(t, err)
+
+	credentials, err := provider.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "AKIDINSTANCE", credentials.AccessKeyID)
+	assert.Equal(t, "instance-secret", credentials.SecretAccessKey)
+	assert.Equal(t, "instance-token", credentials.SessionToken)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), credentials.Expiration)
+}
+
+func TestLoadAWSCredentialsShouldFailWhenNoSourceIsAvailable(t *testing.T) {
+	unsetAWSCredentialsEnv(t)
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	_, err := LoadAWSCredentials(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no aws credentials found")
+}
+
+func TestRefreshingAWSCredentialsShouldRenewThemBeforeTheyExpire(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	retrieved := 0
+
+	provider := &refreshingAWSCredentials{
+		retrieve: func(_ context.Context) (AWSCredentials, error) {
+			retrieved++
+
+			return AWSCredentials{
+				AccessKeyID:     fmt.Sprintf("AKID%d", retrieved),
+				SecretAccessKey: "secret",
+				Expiration:      now.Add(time.Hour),
+			}, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	for _, elapsed := range []time.Duration{0, 30 * time.Minute, 54 * time.Minute} {
+		now = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Add(elapsed)
+
+		credentials, err := provider.Retrieve(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "AKID1", credentials.AccessKeyID, elapsed)
+	}
+
+	// less than 5 minutes before the expiration
+	now = time.Date(2030, 1, 1, 0, 56, 0, 0, time.UTC)
+
+	credentials, err := provider.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "AKID2", credentials.AccessKeyID)
+}
+
+// unsetAWSCredentialsEnv makes sure that the credentials of the host running the tests are not used.
+func unsetAWSCredentialsEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID",
+		"AWS_SECRET_ACCESS_KEY",
+		"AWS_SESSION_TOKEN",
+		"AWS_PROFILE",
+		"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+		"AWS_EC2_METADATA_DISABLED",
+		"AWS_EC2_METADATA_SERVICE_ENDPOINT",
+	} {
+		t.Setenv(name, "")
+	}
+
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "testdata/not_existing")
+}
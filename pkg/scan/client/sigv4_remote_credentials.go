@@ -0,0 +1,230 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// awsMetadataTimeout bounds the requests to the credentials endpoints, which are local to the host.
+	awsMetadataTimeout = 2 * time.Second
+	// awsCredentialsExpiryWindow is how long before their expiration the temporary credentials are renewed.
+	awsCredentialsExpiryWindow = 5 * time.Minute
+	// maxAWSMetadataSize bounds the size of the responses of the credentials endpoints.
+	maxAWSMetadataSize = 1 << 16
+
+	awsContainerCredentialsHost = "http://169.254.170.2"
+	awsIMDSEndpoint             = "http://169.254.169.254"
+	awsIMDSTokenTTL             = "21600"
+)
+
+type awsCredentialsSource func(ctx context.Context) (AWSCredentials, error)
+
+// awsRemoteCredentialsSource returns the source of the container credentials when the container endpoint is
+// configured, the one of the instance metadata service otherwise; it returns nil when both are disabled.
+func awsRemoteCredentialsSource() (awsCredentialsSource, error) {
+	// the endpoints are local to the host: the proxies configured in the environment must not be used
+	c := &http.Client{Timeout: awsMetadataTimeout, Transport: &http.Transport{}}
+
+	if relativeURI := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relativeURI != "" {
+		return containerCredentialsSource(c, awsContainerCredentialsHost+relativeURI), nil
+	}
+
+	if fullURI := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); fullURI != "" {
+		return containerCredentialsSource(c, fullURI), nil
+	}
+
+	if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		return nil, nil
+	}
+
+	endpoint := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = awsIMDSEndpoint
+	}
+
+	return imdsCredentialsSource(c, strings.TrimSuffix(endpoint, "/")), nil
+}
+
+// awsRemoteCredentials is the format in which both the container endpoint and the instance metadata
+// service return the credentials.
+type awsRemoteCredentials struct {
+	Code            string
+	Message         string
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+func (r awsRemoteCredentials) credentials() (AWSCredentials, error) {
+	if r.Code != "" && r.Code != "Success" {
+		return AWSCredentials{}, errors.Errorf("%s: %s", r.Code, r.Message)
+	}
+
+	if r.AccessKeyID == "" || r.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("the response does not contain any credentials")
+	}
+
+	return AWSCredentials{
+		AccessKeyID:     r.AccessKeyID,
+		SecretAccessKey: r.SecretAccessKey,
+		SessionToken:    r.Token,
+		Expiration:      r.Expiration,
+	}, nil
+}
+
+// containerCredentialsSource retrieves the credentials of the task or pod role from the container endpoint.
+func containerCredentialsSource(c *http.Client, endpoint string) awsCredentialsSource {
+	return func(ctx context.Context) (AWSCredentials, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "invalid container credentials endpoint")
+		}
+
+		authorization, err := containerAuthorizationToken()
+		if err != nil {
+			return AWSCredentials{}, err
+		}
+
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		raw, err := fetchAWSMetadata(c, req)
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to retrieve the aws container credentials")
+		}
+
+		remote := awsRemoteCredentials{}
+		if err := json.Unmarshal(raw, &remote); err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to parse the aws container credentials")
+		}
+
+		credentials, err := remote.credentials()
+
+		return credentials, errors.Wrap(err, "invalid aws container credentials")
+	}
+}
+
+// containerAuthorizationToken returns the token authorizing the requests to the container endpoint, the
+// file is read every time as the token it contains is rotated.
+func containerAuthorizationToken() (string, error) {
+	if path := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); path != "" {
+		raw, err := os.ReadFile(path) // #nosec
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the aws container authorization token")
+		}
+
+		return strings.TrimSpace(string(raw)), nil
+	}
+
+	return os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"), nil
+}
+
+// imdsCredentialsSource retrieves the credentials of the role of the EC2 instance from the instance metadata
+// service, using a session token as IMDSv2 requires.
+func imdsCredentialsSource(c *http.Client, endpoint string) awsCredentialsSource {
+	return func(ctx context.Context) (AWSCredentials, error) {
+		tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "invalid instance metadata service endpoint")
+		}
+
+		tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", awsIMDSTokenTTL)
+
+		token, err := fetchAWSMetadata(c, tokenReq)
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to retrieve the instance metadata service token")
+		}
+
+		get := func(path string) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("X-aws-ec2-metadata-token", string(token))
+
+			return fetchAWSMetadata(c, req)
+		}
+
+		roles, err := get("/latest/meta-data/iam/security-credentials/")
+		if err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to retrieve the role of the instance")
+		}
+
+		role := strings.TrimSpace(strings.SplitN(string(roles), "\n", 2)[0])
+		if role == "" {
+			return AWSCredentials{}, errors.New("no role is attached to the instance")
+		}
+
+		raw, err := get("/latest/meta-data/iam/security-credentials/" + role)
+		if err != nil {
+			return AWSCredentials{}, errors.Wrapf(err, "failed to retrieve the credentials of the instance role %s", role)
+		}
+
+		remote := awsRemoteCredentials{}
+		if err := json.Unmarshal(raw, &remote); err != nil {
+			return AWSCredentials{}, errors.Wrap(err, "failed to parse the instance role credentials")
+		}
+
+		credentials, err := remote.credentials()
+
+		return credentials, errors.Wrap(err, "invalid instance role credentials")
+	}
+}
+
+func fetchAWSMetadata(c *http.Client, req *http.Request) ([]byte, error) {
+	res, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(res.Body, maxAWSMetadataSize))
+}
+
+// refreshingAWSCredentials caches temporary credentials, renewing them shortly before they expire.
+type refreshingAWSCredentials struct {
+	retrieve awsCredentialsSource
+	now      func() time.Time
+
+	mu          sync.Mutex
+	credentials AWSCredentials
+}
+
+func (r *refreshingAWSCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := !r.credentials.Expiration.IsZero() &&
+		!r.now().Add(awsCredentialsExpiryWindow).Before(r.credentials.Expiration)
+
+	if r.credentials.AccessKeyID != "" && !expired {
+		return r.credentials, nil
+	}
+
+	credentials, err := r.retrieve(ctx)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+
+	r.credentials = credentials
+
+	return credentials, nil
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const defaultSSHPort = "22"
+
+// SSHTunnelConfig describes how to reach the SSH jump host the connections are tunneled through.
+type SSHTunnelConfig struct {
+	// Destination is the jump host in the format user@host[:port].
+	Destination string
+	// KeyPath is the private key used to authenticate, when empty the keys of the ssh agent
+	// listening on SSH_AUTH_SOCK are used.
+	KeyPath string
+	// KnownHostsPath is the file used to verify the key of the jump host, ~/.ssh/known_hosts by default.
+	KnownHostsPath string
+	Timeout        time.Duration
+}
+
+// SSHTunnel is a Dialer establishing every connection from the jump host, through a single SSH connection.
+type SSHTunnel struct {
+	client *ssh.Client
+}
+
+// DialSSHTunnel connects and authenticates to the jump host described by the config.
+func DialSSHTunnel(config SSHTunnelConfig) (*SSHTunnel, error) {
+	user, addr, err := parseSSHDestination(config.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := sshAuthMethod(config.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(config.KnownHostsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         config.Timeout,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to the ssh jump host %s", addr)
+	}
+
+	return &SSHTunnel{client: c}, nil
+}
+
+// DialContext opens a connection to addr from the jump host.
+func (t *SSHTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	type dialResult struct {
+		conn net.Conn
+		err  error
+	}
+
+	// the ssh client does not support contexts, the dial is abandoned when the context is done
+	results := make(chan dialResult, 1)
+
+	go func() {
+		conn, err := t.client.Dial(network, addr)
+		results <- dialResult{conn: conn, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		go func() {
+			if r := <-results; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+
+		return nil, ctx.Err()
+	case r := <-results:
+		return r.conn, errors.Wrapf(r.err, "failed to dial %s through the ssh tunnel", addr)
+	}
+}
+
+// Close terminates the SSH connection, and with it every connection tunneled through it.
+func (t *SSHTunnel) Close() error {
+	return t.client.Close()
+}
+
+func parseSSHDestination(destination string) (user, addr string, err error) {
+	parts := strings.SplitN(destination, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("ssh destination must be in the format user@host[:port]: %s", destination)
+	}
+
+	user, addr = parts[0], parts[1]
+
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSSHPort)
+	}
+
+	return user, addr, nil
+}
+
+func sshAuthMethod(keyPath string) (ssh.AuthMethod, error) {
+	if keyPath != "" {
+		rawKey, err := os.ReadFile(keyPath) // #nosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read ssh key %s", keyPath)
+		}
+
+		signer, err := ssh.ParsePrivateKey(rawKey)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"failed to parse ssh key %s (keys protected by a passphrase must be loaded in the ssh agent)",
+				keyPath,
+			)
+		}
+
+		return ssh.PublicKeys(signer), nil
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, errors.New("no ssh key provided and SSH_AUTH_SOCK is not set")
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the ssh agent")
+	}
+
+	return ssh.PublicKeysCallback(agent.NewClient(conn).Signers), nil
+}
+
+func sshHostKeyCallback(knownHostsPath string) (ssh.HostKeyCallback, error) {
+	if knownHostsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find the known_hosts file")
+		}
+
+		knownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load known hosts from %s", knownHostsPath)
+	}
+
+	return callback, nil
+}
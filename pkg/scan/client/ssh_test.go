@@ -0,0 +1,163 @@
+package client_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+func TestShouldTunnelTheRequestsThroughTheSSHJumpHost(t *testing.T) {
+	dir := t.TempDir()
+
+	privateKey, publicKey := generateSSHKey(t)
+	keyPath := writeSSHKey(t, dir, privateKey)
+
+	sshServer := test.NewSSHServer(t, "pivot", publicKey)
+	defer sshServer.Close() //nolint:errcheck
+
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	tunnel, err := client.DialSSHTunnel(client.SSHTunnelConfig{
+		Destination:    "pivot@" + sshServer.Addr(),
+		KeyPath:        keyPath,
+		KnownHostsPath: writeKnownHosts(t, dir, sshServer.KnownHostsLine()),
+		Timeout:        time.Second,
+	})
+	assert.NoError(t, err)
+
+	defer tunnel.Close() //nolint:errcheck
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithDialer(tunnel),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	res.Body.Close() //nolint:errcheck,gosec
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, 1, serverAssertion.Len())
+	assert.Equal(t, 1, sshServer.Forwarded())
+}
+
+func TestShouldAuthenticateAgainstTheSSHJumpHostUsingTheAgent(t *testing.T) {
+	dir := t.TempDir()
+
+	privateKey, publicKey := generateSSHKey(t)
+
+	keyring := agent.NewKeyring()
+	assert.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: privateKey}))
+
+	socket := filepath.Join(dir, "agent.sock")
+
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	defer listener.Close() //nolint:errcheck
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go agent.ServeAgent(keyring, conn) //nolint:errcheck
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	sshServer := test.NewSSHServer(t, "pivot", publicKey)
+	defer sshServer.Close() //nolint:errcheck
+
+	tunnel, err := client.DialSSHTunnel(client.SSHTunnelConfig{
+		Destination:    "pivot@" + sshServer.Addr(),
+		KnownHostsPath: writeKnownHosts(t, dir, sshServer.KnownHostsLine()),
+		Timeout:        time.Second,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, tunnel.Close())
+}
+
+func TestShouldRefuseAnSSHJumpHostWithAnUnknownHostKey(t *testing.T) {
+	dir := t.TempDir()
+
+	privateKey, publicKey := generateSSHKey(t)
+
+	sshServer := test.NewSSHServer(t, "pivot", publicKey)
+	defer sshServer.Close() //nolint:errcheck
+
+	tunnel, err := client.DialSSHTunnel(client.SSHTunnelConfig{
+		Destination:    "pivot@" + sshServer.Addr(),
+		KeyPath:        writeSSHKey(t, dir, privateKey),
+		KnownHostsPath: writeKnownHosts(t, dir, ""),
+		Timeout:        time.Second,
+	})
+	assert.Nil(t, tunnel)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to the ssh jump host")
+	assert.Contains(t, err.Error(), "key is unknown")
+}
+
+func TestShouldFailToDialAnSSHTunnelWithAnInvalidDestination(t *testing.T) {
+	tunnel, err := client.DialSSHTunnel(client.SSHTunnelConfig{Destination: "jumphost"})
+	assert.Nil(t, tunnel)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ssh destination must be in the format user@host[:port]")
+}
+
+func generateSSHKey(t *testing.T) (ed25519.PrivateKey, ssh.PublicKey) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	assert.NoError(t, err)
+
+	return privateKey, publicKey
+}
+
+func writeSSHKey(t *testing.T, dir string, privateKey ed25519.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "id_ed25519")
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	return path
+}
+
+func writeKnownHosts(t *testing.T, dir, line string) string {
+	path := filepath.Join(dir, "known_hosts")
+	assert.NoError(t, os.WriteFile(path, []byte(line), 0600))
+
+	return path
+}
@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldTimeoutWhenTheConnectionCannotBeEstablishedInTime(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithConnectTimeout(time.Nanosecond),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.Get(testServer.URL) //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "i/o timeout")
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
+
+func TestShouldTimeoutWhenTheTLSHandshakeIsTooSlow(t *testing.T) {
+	// accepts the connections without ever completing the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	defer listener.Close() //nolint:errcheck
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	testCases := []struct {
+		opts          []client.Option
+		expectedError string
+	}{
+		{
+			expectedError: "TLS handshake timeout",
+		},
+		{
+			opts:          []client.Option{client.WithTLSFingerprint("firefox")},
+			expectedError: "context deadline exceeded",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			true,
+			nil,
+			append([]client.Option{client.WithTLSHandshakeTimeout(50 * time.Millisecond)}, tc.opts...)...,
+		)
+		assert.NoError(t, err)
+
+		start := time.Now()
+
+		_, err = c.Get("https://" + listener.Addr().String()) //nolint:bodyclose
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), tc.expectedError)
+		assert.Less(t, time.Since(start), time.Second)
+	}
+}
+
+func TestShouldTimeoutWhenTheResponseHeadersAreTooSlow(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/slow" {
+				time.Sleep(200 * time.Millisecond)
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithResponseHeaderTimeout(50*time.Millisecond),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL + "/fast")
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+
+	_, err = c.Get(testServer.URL + "/slow") //nolint:bodyclose
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout awaiting response headers")
+}
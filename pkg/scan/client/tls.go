@@ -0,0 +1,289 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// LoadPKCS12Certificate decodes a (password protected) PKCS#12 bundle into a certificate
+// that can be used to authenticate against servers requiring mutual TLS.
+func LoadPKCS12Certificate(path, password string) (tls.Certificate, error) {
+	raw, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to read pkcs12 bundle %s", path)
+	}
+
+	key, cert, caCerts, err := pkcs12.DecodeChain(raw, password)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to decode pkcs12 bundle %s", path)
+	}
+
+	certificate := tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}
+
+	for _, caCert := range caCerts {
+		certificate.Certificate = append(certificate.Certificate, caCert.Raw)
+	}
+
+	return certificate, nil
+}
+
+// LoadX509KeyPair reads a PEM encoded certificate (chain) and its private key, which can be protected by
+// a passphrase using the legacy PEM encryption, to authenticate against servers requiring mutual TLS.
+func LoadX509KeyPair(certPath, keyPath, passphrase string) (tls.Certificate, error) {
+	rawCert, err := os.ReadFile(certPath) // #nosec
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to read certificate %s", certPath)
+	}
+
+	rawKey, err := os.ReadFile(keyPath) // #nosec
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to read private key %s", keyPath)
+	}
+
+	if passphrase != "" {
+		if rawKey, err = decryptPEMKey(rawKey, passphrase); err != nil {
+			return tls.Certificate{}, errors.Wrapf(err, "failed to decrypt private key %s", keyPath)
+		}
+	}
+
+	certificate, err := tls.X509KeyPair(rawCert, rawKey)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to load key pair %s %s", certPath, keyPath)
+	}
+
+	return certificate, nil
+}
+
+func decryptPEMKey(rawKey []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(rawKey)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	//nolint:staticcheck // the legacy PEM encryption is insecure, but still the one produced by many tools
+	if !x509.IsEncryptedPEMBlock(block) {
+		return nil, errors.New("the key is not encrypted using the legacy PEM encryption")
+	}
+
+	//nolint:staticcheck
+	der, err := x509.DecryptPEMBlock(block, []byte(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
+
+// LoadCertPool reads a PEM encoded bundle of certificate authorities, which are trusted in addition to the
+// ones of the system, eg: to scan internal services signed by a private CA.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	rawBundle, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read ca bundle %s", path)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(rawBundle) {
+		return nil, errors.Errorf("no certificate found in ca bundle %s", path)
+	}
+
+	return pool, nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersions returns the names of the TLS versions that can be used as minimum or maximum version.
+func TLSVersions() []string {
+	names := make([]string, 0, len(tlsVersions))
+	for name := range tlsVersions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func tlsVersion(name string) (uint16, error) {
+	version, found := tlsVersions[name]
+	if !found {
+		return 0, errors.Errorf("unknown tls version `%s`, valid values are %v", name, TLSVersions())
+	}
+
+	return version, nil
+}
+
+// TLSVersionName returns the name of the given TLS version, eg: 1.2.
+func TLSVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// TLSCipherSuites returns the names of the cipher suites that can be enabled, including the insecure ones
+// still offered by legacy servers. The TLS 1.3 cipher suites are not configurable, so they are not listed.
+func TLSCipherSuites() []string {
+	names := make([]string, 0)
+
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if isTLS13OnlyCipherSuite(suite) {
+			continue
+		}
+
+		names = append(names, suite.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func isTLS13OnlyCipherSuite(suite *tls.CipherSuite) bool {
+	return len(suite.SupportedVersions) == 1 && suite.SupportedVersions[0] == tls.VersionTLS13
+}
+
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	ids := make(map[string]uint16)
+
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if !isTLS13OnlyCipherSuite(suite) {
+			ids[suite.Name] = suite.ID
+		}
+	}
+
+	suites := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, found := ids[name]
+		if !found {
+			return nil, errors.Errorf("unknown tls cipher suite `%s`, valid values are %v", name, TLSCipherSuites())
+		}
+
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
+
+// buildTLSConfig returns nil when no customization is needed, so that the transport keeps using the defaults.
+func buildTLSConfig(shouldSkipSSLCertificatesValidation bool, o options) (*tls.Config, error) {
+	if !shouldSkipSSLCertificatesValidation &&
+		len(o.clientCertificates) == 0 &&
+		o.tlsMinVersion == "" &&
+		o.tlsMaxVersion == "" &&
+		len(o.tlsCipherSuites) == 0 &&
+		o.rootCAs == nil &&
+		o.serverName == "" &&
+		len(o.insecureSkipVerifyHosts) == 0 {
+		return nil, nil
+	}
+
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: shouldSkipSSLCertificatesValidation,
+		RootCAs:            o.rootCAs,
+		ServerName:         o.serverName,
+	}
+
+	if len(o.clientCertificates) > 0 {
+		tlsConfig.Certificates = o.clientCertificates
+	}
+
+	if o.tlsMinVersion != "" {
+		minVersion, err := tlsVersion(o.tlsMinVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.MinVersion = minVersion
+	}
+
+	if o.tlsMaxVersion != "" {
+		maxVersion, err := tlsVersion(o.tlsMaxVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		if maxVersion < tlsConfig.MinVersion {
+			return nil, errors.New("the tls max version cannot be lower than the tls min version")
+		}
+
+		tlsConfig.MaxVersion = maxVersion
+	}
+
+	if len(o.tlsCipherSuites) > 0 {
+		if o.tlsFingerprint != "" {
+			return nil, errors.New("the tls cipher suites cannot be customized when using a tls fingerprint")
+		}
+
+		suites, err := cipherSuiteIDs(o.tlsCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.CipherSuites = suites
+	}
+
+	return tlsConfig, nil
+}
+
+// newInsecureHostsRoundTripper sends the requests for the given hosts through a round tripper skipping the
+// validation of the certificates, the crypto/tls verification cannot be disabled for a subset of the hosts
+// of a single transport.
+func newInsecureHostsRoundTripper(secure, insecure http.RoundTripper, hosts []string) http.RoundTripper {
+	r := &insecureHostsRoundTripper{secure: secure, insecure: insecure, hosts: make(map[string]struct{}, len(hosts))}
+
+	for _, host := range hosts {
+		r.hosts[strings.ToLower(host)] = struct{}{}
+	}
+
+	return r
+}
+
+type insecureHostsRoundTripper struct {
+	secure   http.RoundTripper
+	insecure http.RoundTripper
+	hosts    map[string]struct{}
+}
+
+func (i *insecureHostsRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if _, found := i.hosts[strings.ToLower(r.URL.Hostname())]; found {
+		return i.insecure.RoundTrip(r)
+	}
+
+	return i.secure.RoundTrip(r)
+}
+
+func (i *insecureHostsRoundTripper) CloseIdleConnections() {
+	for _, roundTripper := range []http.RoundTripper{i.secure, i.insecure} {
+		if closer, ok := roundTripper.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how early a token is considered expired, to avoid
+// sending a token that expires while the request is in flight.
+const tokenExpiryDelta = 10 * time.Second
+
+// TokenSource provides bearer tokens, a zero expiry means that the token never expires unless
+// it is a JWT carrying an `exp` claim.
+type TokenSource interface {
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+func decorateTransportWithBearerTokenDecorator(
+	decorated http.RoundTripper,
+	tokenSource TokenSource,
+) (*bearerTokenTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if tokenSource == nil {
+		return nil, errors.New("token source is nil")
+	}
+
+	return &bearerTokenTransportDecorator{decorated: decorated, tokenSource: tokenSource}, nil
+}
+
+type bearerTokenTransportDecorator struct {
+	decorated   http.RoundTripper
+	tokenSource TokenSource
+
+	token  string
+	expiry time.Time
+	mx     sync.Mutex
+}
+
+// RoundTrip attaches the current token to the request, if the server replies with 401 the token
+// is renewed and the request is retried once.
+func (b *bearerTokenTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := b.currentToken(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := b.decorated.RoundTrip(r)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || !canBeReplayed(r) {
+		return res, err
+	}
+
+	b.invalidate(token)
+
+	refused := token
+
+	// retrying is pointless when the token source could not provide a different token
+	token, err = b.currentToken(r.Context())
+	if err != nil || token == refused {
+		return res, nil //nolint:nilerr
+	}
+
+	retry := r.Clone(r.Context())
+	if r.GetBody != nil {
+		if retry.Body, err = r.GetBody(); err != nil {
+			return res, nil //nolint:nilerr
+		}
+	}
+
+	_ = res.Body.Close()
+
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	return b.decorated.RoundTrip(retry)
+}
+
+func (b *bearerTokenTransportDecorator) currentToken(ctx context.Context) (string, error) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.token != "" && !b.isExpired() {
+		return b.token, nil
+	}
+
+	token, expiry, err := b.tokenSource.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if expiry.IsZero() {
+		expiry, _ = jwtExpiry(token)
+	}
+
+	b.token = token
+	b.expiry = expiry
+
+	return b.token, nil
+}
+
+// invalidate discards the given token, so that the next request will trigger a renewal; if the token was
+// already replaced (eg by a concurrent request that got a 401 too) nothing happens.
+func (b *bearerTokenTransportDecorator) invalidate(token string) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.token == token {
+		b.token = ""
+	}
+}
+
+func (b *bearerTokenTransportDecorator) isExpired() bool {
+	if b.expiry.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(tokenExpiryDelta).After(b.expiry)
+}
+
+func canBeReplayed(r *http.Request) bool {
+	return r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+}
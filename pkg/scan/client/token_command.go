@@ -0,0 +1,87 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// tokenCommandTimeout bounds the execution of the command providing the token.
+	tokenCommandTimeout = 30 * time.Second
+	// tokenCommandMinInterval prevents the command from being executed for every request when the
+	// server keeps refusing the fresh tokens too.
+	tokenCommandMinInterval = time.Second
+)
+
+func newCommandTokenSource(command string) *commandTokenSource {
+	return &commandTokenSource{command: command}
+}
+
+// commandTokenSource obtains the bearer tokens by executing a shell command, the token being what the
+// command prints to the standard output; eg: a script refreshing an OAuth2 token.
+type commandTokenSource struct {
+	command string
+
+	mx      sync.Mutex
+	token   string
+	fetched time.Time
+	runs    int
+}
+
+func (c *commandTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	// the first token printed might be already expired, so the first refresh is never delayed
+	if c.runs > 1 && time.Since(c.fetched) < tokenCommandMinInterval {
+		return c.token, time.Time{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	cmd := shellCommand(ctx, c.command)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", time.Time{}, errors.Wrapf(
+			err,
+			"token command: failed to execute `%s`: %s",
+			c.command,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	// the output of the commands printing the whole header value is accepted too
+	if scheme, value, found := strings.Cut(token, " "); found && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(value)
+	}
+
+	if token == "" {
+		return "", time.Time{}, errors.Errorf("token command: `%s` did not print any token", c.command)
+	}
+
+	c.token, c.fetched = token, time.Now()
+	c.runs++
+
+	return token, time.Time{}, nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) // #nosec
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", command) // #nosec
+}
@@ -0,0 +1,145 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// counterTokenCommand returns a command printing a different token every time it is executed.
+func counterTokenCommand(t *testing.T) string {
+	counter := filepath.Join(t.TempDir(), "counter")
+
+	return fmt.Sprintf(`n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; echo "token-$n"`, counter)
+}
+
+func TestShouldRefreshTheTokenWithTheCommandWhenTheServerRepliesWith401(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithTokenCommand(counterTokenCommand(t)),
+	)
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/home", "/admin", "/login"} {
+		res, err := c.Get(testServer.URL + path)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNoContent, res.StatusCode, path)
+	}
+
+	// the expired token is refused once, the fresh one is used for the following requests
+	assert.Equal(t, 4, serverAssertion.Len())
+
+	serverAssertion.At(0, func(r http.Request) {
+		assert.Equal(t, "Bearer token-1", r.Header.Get("Authorization"))
+	})
+}
+
+func TestShouldNotRunTheTokenCommandForEveryRequestWhenTheTokensKeepBeingRefused(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer testServer.Close()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithTokenCommand(counterTokenCommand(t)),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		res, err := c.Get(testServer.URL)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	}
+
+	// the first token is refreshed once, then the command is not executed again for a while
+	assert.Equal(t, 6, serverAssertion.Len())
+
+	serverAssertion.Range(func(i int, r http.Request) {
+		if i > 0 {
+			assert.Equal(t, "Bearer token-2", r.Header.Get("Authorization"))
+		}
+	})
+}
+
+func TestShouldFailTheRequestsWhenTheTokenCommandFails(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		command       string
+		expectedError string
+	}{
+		{
+			command:       "echo 'session expired' >&2; exit 3",
+			expectedError: "session expired",
+		},
+		{
+			command:       "echo",
+			expectedError: "did not print any token",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			nil,
+			client.WithTokenCommand(tc.command),
+		)
+		assert.NoError(t, err)
+
+		_, err = c.Get(testServer.URL) //nolint:bodyclose
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	}
+
+	assert.Equal(t, 0, serverAssertion.Len())
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorateTransportBearerTokenShouldFailWithNilDecorated(t *testing.T) {
+	transport, err := decorateTransportWithBearerTokenDecorator(
+		nil,
+		newOAuth2ClientCredentialsTokenSource(OAuth2ClientCredentials{}, http.DefaultClient),
+	)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
+
+func TestDecorateTransportBearerTokenShouldFailWithNilTokenSource(t *testing.T) {
+	transport, err := decorateTransportWithBearerTokenDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const torControlTimeout = 10 * time.Second
+
+// TorController talks to the control port of a Tor instance.
+type TorController struct {
+	addr     string
+	password string
+	mu       sync.Mutex
+}
+
+// NewTorController creates a controller for the control port listening on addr, the password is only
+// needed when the control port is protected by HashedControlPassword.
+func NewTorController(addr, password string) *TorController {
+	return &TorController{addr: addr, password: password}
+}
+
+// NewCircuit asks Tor to use new circuits for the following connections, changing the exit node.
+func (t *TorController) NewCircuit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, err := net.DialTimeout("tcp", t.addr, torControlTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to the tor control port %s", t.addr)
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	_ = conn.SetDeadline(time.Now().Add(torControlTimeout))
+
+	reader := bufio.NewReader(conn)
+
+	commands := []string{
+		fmt.Sprintf("AUTHENTICATE %s", quoteTorControlString(t.password)),
+		"SIGNAL NEWNYM",
+		"QUIT",
+	}
+
+	for _, command := range commands {
+		if _, err := fmt.Fprintf(conn, "%s\r\n", command); err != nil {
+			return errors.Wrap(err, "failed to write to the tor control port")
+		}
+
+		reply, err := reader.ReadString('\n')
+		if err != nil {
+			return errors.Wrap(err, "failed to read from the tor control port")
+		}
+
+		if !strings.HasPrefix(reply, "250") {
+			return errors.Errorf("tor control port refused `%s`: %s", strings.Fields(command)[0], strings.TrimSpace(reply))
+		}
+	}
+
+	return nil
+}
+
+func quoteTorControlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// TorCircuitRenewal describes when a new Tor circuit has to be requested.
+type TorCircuitRenewal struct {
+	Controller *TorController
+	// EveryRequests renews the circuit after the given amount of requests, zero disables it.
+	EveryRequests int
+	// OnTooManyRequests renews the circuit every time the target replies with 429.
+	OnTooManyRequests bool
+	// OnError is notified when the circuit could not be renewed.
+	OnError func(err error)
+}
+
+func decorateTransportWithTorCircuitRenewalDecorator(
+	decorated http.RoundTripper,
+	renewal TorCircuitRenewal,
+) (*torCircuitRenewalTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if renewal.Controller == nil {
+		return nil, errors.New("tor controller is nil")
+	}
+
+	return &torCircuitRenewalTransportDecorator{decorated: decorated, renewal: renewal}, nil
+}
+
+type torCircuitRenewalTransportDecorator struct {
+	decorated http.RoundTripper
+	renewal   TorCircuitRenewal
+	requests  uint64
+}
+
+func (t *torCircuitRenewalTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	res, err := t.decorated.RoundTrip(r)
+
+	requests := atomic.AddUint64(&t.requests, 1)
+
+	renewForRequests := t.renewal.EveryRequests > 0 && requests%uint64(t.renewal.EveryRequests) == 0
+	renewForStatus := t.renewal.OnTooManyRequests && err == nil && res.StatusCode == http.StatusTooManyRequests
+
+	if renewForRequests || renewForStatus {
+		t.renew()
+	}
+
+	return res, err
+}
+
+func (t *torCircuitRenewalTransportDecorator) renew() {
+	if err := t.renewal.Controller.NewCircuit(); err != nil {
+		if t.renewal.OnError != nil {
+			t.renewal.OnError(err)
+		}
+
+		return
+	}
+
+	// the connections kept alive would keep using the previous circuit
+	if closer, ok := t.decorated.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,141 @@
+package client_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldRenewTheTorCircuit(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/throttled" {
+				w.WriteHeader(http.StatusTooManyRequests)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	testCases := []struct {
+		renewal          client.TorCircuitRenewal
+		paths            []string
+		expectedRenewals int
+	}{
+		{
+			renewal:          client.TorCircuitRenewal{EveryRequests: 2},
+			paths:            []string{"/a", "/b", "/c", "/d", "/e"},
+			expectedRenewals: 2,
+		},
+		{
+			renewal:          client.TorCircuitRenewal{OnTooManyRequests: true},
+			paths:            []string{"/a", "/throttled", "/b", "/throttled"},
+			expectedRenewals: 2,
+		},
+		{
+			renewal:          client.TorCircuitRenewal{},
+			paths:            []string{"/a", "/throttled"},
+			expectedRenewals: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		controlServer := test.NewTorControlServer(t, "c0ntrol")
+
+		renewal := tc.renewal
+		renewal.Controller = client.NewTorController(controlServer.Addr(), "c0ntrol")
+
+		c, err := client.NewClientFromConfig(
+			1500,
+			nil,
+			"",
+			false,
+			nil,
+			nil,
+			false,
+			false,
+			test.MustParseURL(t, testServer.URL),
+			client.WithTorCircuitRenewal(renewal),
+		)
+		assert.NoError(t, err)
+
+		for _, path := range tc.paths {
+			res, err := c.Get(testServer.URL + path)
+			assert.NoError(t, err)
+			assert.NoError(t, res.Body.Close())
+		}
+
+		assert.Len(t, controlServer.Signals(), tc.expectedRenewals)
+
+		for _, signal := range controlServer.Signals() {
+			assert.Equal(t, "NEWNYM", signal)
+		}
+
+		assert.NoError(t, controlServer.Close())
+	}
+}
+
+func TestShouldNotifyWhenTheTorCircuitCannotBeRenewed(t *testing.T) {
+	testServer, _ := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	controlServer := test.NewTorControlServer(t, "c0ntrol")
+	defer controlServer.Close() //nolint:errcheck
+
+	var (
+		mu            sync.Mutex
+		renewalErrors []error
+	)
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		test.MustParseURL(t, testServer.URL),
+		client.WithTorCircuitRenewal(client.TorCircuitRenewal{
+			Controller:    client.NewTorController(controlServer.Addr(), "wrong"),
+			EveryRequests: 1,
+			OnError: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				renewalErrors = append(renewalErrors, err)
+			},
+		}),
+	)
+	assert.NoError(t, err)
+
+	res, err := c.Get(testServer.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Len(t, renewalErrors, 1)
+	assert.Contains(t, renewalErrors[0].Error(), "tor control port refused `AUTHENTICATE`")
+	assert.Empty(t, controlServer.Signals())
+}
+
+func TestTorControllerShouldFailWhenTheControlPortIsUnreachable(t *testing.T) {
+	err := client.NewTorController("127.0.0.1:9558", "").NewCircuit()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to connect to the tor control port 127.0.0.1:9558")
+}
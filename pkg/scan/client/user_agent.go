@@ -1,10 +1,77 @@
 package client
 
 import (
-	"errors"
+	"bufio"
+	"math/rand"
 	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
+// defaultUserAgents are the user agents of common browsers, used when rotating the user agent without
+// providing a list.
+var defaultUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/128.0.0.0 Safari/537.36 Edg/128.0.0.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+		"Version/18.0 Safari/605.1.15",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.7; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 18_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) " +
+		"Version/18.0 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) " +
+		"Chrome/129.0.0.0 Mobile Safari/537.36",
+}
+
+// DefaultUserAgents returns the built-in list of browser user agents, see WithRandomUserAgents.
+func DefaultUserAgents() []string {
+	userAgents := make([]string, len(defaultUserAgents))
+	copy(userAgents, defaultUserAgents)
+
+	return userAgents
+}
+
+// LoadUserAgents reads a file containing one user agent per line, empty lines and lines starting with #
+// are ignored.
+func LoadUserAgents(path string) ([]string, error) {
+	file, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open user agent list %s", path)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	userAgents := make([]string, 0)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		userAgent := strings.TrimSpace(scanner.Text())
+		if userAgent == "" || strings.HasPrefix(userAgent, "#") {
+			continue
+		}
+
+		userAgents = append(userAgents, userAgent)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read user agent list %s", path)
+	}
+
+	if len(userAgents) == 0 {
+		return nil, errors.Errorf("user agent list %s is empty", path)
+	}
+
+	return userAgents, nil
+}
+
 func decorateTransportWithUserAgentDecorator(decorated http.RoundTripper, userAgent string) (*userAgentTransportDecorator, error) {
 	if decorated == nil {
 		return nil, errors.New("decorated round tripper is nil")
@@ -23,3 +90,30 @@ func (u *userAgentTransportDecorator) RoundTrip(r *http.Request) (*http.Response
 
 	return u.decorated.RoundTrip(r)
 }
+
+func decorateTransportWithRandomUserAgentDecorator(
+	decorated http.RoundTripper,
+	userAgents []string,
+) (*randomUserAgentTransportDecorator, error) {
+	if decorated == nil {
+		return nil, errors.New("decorated round tripper is nil")
+	}
+
+	if len(userAgents) == 0 {
+		return nil, errors.New("no user agent to pick from")
+	}
+
+	return &randomUserAgentTransportDecorator{decorated: decorated, userAgents: userAgents}, nil
+}
+
+// randomUserAgentTransportDecorator sets a user agent picked at random for every request.
+type randomUserAgentTransportDecorator struct {
+	decorated  http.RoundTripper
+	userAgents []string
+}
+
+func (u *randomUserAgentTransportDecorator) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Header.Set("User-Agent", u.userAgents[rand.Intn(len(u.userAgents))]) // #nosec
+
+	return u.decorated.RoundTrip(r)
+}
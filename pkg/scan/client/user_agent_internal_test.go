@@ -1,6 +1,7 @@
 package client
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,3 +12,13 @@ func TestDecorateTransportUserAgent(t *testing.T) {
 	assert.Nil(t, transport)
 	assert.Error(t, err)
 }
+
+func TestDecorateTransportRandomUserAgent(t *testing.T) {
+	transport, err := decorateTransportWithRandomUserAgentDecorator(nil, defaultUserAgents)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+
+	transport, err = decorateTransportWithRandomUserAgentDecorator(http.DefaultTransport, nil)
+	assert.Nil(t, transport)
+	assert.Error(t, err)
+}
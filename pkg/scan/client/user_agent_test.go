@@ -0,0 +1,69 @@
+package client_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldPickARandomUserAgentForEveryRequest(t *testing.T) {
+	testServer, serverAssertion := test.NewServerWithAssertion(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer testServer.Close()
+
+	userAgents := client.DefaultUserAgents()
+
+	c, err := client.NewClientFromConfig(
+		1500,
+		nil,
+		"dirstalk",
+		false,
+		nil,
+		nil,
+		false,
+		false,
+		nil,
+		client.WithRandomUserAgents(userAgents),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 50; i++ {
+		res, err := c.Get(testServer.URL)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Body.Close())
+	}
+
+	seen := make(map[string]struct{})
+	serverAssertion.Range(func(_ int, r http.Request) {
+		assert.Contains(t, userAgents, r.Header.Get("User-Agent"))
+		seen[r.Header.Get("User-Agent")] = struct{}{}
+	})
+	assert.Greater(t, len(seen), 1)
+}
+
+func TestLoadUserAgents(t *testing.T) {
+	userAgents, err := client.LoadUserAgents("testdata/user_agents.txt")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]string{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+			"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+		},
+		userAgents,
+	)
+
+	_, err = client.LoadUserAgents("testdata/user_agents_empty.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is empty")
+
+	_, err = client.LoadUserAgents("testdata/not_existing.txt")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open user agent list")
+}
@@ -0,0 +1,124 @@
+package scan
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// NewConcurrencyLimiter creates a ConcurrencyLimiter allowing up to total requests in flight, perHost of them
+// to the same host; 0 means no limit.
+func NewConcurrencyLimiter(total, perHost int) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{perHost: perHost, hosts: make(map[string]chan struct{})}
+
+	if total > 0 {
+		l.total = make(chan struct{}, total)
+	}
+
+	return l
+}
+
+// ConcurrencyLimiter bounds the requests in flight of the scanners sharing it, eg: the scans of several targets,
+// so that a slow host does not take all of them and no host receives more than its share.
+type ConcurrencyLimiter struct {
+	total   chan struct{}
+	perHost int
+
+	mx    sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// WithConcurrencyLimiter makes the scanner perform its requests within the limits of the given limiter.
+func WithConcurrencyLimiter(limiter *ConcurrencyLimiter) ScannerOption {
+	return func(s *Scanner) {
+		s.limiter = limiter
+	}
+}
+
+// acquire waits for the request to the given host to be allowed, the returned function frees its slot.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	slots := make([]chan struct{}, 0, 2)
+
+	// the slot of the host is taken first, not to hold a slot of the total while waiting for a busy host
+	if hostSlots := l.hostSlots(host); hostSlots != nil {
+		slots = append(slots, hostSlots)
+	}
+
+	if l.total != nil {
+		slots = append(slots, l.total)
+	}
+
+	release := func(acquired []chan struct{}) {
+		for _, s := range acquired {
+			<-s
+		}
+	}
+
+	for i, s := range slots {
+		select {
+		case <-ctx.Done():
+			release(slots[:i])
+
+			return nil, ctx.Err()
+		case s <- struct{}{}:
+		}
+	}
+
+	once := sync.Once{}
+
+	return func() { once.Do(func() { release(slots) }) }, nil
+}
+
+func (l *ConcurrencyLimiter) hostSlots(host string) chan struct{} {
+	if l.perHost <= 0 {
+		return nil
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.perHost)
+		l.hosts[host] = slots
+	}
+
+	return slots
+}
+
+// doWithinConcurrencyLimit performs the request once the limiter allows it, the request is considered in flight
+// until its response body is closed.
+func (s *Scanner) doWithinConcurrencyLimit(req *http.Request) (*http.Response, error) {
+	if s.limiter == nil {
+		return s.httpClient.Do(req)
+	}
+
+	release, err := s.limiter.acquire(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	res.Body = releasingBody{ReadCloser: res.Body, release: release}
+
+	return res, nil
+}
+
+// releasingBody frees the slot of the request once the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b releasingBody) Close() error {
+	defer b.release()
+
+	return b.ReadCloser.Close()
+}
@@ -1,26 +1,147 @@
 package scan
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // Config represents the configuration needed to perform a scan.
 type Config struct {
 	DictionaryPath                      string
 	DictionaryTimeoutInMilliseconds     int
+	Extensions                          []string
+	TrailingSlash                       bool
+	RandomizeOrder                      bool
+	Seed                                int64
 	HTTPMethods                         []string
+	BodyTemplate                        string
+	ContentType                         string
 	HTTPStatusesToIgnore                []int
+	IncludeStatuses                     []Range
+	ExcludeStatuses                     []Range
 	Threads                             int
+	ThreadsPerHost                      int
 	TimeoutInMilliseconds               int
+	ConnectTimeoutInMilliseconds        int
+	TLSTimeoutInMilliseconds            int
+	ResponseHeaderTimeoutInMilliseconds int
+	MaxIdleConns                        int
+	MaxConnsPerHost                     int
+	DisableKeepAlive                    bool
+	Retries                             int
+	RetryBackoff                        time.Duration
+	RateLimit                           float64
+	MaxBandwidth                        int64
+	Delay                               time.Duration
+	DelayJitter                         time.Duration
+	AdaptiveRate                        bool
+	CircuitBreaker                      int
+	CircuitBreakerPause                 time.Duration
+	MaxErrors                           int
+	MaxScanDuration                     time.Duration
+	MaxRequests                         uint64
+	MaxBodySize                         int64
+	DisableCompression                  bool
 	CacheRequests                       bool
 	ScanDepth                           int
+	RecurseOn                           []Range
+	Strategy                            string
+	BloomFilterCapacity                 uint
+	BloomFilterErrorRate                float64
+	RecursionExclusions                 []PathPattern
+	DepthOverrides                      []DepthOverride
 	Socks5Url                           *url.URL
+	ProxyChain                          []*url.URL
+	HTTPProxy                           *url.URL
+	ProxyListPath                       string
+	ProxyRotation                       string
+	ProxyPAC                            string
+	ProxyAuth                           string
+	TorControlAddr                      string
+	TorControlPassword                  string
+	TorRenewEvery                       int
+	TorRenewOnTooManyRequests           bool
+	SSHTunnel                           string
+	SSHKeyPath                          string
+	SSHKnownHostsPath                   string
+	LocalAddr                           net.IP
+	IPVersion                           int
+	Resolve                             map[string]string
+	DoHResolver                         *url.URL
+	DNSCacheTTL                         time.Duration
 	UserAgent                           string
+	RandomUserAgent                     bool
+	UserAgentFilePath                   string
 	UseCookieJar                        bool
 	Cookies                             []*http.Cookie
 	Headers                             map[string]string
+	HeaderPoolPath                      string
+	HostHeader                          string
 	Out                                 string
+	OutputDir                           string
+	StatePath                           string
+	ResumePath                          string
+	TargetsPath                         string
+	ParallelTargets                     int
+	ProbePorts                          []int
+	ProbeTimeoutInMilliseconds          int
+	SignResultsKeyPath                  string
 	ShouldSkipSSLCertificatesValidation bool
+	SkipSSLCertificatesValidationHosts  []string
+	CACertPath                          string
 	IgnoreEmpty20xResponses             bool
+	AutoCalibrate                       bool
+	CalibrationSimilarity               int
+	FilterSize                          []Range
+	FilterWords                         []Range
+	FilterLines                         []Range
+	MatchSize                           []Range
+	MatchWords                          []Range
+	MatchLines                          []Range
+	MatchRegexes                        []string
+	FilterRegexes                       []string
+	Interactive                         bool
+	ScanName                            string
+	Tags                                map[string]string
+	ExtractRegexes                      []string
+	ScanSecrets                         bool
+	SecretPatterns                      []string
+	BackupVariants                      bool
+	LeakChecks                          bool
+	Fingerprint                         bool
+	ProbeMethods                        []string
+	MethodOverride                      bool
+	FollowRedirects                     bool
+	MaxRedirects                        int
+	Robots                              string
+	DictionaryHints                     []string
+	RulesPath                           string
+	ClassifyPath                        string
+	ScopePath                           string
+	OAuth2TokenURL                      string
+	OAuth2ClientID                      string
+	OAuth2ClientSecret                  string
+	OAuth2Scopes                        []string
+	DigestAuthUsername                  string
+	DigestAuthPassword                  string
+	NTLMAuthUsername                    string
+	NTLMAuthPassword                    string
+	AuthRefreshCommand                  string
+	LoginPath                           string
+	AWSSigV4Region                      string
+	AWSSigV4Service                     string
+	TLSP12Path                          string
+	TLSP12Password                      string
+	TLSCertPath                         string
+	TLSKeyPath                          string
+	TLSKeyPassword                      string
+	TLSFingerprint                      string
+	TLSJA3                              string
+	TLSMinVersion                       string
+	TLSMaxVersion                       string
+	SNI                                 string
+	TLSCipherSuites                     []string
+	HTTPVersion                         string
 }
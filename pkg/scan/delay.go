@@ -0,0 +1,38 @@
+package scan
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// WithDelay makes every worker wait before each of its requests, for the given delay plus a random
+// amount of time up to the given jitter.
+func WithDelay(delay, jitter time.Duration) ScannerOption {
+	return func(s *Scanner) {
+		s.delay = delay
+		s.delayJitter = jitter
+	}
+}
+
+// sleepBeforeRequest waits for the configured delay, it returns early when the context is done.
+func (s *Scanner) sleepBeforeRequest(ctx context.Context) error {
+	delay := s.delay
+	if s.delayJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(s.delayJitter))) // #nosec
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
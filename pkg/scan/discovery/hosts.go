@@ -0,0 +1,140 @@
+package discovery
+
+import (
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxHosts is the largest amount of hosts a range can expand to, eg: a /16 network.
+const MaxHosts = 1 << 16
+
+// IsHosts tells an IP, a CIDR or an IP range apart from a url, judging from its start.
+func IsHosts(target string) bool {
+	start := target
+	if i := strings.IndexAny(target, "/-"); i >= 0 {
+		start = target[:i]
+	}
+
+	return net.ParseIP(strings.TrimSpace(start)) != nil
+}
+
+// ExpandHosts returns the addresses of a CIDR (eg: 10.0.0.0/24), of a range (eg: 10.0.0.1-10.0.0.20 or
+// 10.0.0.1-20) or of a single IP. The network and broadcast addresses of an IPv4 CIDR are left out.
+func ExpandHosts(target string) ([]net.IP, error) {
+	if strings.Contains(target, "/") {
+		return expandCIDR(target)
+	}
+
+	if strings.Contains(target, "-") {
+		return expandRange(target)
+	}
+
+	ip := net.ParseIP(target)
+	if ip == nil {
+		return nil, errors.Errorf("%s is not an IP, a CIDR or an IP range", target)
+	}
+
+	return []net.IP{ip}, nil
+}
+
+func expandCIDR(target string) ([]net.IP, error) {
+	ip, network, err := net.ParseCIDR(target)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid CIDR %s", target)
+	}
+
+	ones, bits := network.Mask.Size()
+
+	first, last := network.IP, lastIP(network)
+
+	// the network and broadcast addresses do not belong to any host, unless the network is too small to have them
+	if ip.To4() != nil && bits-ones > 1 {
+		first, last = nextIP(first, 1), nextIP(last, -1)
+	}
+
+	return ipsBetween(first, last, target)
+}
+
+func expandRange(target string) ([]net.IP, error) {
+	parts := strings.SplitN(target, "-", 2)
+
+	first := net.ParseIP(strings.TrimSpace(parts[0]))
+	if first == nil {
+		return nil, errors.Errorf("invalid IP range %s, the start is not an IP", target)
+	}
+
+	end := strings.TrimSpace(parts[1])
+
+	// 10.0.0.1-20 is a shorthand for 10.0.0.1-10.0.0.20
+	if first.To4() != nil && !strings.ContainsAny(end, ".:") {
+		end = first.String()[:strings.LastIndex(first.String(), ".")+1] + end
+	}
+
+	last := net.ParseIP(end)
+	if last == nil {
+		return nil, errors.Errorf("invalid IP range %s, the end is not an IP", target)
+	}
+
+	if (first.To4() == nil) != (last.To4() == nil) {
+		return nil, errors.Errorf("invalid IP range %s, the start and the end belong to different IP versions", target)
+	}
+
+	if ipToInt(last).Cmp(ipToInt(first)) < 0 {
+		return nil, errors.Errorf("invalid IP range %s, the end comes before the start", target)
+	}
+
+	return ipsBetween(first, last, target)
+}
+
+func ipsBetween(first, last net.IP, target string) ([]net.IP, error) {
+	count := new(big.Int).Sub(ipToInt(last), ipToInt(first))
+	count.Add(count, big.NewInt(1))
+
+	if count.Cmp(big.NewInt(MaxHosts)) > 0 {
+		return nil, errors.Errorf("%s has more than %d hosts", target, MaxHosts)
+	}
+
+	size := int(count.Int64())
+
+	ips := make([]net.IP, 0, size)
+	for ip := first; len(ips) < size; ip = nextIP(ip, 1) {
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+func lastIP(network *net.IPNet) net.IP {
+	ip := make(net.IP, len(network.IP))
+	for i := range network.IP {
+		ip[i] = network.IP[i] | ^network.Mask[i]
+	}
+
+	return ip
+}
+
+// nextIP returns the IP that comes delta addresses after the given one.
+func nextIP(ip net.IP, delta int64) net.IP {
+	size := len(ip)
+	if v4 := ip.To4(); v4 != nil {
+		size = net.IPv4len
+	}
+
+	raw := new(big.Int).Add(ipToInt(ip), big.NewInt(delta)).Bytes()
+
+	next := make(net.IP, size)
+	copy(next[size-len(raw):], raw)
+
+	return next
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	if v4 := ip.To4(); v4 != nil {
+		return new(big.Int).SetBytes(v4)
+	}
+
+	return new(big.Int).SetBytes(ip.To16())
+}
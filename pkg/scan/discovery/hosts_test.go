@@ -0,0 +1,81 @@
+package discovery_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandHosts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		target   string
+		expected []string
+	}{
+		{target: "10.0.0.1", expected: []string{"10.0.0.1"}},
+		{target: "10.0.0.0/30", expected: []string{"10.0.0.1", "10.0.0.2"}},
+		{target: "10.0.0.8/31", expected: []string{"10.0.0.8", "10.0.0.9"}},
+		{target: "10.0.0.255-10.0.1.1", expected: []string{"10.0.0.255", "10.0.1.0", "10.0.1.1"}},
+		{target: "10.0.0.1-3", expected: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{target: "fd00::/127", expected: []string{"fd00::", "fd00::1"}},
+		{target: "fd00::ff-fd00::100", expected: []string{"fd00::ff", "fd00::100"}},
+	}
+
+	for _, tc := range testCases {
+		ips, err := discovery.ExpandHosts(tc.target)
+		assert.NoError(t, err)
+
+		actual := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			actual = append(actual, ip.String())
+		}
+
+		assert.Equal(t, tc.expected, actual, tc.target)
+	}
+}
+
+func TestExpandHostsShouldErrForInvalidTargets(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		target        string
+		expectedError string
+	}{
+		{target: "localhost", expectedError: "is not an IP, a CIDR or an IP range"},
+		{target: "10.0.0.0/33", expectedError: "invalid CIDR"},
+		{target: "10.0.0.0/8", expectedError: "has more than 65536 hosts"},
+		{target: "10.0.0.5-2", expectedError: "the end comes before the start"},
+		{target: "10.0.0.1-fd00::1", expectedError: "different IP versions"},
+		{target: "nope-10.0.0.1", expectedError: "the start is not an IP"},
+	}
+
+	for _, tc := range testCases {
+		_, err := discovery.ExpandHosts(tc.target)
+		assert.Error(t, err, tc.target)
+		assert.Contains(t, err.Error(), tc.expectedError)
+	}
+}
+
+func TestExpandHostsShouldAllowTheLargestRange(t *testing.T) {
+	t.Parallel()
+
+	ips, err := discovery.ExpandHosts("10.0.0.0-10.0.255.255")
+	assert.NoError(t, err)
+	assert.Len(t, ips, discovery.MaxHosts)
+	assert.True(t, net.ParseIP("10.0.255.255").Equal(ips[len(ips)-1]))
+}
+
+func TestIsHosts(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, discovery.IsHosts("10.0.0.1"))
+	assert.True(t, discovery.IsHosts("10.0.0.0/24"))
+	assert.True(t, discovery.IsHosts("10.0.0.1-20"))
+	assert.True(t, discovery.IsHosts("fd00::/120"))
+	assert.False(t, discovery.IsHosts("http://10.0.0.1/"))
+	assert.False(t, discovery.IsHosts("localhost"))
+	assert.False(t, discovery.IsHosts("home/index.php"))
+}
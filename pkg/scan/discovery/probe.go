@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// schemes are tried in order, as a TLS server answers plain HTTP requests with a 400 rather than failing.
+var schemes = []string{"https", "http"}
+
+// NewProber creates a Prober that probes the given ports of every host, concurrency at a time.
+func NewProber(doer scan.Doer, ports []int, concurrency int, logger *logrus.Logger) *Prober {
+	return &Prober{
+		doer:        doer,
+		ports:       ports,
+		concurrency: concurrency,
+		logger:      logger,
+	}
+}
+
+// Prober finds the hosts with an HTTP responder listening.
+type Prober struct {
+	doer        scan.Doer
+	ports       []int
+	concurrency int
+	logger      *logrus.Logger
+}
+
+// Probe returns the URL of every HTTP responder found on the given hosts, IPs or host names, ordered like the hosts
+// and the ports. Any response counts, whatever its status code.
+func (p *Prober) Probe(ctx context.Context, hosts []string) []*url.URL {
+	found := make([]*url.URL, len(hosts)*len(p.ports))
+
+	slots := make(chan struct{}, p.concurrency)
+	wg := sync.WaitGroup{}
+
+	for i, host := range hosts {
+		for j, port := range p.ports {
+			select {
+			case <-ctx.Done():
+				continue
+			case slots <- struct{}{}:
+			}
+
+			wg.Add(1)
+
+			go func(index int, address string, port int) {
+				defer wg.Done()
+				defer func() { <-slots }()
+
+				found[index] = p.probe(ctx, address, port)
+			}(i*len(p.ports)+j, host, port)
+		}
+	}
+
+	wg.Wait()
+
+	live := make([]*url.URL, 0)
+
+	for _, u := range found {
+		if u != nil {
+			live = append(live, u)
+		}
+	}
+
+	return live
+}
+
+func (p *Prober) probe(ctx context.Context, address string, port int) *url.URL {
+	for _, scheme := range schemes {
+		u := &url.URL{Scheme: scheme, Host: net.JoinHostPort(address, strconv.Itoa(port)), Path: "/"}
+
+		// the default ports are left out, to have the same URL the user would type
+		if (scheme == "https" && port == 443) || (scheme == "http" && port == 80) {
+			u.Host = strings.TrimSuffix(u.Host, ":"+strconv.Itoa(port))
+		}
+
+		err := p.do(ctx, u)
+		if err == nil {
+			return u
+		}
+
+		p.logger.WithError(err).WithField("url", u.String()).Debug("No HTTP responder found")
+	}
+
+	return nil
+}
+
+func (p *Prober) do(ctx context.Context, u *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to build the probe request")
+	}
+
+	res, err := p.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to perform the probe request")
+	}
+
+	return res.Body.Close()
+}
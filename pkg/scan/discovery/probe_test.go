@@ -0,0 +1,65 @@
+package discovery_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProberShouldFindTheHTTPResponders(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	// nothing listens on a port just released
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	assert.NoError(t, listener.Close())
+
+	logger, _ := test.NewLogger()
+
+	c := tlsServer.Client()
+	c.Timeout = time.Second
+
+	sut := discovery.NewProber(
+		c,
+		[]int{
+			port(t, listener.Addr().String()),
+			port(t, plainServer.Listener.Addr().String()),
+			port(t, tlsServer.Listener.Addr().String()),
+		},
+		2,
+		logger,
+	)
+
+	live := sut.Probe(context.Background(), []string{"127.0.0.1"})
+	assert.Len(t, live, 2)
+	assert.Equal(t, plainServer.URL+"/", live[0].String())
+	assert.Equal(t, tlsServer.URL+"/", live[1].String())
+}
+
+func port(t *testing.T, address string) int {
+	_, rawPort, err := net.SplitHostPort(address)
+	assert.NoError(t, err)
+
+	p, err := strconv.Atoi(rawPort)
+	assert.NoError(t, err)
+
+	return p
+}
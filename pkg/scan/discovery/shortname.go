@@ -0,0 +1,360 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+const (
+	// shortNameChars are the characters looked for in the short names, the ones common in the file names.
+	shortNameChars = "abcdefghijklmnopqrstuvwxyz0123456789-_"
+	// maxShortNameLength is the length of the name of a short name, before the tilde.
+	maxShortNameLength = 6
+	// maxShortExtensionLength is the length of the extension of a short name.
+	maxShortExtensionLength = 3
+	// maxShortNameIndex is the last index after the tilde, past it Windows hashes the long name instead.
+	maxShortNameIndex = 4
+)
+
+// ErrShortNamesNotDisclosed is returned when the target does not answer the wildcard patterns differently
+// depending on whether a short name matches them.
+var ErrShortNamesNotDisclosed = errors.New("the target does not disclose its short names")
+
+// shortNameMethods are tried in order to find one the target discloses the short names to.
+var shortNameMethods = []string{http.MethodGet, http.MethodOptions}
+
+// ShortName is an 8.3 short name, as Windows generates them for the long file names (eg: ADMINI~1.ASP).
+type ShortName struct {
+	Name      string
+	Index     int
+	Extension string
+}
+
+func (n ShortName) String() string {
+	s := n.Name + "~" + strconv.Itoa(n.Index)
+	if n.Extension != "" {
+		s += "." + n.Extension
+	}
+
+	return s
+}
+
+// NewShortNameScanner creates a ShortNameScanner performing concurrency requests at a time.
+func NewShortNameScanner(doer scan.Doer, concurrency int, logger *logrus.Logger) *ShortNameScanner {
+	return &ShortNameScanner{
+		doer:        doer,
+		concurrency: concurrency,
+		logger:      logger,
+	}
+}
+
+// ShortNameScanner enumerates the short names of the files and directories of an IIS target via the tilde
+// vulnerability: the wildcard patterns (eg: /ADM*~1*/.aspx) get a response which differs depending on whether
+// a short name matches them.
+type ShortNameScanner struct {
+	doer        scan.Doer
+	concurrency int
+	logger      *logrus.Logger
+}
+
+// shortNameOracle tells whether a short name matches a pattern in the directory of the base URL.
+type shortNameOracle struct {
+	doer          scan.Doer
+	base          url.URL
+	method        string
+	missingStatus int
+}
+
+// Scan returns the short names found in the directory of the given URL, ErrShortNamesNotDisclosed when the
+// target is not vulnerable.
+func (s *ShortNameScanner) Scan(ctx context.Context, u *url.URL) ([]ShortName, error) {
+	oracle, err := s.calibrate(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"method":         oracle.method,
+		"missing-status": oracle.missingStatus,
+	}).Info("The target discloses its short names")
+
+	shortNames := make([]ShortName, 0)
+
+	for _, name := range s.names(ctx, oracle) {
+		for index := 1; index <= maxShortNameIndex; index++ {
+			base := name + "~" + strconv.Itoa(index)
+			if index > 1 && !oracle.matches(ctx, base+"*") {
+				break
+			}
+
+			for _, extension := range s.extensions(ctx, oracle, base) {
+				shortName := ShortName{
+					Name:      strings.ToUpper(name),
+					Index:     index,
+					Extension: strings.ToUpper(extension),
+				}
+
+				s.logger.WithField("short-name", shortName.String()).Info("Found")
+
+				shortNames = append(shortNames, shortName)
+			}
+		}
+	}
+
+	return shortNames, ctx.Err()
+}
+
+// calibrate finds a method getting a different status for a pattern any short name matches than for one
+// no short name matches.
+func (s *ShortNameScanner) calibrate(ctx context.Context, u *url.URL) (shortNameOracle, error) {
+	base := *u
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	for _, method := range shortNameMethods {
+		oracle := shortNameOracle{doer: s.doer, base: base, method: method}
+
+		anyStatus, err := oracle.status(ctx, "*~1*")
+		if err != nil {
+			return shortNameOracle{}, err
+		}
+
+		// two random prefixes, not to mistake a transient response for the one of the missing short names
+		firstMissing, err := oracle.status(ctx, randomLabel()[:maxShortNameLength]+"*~1*")
+		if err != nil {
+			return shortNameOracle{}, err
+		}
+
+		secondMissing, err := oracle.status(ctx, randomLabel()[:maxShortNameLength]+"*~1*")
+		if err != nil {
+			return shortNameOracle{}, err
+		}
+
+		if firstMissing == secondMissing && anyStatus != firstMissing {
+			oracle.missingStatus = firstMissing
+
+			return oracle, nil
+		}
+	}
+
+	return shortNameOracle{}, ErrShortNamesNotDisclosed
+}
+
+// names returns the names the short names start with, extending one character at a time the prefixes which
+// some short name starts with.
+func (s *ShortNameScanner) names(ctx context.Context, oracle shortNameOracle) []string {
+	names := make([]string, 0)
+	prefixes := []string{""}
+
+	for len(prefixes) > 0 && ctx.Err() == nil {
+		prefix := prefixes[0]
+		prefixes = prefixes[1:]
+
+		if prefix != "" && oracle.matches(ctx, prefix+"~1*") {
+			names = append(names, prefix)
+		}
+
+		if len(prefix) == maxShortNameLength {
+			continue
+		}
+
+		prefixes = append(prefixes, s.matching(ctx, oracle, prefix, func(candidate string) string {
+			return candidate + "*~1*"
+		})...)
+	}
+
+	return names
+}
+
+// extensions returns the extensions of the short names starting with the given base (eg: ADMINI~1), an empty
+// extension standing for the files and directories without one.
+func (s *ShortNameScanner) extensions(ctx context.Context, oracle shortNameOracle, base string) []string {
+	extensions := make([]string, 0)
+	prefixes := []string{""}
+
+	for len(prefixes) > 0 && ctx.Err() == nil {
+		prefix := prefixes[0]
+		prefixes = prefixes[1:]
+
+		next := s.matching(ctx, oracle, prefix, func(candidate string) string {
+			return base + "." + candidate + "*"
+		})
+
+		switch {
+		case len(next) == 0:
+			extensions = append(extensions, prefix)
+		case len(prefix)+1 == maxShortExtensionLength:
+			extensions = append(extensions, next...)
+		default:
+			prefixes = append(prefixes, next...)
+		}
+	}
+
+	return extensions
+}
+
+// matching returns the given prefix extended by the characters for which the pattern built matches a short
+// name, checking them concurrently.
+func (s *ShortNameScanner) matching(
+	ctx context.Context,
+	oracle shortNameOracle,
+	prefix string,
+	pattern func(candidate string) string,
+) []string {
+	matched := make([]bool, len(shortNameChars))
+
+	slots := make(chan struct{}, s.concurrency)
+	wg := sync.WaitGroup{}
+
+	for i, c := range shortNameChars {
+		select {
+		case <-ctx.Done():
+			continue
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func(index int, candidate string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			matched[index] = oracle.matches(ctx, pattern(candidate))
+		}(i, prefix+string(c))
+	}
+
+	wg.Wait()
+
+	candidates := make([]string, 0)
+
+	for i, c := range shortNameChars {
+		if matched[i] {
+			candidates = append(candidates, prefix+string(c))
+		}
+	}
+
+	return candidates
+}
+
+func (o shortNameOracle) matches(ctx context.Context, pattern string) bool {
+	status, err := o.status(ctx, pattern)
+
+	return err == nil && status != o.missingStatus
+}
+
+func (o shortNameOracle) status(ctx context.Context, pattern string) (int, error) {
+	u := o.base
+	u.Path = path.Join(u.Path, pattern, ".aspx")
+
+	req, err := http.NewRequestWithContext(ctx, o.method, u.String(), nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build the short name request")
+	}
+
+	res, err := o.doer.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to perform the short name request for `%s`", pattern)
+	}
+
+	return res.StatusCode, res.Body.Close()
+}
+
+// ShortNameHints returns the dictionary entries guessed out of the short names: the short names themselves,
+// which IIS serves like the long names, and the long names they could stand for. A name shorter than six
+// characters is the whole long name, a six characters one is completed with the words of the dictionary
+// starting with it. The extensions are completed with the ones of the dictionary starting with them.
+func ShortNameHints(shortNames []ShortName, dictionary []string) []string {
+	words, extensions := dictionaryWordsAndExtensions(dictionary)
+
+	hints := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	add := func(hint string) {
+		if _, found := seen[hint]; !found {
+			seen[hint] = struct{}{}
+			hints = append(hints, hint)
+		}
+	}
+
+	for _, n := range shortNames {
+		add(strings.ToLower(n.String()))
+
+		name, extension := strings.ToLower(n.Name), strings.ToLower(n.Extension)
+
+		bases := []string{name}
+		if len(name) == maxShortNameLength {
+			bases = wordsStartingWith(words, name)
+		}
+
+		for _, base := range bases {
+			if extension == "" {
+				add(base)
+
+				continue
+			}
+
+			add(base + "." + extension)
+
+			for _, e := range extensions {
+				if strings.HasPrefix(e, extension) {
+					add(base + "." + e)
+				}
+			}
+		}
+	}
+
+	return hints
+}
+
+// dictionaryWordsAndExtensions splits the entries of the dictionary in the last element of their path and
+// its extension.
+func dictionaryWordsAndExtensions(dictionary []string) ([]string, []string) {
+	words, extensions := make([]string, 0), make([]string, 0)
+	seenWords, seenExtensions := make(map[string]struct{}), make(map[string]struct{})
+
+	for _, entry := range dictionary {
+		entry = strings.ToLower(path.Base(strings.Trim(entry, "/")))
+
+		word, extension := entry, ""
+		if dot := strings.LastIndex(entry, "."); dot > 0 {
+			word, extension = entry[:dot], entry[dot+1:]
+		}
+
+		if _, found := seenWords[word]; !found {
+			seenWords[word] = struct{}{}
+			words = append(words, word)
+		}
+
+		if _, found := seenExtensions[extension]; !found && extension != "" {
+			seenExtensions[extension] = struct{}{}
+			extensions = append(extensions, extension)
+		}
+	}
+
+	return words, extensions
+}
+
+// wordsStartingWith returns the words starting with the given prefix once stripped of the characters Windows
+// leaves out of the short names.
+func wordsStartingWith(words []string, prefix string) []string {
+	matching := make([]string, 0)
+
+	for _, word := range words {
+		stripped := strings.NewReplacer(" ", "", ".", "").Replace(word)
+		if strings.HasPrefix(stripped, prefix) {
+			matching = append(matching, word)
+		}
+	}
+
+	return matching
+}
@@ -0,0 +1,81 @@
+package discovery_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortNameScannerShouldFindTheShortNames(t *testing.T) {
+	t.Parallel()
+
+	testServer, _ := test.NewIISShortNameServerWithAssertion(
+		[]string{"ADMINI~1.ASP", "ADMINI~2.ASP", "WEB~1.CO", "BACKUP~1"},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+	)
+	defer testServer.Close()
+
+	logger, _ := test.NewLogger()
+
+	sut := discovery.NewShortNameScanner(testServer.Client(), 5, logger)
+
+	shortNames, err := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL))
+	assert.NoError(t, err)
+
+	expected := []discovery.ShortName{
+		{Name: "WEB", Index: 1, Extension: "CO"},
+		{Name: "ADMINI", Index: 1, Extension: "ASP"},
+		{Name: "ADMINI", Index: 2, Extension: "ASP"},
+		{Name: "BACKUP", Index: 1},
+	}
+	assert.ElementsMatch(t, expected, shortNames)
+}
+
+func TestShortNameScannerShouldFailWhenTheShortNamesAreNotDisclosed(t *testing.T) {
+	t.Parallel()
+
+	testServer, serverAssertion := test.NewServerWithAssertion(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer testServer.Close()
+
+	logger, _ := test.NewLogger()
+
+	sut := discovery.NewShortNameScanner(testServer.Client(), 5, logger)
+
+	_, err := sut.Scan(context.Background(), test.MustParseURL(t, testServer.URL))
+	assert.ErrorIs(t, err, discovery.ErrShortNamesNotDisclosed)
+
+	// the calibration with every method
+	assert.Equal(t, 6, serverAssertion.Len())
+}
+
+func TestShortNameHints(t *testing.T) {
+	t.Parallel()
+
+	shortNames := []discovery.ShortName{
+		{Name: "WEB", Index: 1, Extension: "CO"},
+		{Name: "ADMINI", Index: 1, Extension: "ASP"},
+		{Name: "BACKUP", Index: 1},
+	}
+
+	dictionary := []string{"administrator", "admin/panel", "backups", "index.aspx", "web.config", "home"}
+
+	expected := []string{
+		"web~1.co",
+		"web.co",
+		"web.config",
+		"admini~1.asp",
+		"administrator.asp",
+		"administrator.aspx",
+		"backup~1",
+		"backups",
+	}
+	assert.Equal(t, expected, discovery.ShortNameHints(shortNames, dictionary))
+}
@@ -0,0 +1,237 @@
+package discovery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// wildcardProbes is the amount of random subdomains resolved to detect a wildcard DNS record.
+const wildcardProbes = 3
+
+// Resolver looks up the addresses of a host, it is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Subdomain is a subdomain found along with the addresses it resolves to.
+type Subdomain struct {
+	Host      string
+	Addresses []string
+}
+
+// NewSubdomainFinder creates a SubdomainFinder resolving concurrency subdomains at a time.
+func NewSubdomainFinder(resolver Resolver, concurrency int, logger *logrus.Logger) *SubdomainFinder {
+	return &SubdomainFinder{
+		resolver:    resolver,
+		concurrency: concurrency,
+		logger:      logger,
+	}
+}
+
+// SubdomainFinder brute-forces the subdomains of a domain.
+type SubdomainFinder struct {
+	resolver    Resolver
+	concurrency int
+	logger      *logrus.Logger
+}
+
+// Find resolves every word of the dictionary as a subdomain of the given domain and returns the ones found,
+// ordered like the dictionary. When the domain has a wildcard record, the subdomains resolving only to the
+// addresses of the wildcard are left out.
+func (f *SubdomainFinder) Find(ctx context.Context, domain string, dictionary []string) []Subdomain {
+	domain = strings.Trim(strings.ToLower(domain), ".")
+
+	wildcard := f.wildcardAddresses(ctx, domain)
+	if len(wildcard) > 0 {
+		f.logger.WithField("addresses", strings.Join(sortedKeys(wildcard), ",")).
+			Warn("Wildcard DNS detected, the subdomains resolving only to its addresses are ignored")
+	}
+
+	found := make([]*Subdomain, len(dictionary))
+
+	slots := make(chan struct{}, f.concurrency)
+	wg := sync.WaitGroup{}
+
+	for i, word := range dictionary {
+		host, ok := subdomainOf(word, domain)
+		if !ok {
+			f.logger.WithField("word", word).Debug("Not a valid subdomain, skipping")
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			continue
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+
+		go func(index int, host string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			found[index] = f.resolve(ctx, host, wildcard)
+		}(i, host)
+	}
+
+	wg.Wait()
+
+	subdomains := make([]Subdomain, 0)
+
+	for _, s := range found {
+		if s != nil {
+			subdomains = append(subdomains, *s)
+		}
+	}
+
+	return subdomains
+}
+
+func (f *SubdomainFinder) resolve(ctx context.Context, host string, wildcard map[string]struct{}) *Subdomain {
+	addresses, err := f.lookup(ctx, host)
+	if err != nil {
+		f.logger.WithError(err).WithField("host", host).Debug("Subdomain not found")
+
+		return nil
+	}
+
+	if len(wildcard) > 0 && containsOnly(addresses, wildcard) {
+		f.logger.WithField("host", host).Debug("Subdomain resolving to the wildcard, ignoring")
+
+		return nil
+	}
+
+	f.logger.WithFields(logrus.Fields{
+		"host":      host,
+		"addresses": strings.Join(addresses, ","),
+	}).Info("Found")
+
+	return &Subdomain{Host: host, Addresses: addresses}
+}
+
+// wildcardAddresses resolves a few random subdomains, any address they resolve to belongs to a wildcard record.
+func (f *SubdomainFinder) wildcardAddresses(ctx context.Context, domain string) map[string]struct{} {
+	addresses := make(map[string]struct{})
+
+	for i := 0; i < wildcardProbes; i++ {
+		resolved, err := f.lookup(ctx, randomLabel()+"."+domain)
+		if err != nil {
+			continue
+		}
+
+		for _, a := range resolved {
+			addresses[a] = struct{}{}
+		}
+	}
+
+	return addresses
+}
+
+func (f *SubdomainFinder) lookup(ctx context.Context, host string) ([]string, error) {
+	// the trailing dot keeps the resolver from trying the search domains of the system
+	addresses, err := f.resolver.LookupHost(ctx, host+".")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(addresses)
+
+	return addresses, nil
+}
+
+// subdomainOf builds the subdomain of the given word, which must be made of valid DNS labels.
+func subdomainOf(word, domain string) (string, bool) {
+	word = strings.Trim(strings.ToLower(strings.TrimSpace(word)), ".")
+	if word == "" {
+		return "", false
+	}
+
+	for _, label := range strings.Split(word, ".") {
+		if !isValidLabel(label) {
+			return "", false
+		}
+	}
+
+	return word + "." + domain, true
+}
+
+func isValidLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+
+	for _, r := range label {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsOnly(addresses []string, set map[string]struct{}) bool {
+	for _, a := range addresses {
+		if _, ok := set[a]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func randomLabel() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// NewResolver creates a Resolver querying the given DNS server (eg: 8.8.8.8:53), or the one of the system
+// when empty, giving up on a lookup after the given timeout.
+func NewResolver(server string, timeout time.Duration) Resolver {
+	r := &net.Resolver{}
+
+	if server != "" {
+		r.PreferGo = true
+		r.Dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{}
+
+			return d.DialContext(ctx, network, server)
+		}
+	}
+
+	return timeoutResolver{resolver: r, timeout: timeout}
+}
+
+type timeoutResolver struct {
+	resolver Resolver
+	timeout  time.Duration
+}
+
+func (r timeoutResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	return r.resolver.LookupHost(ctx, host)
+}
@@ -0,0 +1,99 @@
+package discovery_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubdomainFinderShouldFindTheSubdomains(t *testing.T) {
+	t.Parallel()
+
+	logger, _ := test.NewLogger()
+
+	resolver := resolverFunc(func(ctx context.Context, host string) ([]string, error) {
+		switch host {
+		case "www.example.com.":
+			return []string{"10.0.0.1"}, nil
+		case "api.dev.example.com.":
+			return []string{"10.0.0.3", "10.0.0.2"}, nil
+		default:
+			return nil, errors.New("no such host")
+		}
+	})
+
+	sut := discovery.NewSubdomainFinder(resolver, 2, logger)
+
+	subdomains := sut.Find(context.Background(), "Example.com.", []string{"www", "mail", "api.dev", "home/index.php"})
+	assert.Equal(
+		t,
+		[]discovery.Subdomain{
+			{Host: "www.example.com", Addresses: []string{"10.0.0.1"}},
+			{Host: "api.dev.example.com", Addresses: []string{"10.0.0.2", "10.0.0.3"}},
+		},
+		subdomains,
+	)
+}
+
+func TestSubdomainFinderShouldIgnoreTheSubdomainsResolvingToTheWildcard(t *testing.T) {
+	t.Parallel()
+
+	logger, loggerBuffer := test.NewLogger()
+
+	resolver := resolverFunc(func(ctx context.Context, host string) ([]string, error) {
+		switch host {
+		case "www.example.com.":
+			return []string{"10.0.0.1"}, nil
+		case "mail.example.com.":
+			return []string{"10.0.0.9", "10.0.0.2"}, nil
+		default:
+			return []string{"10.0.0.9"}, nil
+		}
+	})
+
+	sut := discovery.NewSubdomainFinder(resolver, 2, logger)
+
+	subdomains := sut.Find(context.Background(), "example.com", []string{"www", "mail", "blabla"})
+	assert.Equal(
+		t,
+		[]discovery.Subdomain{
+			{Host: "www.example.com", Addresses: []string{"10.0.0.1"}},
+			{Host: "mail.example.com", Addresses: []string{"10.0.0.2", "10.0.0.9"}},
+		},
+		subdomains,
+	)
+
+	assert.Contains(t, loggerBuffer.String(), "Wildcard DNS detected")
+}
+
+func TestResolverShouldQueryTheGivenServer(t *testing.T) {
+	t.Parallel()
+
+	server := test.NewDNSServer(t, map[string][]string{"www.example.com": {"10.0.0.1"}})
+	defer server.Close()
+
+	sut := discovery.NewResolver(server.Addr(), time.Second)
+
+	addresses, err := sut.LookupHost(context.Background(), "www.example.com.")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, addresses)
+
+	_, err = sut.LookupHost(context.Background(), "mail.example.com.")
+	assert.Error(t, err)
+
+	for _, q := range server.Queries() {
+		assert.True(t, strings.HasSuffix(q, ".example.com."), q)
+	}
+}
+
+type resolverFunc func(ctx context.Context, host string) ([]string, error)
+
+func (f resolverFunc) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return f(ctx, host)
+}
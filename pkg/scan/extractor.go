@@ -0,0 +1,31 @@
+package scan
+
+// Extractor pulls values out of the body of a response, grouped by name.
+type Extractor interface {
+	Extract(body []byte) map[string][]string
+}
+
+// ScannerOption allows to customize the behaviour of the Scanner.
+type ScannerOption func(*Scanner)
+
+// WithExtractor reads the body of every result and stores the values found by the extractor in it.
+func WithExtractor(extractor Extractor) ScannerOption {
+	return func(s *Scanner) {
+		s.extractor = extractor
+	}
+}
+
+// WithSecretExtractor reads the body of every result and flags with high severity the ones exposing secrets.
+func WithSecretExtractor(extractor Extractor) ScannerOption {
+	return func(s *Scanner) {
+		s.secretExtractor = extractor
+	}
+}
+
+// WithMaxBodySize limits the bytes of every response body read by the extractors and the classifier,
+// the rest of the body is discarded without being downloaded.
+func WithMaxBodySize(maxBodySize int64) ScannerOption {
+	return func(s *Scanner) {
+		s.maxBodySize = maxBodySize
+	}
+}
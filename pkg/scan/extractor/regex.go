@@ -0,0 +1,76 @@
+package extractor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// NewRegexExtractor builds an extractor storing the capture groups of the given regular expressions.
+// Named groups are stored under their name, the others under `<pattern>#<group index>`.
+func NewRegexExtractor(patterns []string) (RegexExtractor, error) {
+	expressions := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return RegexExtractor{}, errors.Wrapf(err, "invalid extraction regex `%s`", pattern)
+		}
+
+		if expression.NumSubexp() == 0 {
+			return RegexExtractor{}, errors.Errorf("extraction regex `%s` has no capture group", pattern)
+		}
+
+		expressions = append(expressions, expression)
+	}
+
+	return RegexExtractor{expressions: expressions}, nil
+}
+
+type RegexExtractor struct {
+	expressions []*regexp.Regexp
+}
+
+func (e RegexExtractor) Extract(body []byte) map[string][]string {
+	var extracted map[string][]string
+
+	for _, expression := range e.expressions {
+		names := expression.SubexpNames()
+
+		for _, match := range expression.FindAllSubmatch(body, -1) {
+			for i := 1; i < len(match); i++ {
+				if match[i] == nil {
+					continue
+				}
+
+				if extracted == nil {
+					extracted = make(map[string][]string)
+				}
+
+				key := groupKey(expression, names, i)
+				extracted[key] = appendUnique(extracted[key], string(match[i]))
+			}
+		}
+	}
+
+	return extracted
+}
+
+func groupKey(expression *regexp.Regexp, names []string, index int) string {
+	if names[index] != "" {
+		return names[index]
+	}
+
+	return fmt.Sprintf("%s#%d", expression.String(), index)
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,46 @@
+package extractor_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/extractor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexExtractorShouldStoreCaptureGroups(t *testing.T) {
+	sut, err := extractor.NewRegexExtractor([]string{
+		`api_key=([A-Z0-9]+)`,
+		`version (?P<version>\d+\.\d+)(-(?P<build>\w+))?`,
+	})
+	assert.NoError(t, err)
+
+	body := []byte("api_key=ABC123 api_key=XYZ789 api_key=ABC123\nversion 1.2-b42, version 1.3")
+
+	assert.Equal(
+		t,
+		map[string][]string{
+			"api_key=([A-Z0-9]+)#1": {"ABC123", "XYZ789"},
+			"version":               {"1.2", "1.3"},
+			"build":                 {"b42"},
+			`version (?P<version>\d+\.\d+)(-(?P<build>\w+))?#2`: {"-b42"},
+		},
+		sut.Extract(body),
+	)
+}
+
+func TestRegexExtractorShouldReturnNilWhenNothingMatches(t *testing.T) {
+	sut, err := extractor.NewRegexExtractor([]string{`token=(\w+)`})
+	assert.NoError(t, err)
+
+	assert.Nil(t, sut.Extract([]byte("nothing to see here")))
+}
+
+func TestNewRegexExtractorShouldErrForInvalidRegex(t *testing.T) {
+	_, err := extractor.NewRegexExtractor([]string{`token=(\w+`})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid extraction regex")
+
+	_, err = extractor.NewRegexExtractor([]string{`token=\w+`})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no capture group")
+}
@@ -0,0 +1,94 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SecretPattern describes a kind of secret to look for in the response bodies.
+type SecretPattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// DefaultSecretPatterns returns the built-in secret patterns.
+func DefaultSecretPatterns() []SecretPattern {
+	return []SecretPattern{
+		{Name: "aws-access-key-id", Regexp: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{
+			Name:   "aws-secret-access-key",
+			Regexp: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+		},
+		{
+			Name: "private-key",
+			Regexp: regexp.MustCompile(
+				`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`,
+			),
+		},
+		{
+			Name: "connection-string",
+			Regexp: regexp.MustCompile(
+				`\b(?:postgres(?:ql)?|mysql|mongodb(?:\+srv)?|redis|amqps?|mssql|sqlserver)://[^\s:@/]+:[^\s@/]+@[^\s"'<>]+`,
+			),
+		},
+		{
+			Name:   "ado-connection-string",
+			Regexp: regexp.MustCompile(`(?i)\b(?:server|data source)=[^;"'<>]+;[^"'<>\n]*?(?:password|pwd)=[^;"'<>\s]+`),
+		},
+		{Name: "github-token", Regexp: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`)},
+		{Name: "slack-token", Regexp: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+		{Name: "google-api-key", Regexp: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+		{
+			Name:   "password-assignment",
+			Regexp: regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|secret|api_?key)["']?\s*[:=]\s*["']?([^\s"'<>,;]{6,})`),
+		},
+	}
+}
+
+// ParseSecretPattern parses a custom secret pattern in the format name=regex.
+func ParseSecretPattern(raw string) (SecretPattern, error) {
+	parts := strings.SplitN(raw, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return SecretPattern{}, errors.Errorf("secret pattern `%s` must be in the format name=regex", raw)
+	}
+
+	expression, err := regexp.Compile(parts[1])
+	if err != nil {
+		return SecretPattern{}, errors.Wrapf(err, "invalid regex for secret pattern `%s`", parts[0])
+	}
+
+	return SecretPattern{Name: parts[0], Regexp: expression}, nil
+}
+
+// NewSecretExtractor builds an extractor storing the secrets found under the name of the pattern matching them.
+// When a pattern has capture groups the first one is stored, otherwise the whole match.
+func NewSecretExtractor(patterns []SecretPattern) SecretExtractor {
+	return SecretExtractor{patterns: patterns}
+}
+
+type SecretExtractor struct {
+	patterns []SecretPattern
+}
+
+func (e SecretExtractor) Extract(body []byte) map[string][]string {
+	var secrets map[string][]string
+
+	for _, pattern := range e.patterns {
+		for _, match := range pattern.Regexp.FindAllSubmatch(body, -1) {
+			secret := match[0]
+			if len(match) > 1 && match[1] != nil {
+				secret = match[1]
+			}
+
+			if secrets == nil {
+				secrets = make(map[string][]string)
+			}
+
+			secrets[pattern.Name] = appendUnique(secrets[pattern.Name], string(secret))
+		}
+	}
+
+	return secrets
+}
@@ -27,3 +27,24 @@ func (f HTTPStatusResultFilter) ShouldIgnore(result scan.Result) bool {
 
 	return found
 }
+
+func NewStatusRangeResultFilter(included, excluded []scan.Range) StatusRangeResultFilter {
+	return StatusRangeResultFilter{included: included, excluded: excluded}
+}
+
+// StatusRangeResultFilter ignores the results whose status code is not within the included ranges, when
+// any is set, and the ones whose status code is within the excluded ranges.
+type StatusRangeResultFilter struct {
+	included []scan.Range
+	excluded []scan.Range
+}
+
+func (f StatusRangeResultFilter) ShouldIgnore(result scan.Result) bool {
+	statusCode := int64(result.StatusCode)
+
+	if len(f.included) > 0 && !scan.InRanges(f.included, statusCode) {
+		return true
+	}
+
+	return scan.InRanges(f.excluded, statusCode)
+}
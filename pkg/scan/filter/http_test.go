@@ -69,3 +69,52 @@ func TestHTTPStatusResultFilterShouldWorkConcurrently(_ *testing.T) {
 
 	wg.Wait()
 }
+
+func TestStatusRangeResultFilter(t *testing.T) {
+	testCases := []struct {
+		scenario       string
+		included       []scan.Range
+		excluded       []scan.Range
+		statusCode     int
+		expectedResult bool
+	}{
+		{scenario: "no ranges", statusCode: http.StatusNotFound, expectedResult: false},
+		{
+			scenario:       "included",
+			included:       []scan.Range{{Min: 200, Max: 299}, {Min: 401, Max: 401}},
+			statusCode:     http.StatusUnauthorized,
+			expectedResult: false,
+		},
+		{
+			scenario:       "not included",
+			included:       []scan.Range{{Min: 200, Max: 299}, {Min: 401, Max: 401}},
+			statusCode:     http.StatusForbidden,
+			expectedResult: true,
+		},
+		{
+			scenario:       "excluded",
+			excluded:       []scan.Range{{Min: 400, Max: 499}},
+			statusCode:     http.StatusForbidden,
+			expectedResult: true,
+		},
+		{
+			scenario:       "included but excluded",
+			included:       []scan.Range{{Min: 200, Max: 299}},
+			excluded:       []scan.Range{{Min: 204, Max: 204}},
+			statusCode:     http.StatusNoContent,
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewStatusRangeResultFilter(tc.included, tc.excluded).
+				ShouldIgnore(scan.Result{StatusCode: tc.statusCode})
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
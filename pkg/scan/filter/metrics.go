@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// Metrics are ranges of sizes, words and lines of the responses, an empty list of ranges being ignored.
+type Metrics struct {
+	Size  []scan.Range
+	Words []scan.Range
+	Lines []scan.Range
+}
+
+// NeedsBody tells whether the words or the lines of the responses have to be counted.
+func (m Metrics) NeedsBody() bool {
+	return len(m.Words) > 0 || len(m.Lines) > 0
+}
+
+func NewMetricsResultFilter(filtered, matched Metrics) MetricsResultFilter {
+	return MetricsResultFilter{filtered: filtered, matched: matched}
+}
+
+// MetricsResultFilter ignores the results whose size, words or lines are within the filtered ranges, and the
+// ones outside of the matched ranges, the size of the results not telling it is never within any range.
+type MetricsResultFilter struct {
+	filtered Metrics
+	matched  Metrics
+}
+
+func (f MetricsResultFilter) ShouldIgnore(result scan.Result) bool {
+	values := []struct {
+		value    int64
+		filtered []scan.Range
+		matched  []scan.Range
+	}{
+		{value: result.ContentLength, filtered: f.filtered.Size, matched: f.matched.Size},
+		{value: int64(result.Words), filtered: f.filtered.Words, matched: f.matched.Words},
+		{value: int64(result.Lines), filtered: f.filtered.Lines, matched: f.matched.Lines},
+	}
+
+	for _, v := range values {
+		if scan.InRanges(v.filtered, v.value) {
+			return true
+		}
+
+		if len(v.matched) > 0 && !scan.InRanges(v.matched, v.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewChainResultFilter(filters ...scan.ResultFilter) ChainResultFilter {
+	return ChainResultFilter{filters: filters}
+}
+
+// ChainResultFilter ignores the results ignored by any of its filters.
+type ChainResultFilter struct {
+	filters []scan.ResultFilter
+}
+
+func (f ChainResultFilter) ShouldIgnore(result scan.Result) bool {
+	for _, resultFilter := range f.filters {
+		if resultFilter.ShouldIgnore(result) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsResultFilter(t *testing.T) {
+	testCases := []struct {
+		scenario       string
+		filtered       filter.Metrics
+		matched        filter.Metrics
+		result         scan.Result
+		expectedResult bool
+	}{
+		{
+			scenario:       "no ranges",
+			result:         scan.Result{ContentLength: 10, Words: 2, Lines: 1},
+			expectedResult: false,
+		},
+		{
+			scenario:       "filtered size",
+			filtered:       filter.Metrics{Size: []scan.Range{{Min: 0, Max: 0}, {Min: 5, Max: 10}}},
+			result:         scan.Result{ContentLength: 10},
+			expectedResult: true,
+		},
+		{
+			scenario:       "size not filtered",
+			filtered:       filter.Metrics{Size: []scan.Range{{Min: 5, Max: 10}}},
+			result:         scan.Result{ContentLength: 11},
+			expectedResult: false,
+		},
+		{
+			scenario:       "unknown size not filtered",
+			filtered:       filter.Metrics{Size: []scan.Range{{Min: 0, Max: 10}}},
+			result:         scan.Result{ContentLength: -1},
+			expectedResult: false,
+		},
+		{
+			scenario:       "filtered words",
+			filtered:       filter.Metrics{Words: []scan.Range{{Min: 42, Max: 42}}},
+			result:         scan.Result{ContentLength: 100, Words: 42, Lines: 3},
+			expectedResult: true,
+		},
+		{
+			scenario:       "filtered lines",
+			filtered:       filter.Metrics{Lines: []scan.Range{{Min: 3, Max: 3}}},
+			result:         scan.Result{ContentLength: 100, Words: 42, Lines: 3},
+			expectedResult: true,
+		},
+		{
+			scenario:       "matched words",
+			matched:        filter.Metrics{Words: []scan.Range{{Min: 10, Max: 50}}},
+			result:         scan.Result{Words: 42},
+			expectedResult: false,
+		},
+		{
+			scenario:       "words not matched",
+			matched:        filter.Metrics{Words: []scan.Range{{Min: 10, Max: 20}}},
+			result:         scan.Result{Words: 42},
+			expectedResult: true,
+		},
+		{
+			scenario:       "unknown size not matched",
+			matched:        filter.Metrics{Size: []scan.Range{{Min: 0, Max: 10}}},
+			result:         scan.Result{ContentLength: -1},
+			expectedResult: true,
+		},
+		{
+			scenario:       "matched but filtered",
+			filtered:       filter.Metrics{Lines: []scan.Range{{Min: 1, Max: 1}}},
+			matched:        filter.Metrics{Words: []scan.Range{{Min: 0, Max: 50}}},
+			result:         scan.Result{Words: 42, Lines: 1},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual := filter.NewMetricsResultFilter(tc.filtered, tc.matched).ShouldIgnore(tc.result)
+			assert.Equal(t, tc.expectedResult, actual)
+		})
+	}
+}
+
+func TestChainResultFilterShouldIgnoreWhatAnyFilterIgnores(t *testing.T) {
+	sut := filter.NewChainResultFilter(
+		filter.NewHTTPStatusResultFilter([]int{http.StatusNotFound}, false),
+		filter.NewMetricsResultFilter(filter.Metrics{Words: []scan.Range{{Min: 3, Max: 3}}}, filter.Metrics{}),
+	)
+
+	assert.True(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusNotFound, Words: 1}))
+	assert.True(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusOK, Words: 3}))
+	assert.False(t, sut.ShouldIgnore(scan.Result{StatusCode: http.StatusOK, Words: 1}))
+}
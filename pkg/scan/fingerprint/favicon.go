@@ -0,0 +1,96 @@
+package fingerprint
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// faviconHashes are the favicon hashes of the technologies shipping a default favicon.
+var faviconHashes = map[int32]string{
+	116323821:  "Spring Boot",
+	81586312:   "Jenkins",
+	-297069493: "Apache Tomcat",
+}
+
+// FaviconHash returns the hash of the favicon the way Shodan computes it (http.favicon.hash), so that it can
+// be looked up there: the MurmurHash3 of the favicon encoded in base64, split in lines of 76 characters.
+func FaviconHash(favicon []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(favicon)
+
+	b := strings.Builder{}
+
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\n")
+		encoded = encoded[76:]
+	}
+
+	b.WriteString(encoded + "\n")
+
+	return int32(murmur3([]byte(b.String())))
+}
+
+// FingerprintFavicon returns the technology shipping the given favicon by default, if any.
+func FingerprintFavicon(favicon []byte) (Technology, bool) {
+	hash := FaviconHash(favicon)
+
+	name, found := faviconHashes[hash]
+	if !found {
+		return Technology{}, false
+	}
+
+	return Technology{Name: name, Evidence: "favicon " + strconv.Itoa(int(hash))}, true
+}
+
+// murmur3 is the 32 bits MurmurHash3 with a seed of 0.
+func murmur3(data []byte) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	h := uint32(0)
+
+	blocks := len(data) / 4
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	tail := data[blocks*4:]
+	k := uint32(0)
+
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+
+	return h
+}
@@ -0,0 +1,175 @@
+package fingerprint
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	sourceHeader = "header"
+	sourceCookie = "cookie"
+	sourceBody   = "body"
+)
+
+// Technology is a server or framework technology recognized in a response.
+type Technology struct {
+	Name    string
+	Version string `json:",omitempty"`
+	// Evidence tells what the technology was recognized by, eg: header Server.
+	Evidence string
+}
+
+func (t Technology) String() string {
+	if t.Version == "" {
+		return t.Name
+	}
+
+	return t.Name + " " + t.Version
+}
+
+// rule recognizes a technology by a header, a cookie or the body of a response, the first group of the
+// pattern capturing its version when any.
+type rule struct {
+	name    string
+	source  string
+	key     string
+	pattern *regexp.Regexp
+}
+
+func headerRule(name, header, pattern string) rule {
+	return rule{name: name, source: sourceHeader, key: header, pattern: regexp.MustCompile(pattern)}
+}
+
+func cookieRule(name, cookiePrefix string) rule {
+	return rule{name: name, source: sourceCookie, key: cookiePrefix}
+}
+
+func bodyRule(name, pattern string) rule {
+	return rule{name: name, source: sourceBody, pattern: regexp.MustCompile(pattern)}
+}
+
+var rules = []rule{
+	headerRule("nginx", "Server", `(?i)nginx(?:/([\d.]+))?`),
+	headerRule("OpenResty", "Server", `(?i)openresty(?:/([\d.]+))?`),
+	headerRule("Apache HTTP Server", "Server", `^Apache(?:/([\d.]+))?(?:\s|$)`),
+	headerRule("Apache Tomcat", "Server", `Apache-Coyote`),
+	headerRule("IIS", "Server", `Microsoft-IIS(?:/([\d.]+))?`),
+	headerRule("LiteSpeed", "Server", `(?i)litespeed`),
+	headerRule("Caddy", "Server", `Caddy`),
+	headerRule("Kestrel", "Server", `Kestrel`),
+	headerRule("Jetty", "Server", `Jetty(?:\(([\w.-]+)\))?`),
+	headerRule("Gunicorn", "Server", `gunicorn(?:/([\d.]+))?`),
+	headerRule("Cloudflare", "Server", `(?i)cloudflare`),
+	headerRule("PHP", "X-Powered-By", `PHP(?:/([\d.]+))?`),
+	headerRule("ASP.NET", "X-Powered-By", `ASP\.NET`),
+	headerRule("ASP.NET", "X-AspNet-Version", `([\d.]+)`),
+	headerRule("ASP.NET MVC", "X-AspNetMvc-Version", `([\d.]+)`),
+	headerRule("Express", "X-Powered-By", `Express`),
+	headerRule("Next.js", "X-Powered-By", `Next\.js(?: ([\d.]+))?`),
+	headerRule("Java Servlet", "X-Powered-By", `Servlet(?:/([\d.]+))?`),
+	headerRule("Drupal", "X-Generator", `Drupal(?: ([\d.]+))?`),
+	headerRule("Drupal", "X-Drupal-Cache", `.`),
+	headerRule("Jenkins", "X-Jenkins", `([\d.]+)`),
+	cookieRule("PHP", "PHPSESSID"),
+	cookieRule("Java", "JSESSIONID"),
+	cookieRule("ASP.NET", "ASP.NET_SessionId"),
+	cookieRule("ASP", "ASPSESSIONID"),
+	cookieRule("Laravel", "laravel_session"),
+	cookieRule("CodeIgniter", "ci_session"),
+	cookieRule("Django", "csrftoken"),
+	cookieRule("Express", "connect.sid"),
+	cookieRule("WordPress", "wordpress_"),
+	bodyRule("WordPress", `<meta name="generator" content="WordPress ([\d.]+)`),
+	bodyRule("WordPress", `/wp-(?:content|includes)/`),
+	bodyRule("Drupal", `Drupal\.settings|/sites/default/files/`),
+	bodyRule("Joomla", `<meta name="generator" content="Joomla`),
+	bodyRule("Next.js", `__NEXT_DATA__`),
+	bodyRule("Nuxt.js", `__NUXT__`),
+	bodyRule("Angular", `ng-version="([\d.]+)"`),
+	bodyRule("React", `data-reactroot`),
+	bodyRule("Spring Boot", `Whitelabel Error Page`),
+}
+
+// Fingerprint returns the technologies recognized by the headers, the cookies and the body of a response, a
+// technology recognized several times being returned once, with its version when any reveals it.
+func Fingerprint(header http.Header, body []byte) []Technology {
+	recognized := make(map[string]Technology)
+
+	for _, r := range rules {
+		technology, ok := r.match(header, body)
+		if !ok {
+			continue
+		}
+
+		if known, found := recognized[r.name]; found && (known.Version != "" || technology.Version == "") {
+			continue
+		}
+
+		recognized[r.name] = technology
+	}
+
+	return sorted(recognized)
+}
+
+func (r rule) match(header http.Header, body []byte) (Technology, bool) {
+	technology := Technology{Name: r.name, Evidence: r.source + " " + r.key}
+
+	switch r.source {
+	case sourceHeader:
+		for _, value := range header.Values(r.key) {
+			if groups := r.pattern.FindStringSubmatch(value); groups != nil {
+				return withVersion(technology, groups), true
+			}
+		}
+	case sourceCookie:
+		for _, cookie := range (&http.Response{Header: header}).Cookies() {
+			if strings.HasPrefix(cookie.Name, r.key) {
+				return technology, true
+			}
+		}
+	case sourceBody:
+		if groups := r.pattern.FindSubmatch(body); groups != nil {
+			technology.Evidence = r.source + " " + string(groups[0])
+
+			return withVersion(technology, stringGroups(groups)), true
+		}
+	}
+
+	return Technology{}, false
+}
+
+func withVersion(technology Technology, groups []string) Technology {
+	if len(groups) > 1 {
+		technology.Version = groups[1]
+	}
+
+	return technology
+}
+
+func stringGroups(groups [][]byte) []string {
+	s := make([]string, 0, len(groups))
+	for _, g := range groups {
+		s = append(s, string(g))
+	}
+
+	return s
+}
+
+func sorted(technologies map[string]Technology) []Technology {
+	if len(technologies) == 0 {
+		return nil
+	}
+
+	s := make([]Technology, 0, len(technologies))
+	for _, t := range technologies {
+		s = append(s, t)
+	}
+
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Name < s[j].Name
+	})
+
+	return s
+}
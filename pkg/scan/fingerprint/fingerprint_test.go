@@ -0,0 +1,65 @@
+package fingerprint_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/fingerprint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set("Server", "nginx/1.18.0 (Ubuntu)")
+	header.Set("X-Powered-By", "PHP/7.4.3")
+	header.Add("Set-Cookie", "PHPSESSID=abc; path=/")
+	header.Add("Set-Cookie", "wordpress_test_cookie=WP+Cookie+check; path=/")
+
+	body := []byte(`<html><head><meta name="generator" content="WordPress 5.8.1" />` +
+		`<link rel="stylesheet" href="/wp-content/themes/style.css"></head></html>`)
+
+	expected := []fingerprint.Technology{
+		{Name: "PHP", Version: "7.4.3", Evidence: "header X-Powered-By"},
+		{Name: "WordPress", Version: "5.8.1", Evidence: `body <meta name="generator" content="WordPress 5.8.1`},
+		{Name: "nginx", Version: "1.18.0", Evidence: "header Server"},
+	}
+	assert.Equal(t, expected, fingerprint.Fingerprint(header, body))
+}
+
+func TestFingerprintShouldTellApartTheServers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		server   string
+		expected fingerprint.Technology
+	}{
+		{server: "Apache/2.4.41 (Ubuntu)", expected: fingerprint.Technology{Name: "Apache HTTP Server", Version: "2.4.41"}},
+		{server: "Apache", expected: fingerprint.Technology{Name: "Apache HTTP Server"}},
+		{server: "Apache-Coyote/1.1", expected: fingerprint.Technology{Name: "Apache Tomcat"}},
+		{server: "Microsoft-IIS/10.0", expected: fingerprint.Technology{Name: "IIS", Version: "10.0"}},
+	}
+
+	for _, tc := range testCases {
+		header := http.Header{}
+		header.Set("Server", tc.server)
+
+		tc.expected.Evidence = "header Server"
+
+		assert.Equal(t, []fingerprint.Technology{tc.expected}, fingerprint.Fingerprint(header, nil), tc.server)
+	}
+}
+
+func TestFingerprintShouldReturnNilWhenNothingIsRecognized(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, fingerprint.Fingerprint(http.Header{}, []byte("<html></html>")))
+}
+
+func TestFingerprintFaviconShouldIgnoreTheUnknownFavicons(t *testing.T) {
+	t.Parallel()
+
+	_, found := fingerprint.FingerprintFavicon([]byte("not a known favicon"))
+	assert.False(t, found)
+}
@@ -0,0 +1,38 @@
+package scan
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FuzzKeyword marks where the paths of the dictionary are placed in the URL to scan, eg:
+// https://example.com/api/v1/FUZZ/config or https://example.com/?page=FUZZ, where they are
+// query escaped, in the body, see WithBodyTemplate, or in the Host header, see WithHost;
+// without it they are appended to the URL.
+const FuzzKeyword = "FUZZ"
+
+// isFuzzing tells whether the paths are placed where the FuzzKeyword is rather than appended to the URL.
+func (s *Scanner) isFuzzing(baseURL url.URL) bool {
+	return strings.Contains(baseURL.Path, FuzzKeyword) ||
+		strings.Contains(baseURL.RawQuery, FuzzKeyword) ||
+		strings.Contains(s.bodyTemplate, FuzzKeyword) ||
+		strings.Contains(s.host, FuzzKeyword)
+}
+
+// fuzzURL places the path of the target where the FuzzKeyword is.
+func fuzzURL(baseURL url.URL, target Target) url.URL {
+	word := strings.TrimPrefix(target.Path, "/")
+
+	baseURL.Path = strings.ReplaceAll(baseURL.Path, FuzzKeyword, word)
+	baseURL.RawPath = ""
+	baseURL.RawQuery = strings.ReplaceAll(baseURL.RawQuery, FuzzKeyword, url.QueryEscape(word))
+
+	return baseURL
+}
+
+// fuzzingValues tells whether the paths of the dictionary are only placed in the query, the body or the Host
+// header, eg: as the values of a parameter, which have no sub paths to recurse into nor trailing slash or
+// backup variants.
+func (s *Scanner) fuzzingValues() bool {
+	return s.fuzzingURL != nil && !strings.Contains(s.fuzzingURL.Path, FuzzKeyword)
+}
@@ -0,0 +1,36 @@
+package scan
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithHost sends every request with the given Host header while connecting to the host of the target URL,
+// eg: to scan a virtual host by the IP of the server. The redirects pointing to that host are followed.
+// The paths of the dictionary are placed where the FuzzKeyword is, eg: FUZZ.example.com to discover the
+// virtual hosts of the server, best along with WithCalibration to ignore the ones answered as the unknown hosts.
+func WithHost(host string) ScannerOption {
+	return func(s *Scanner) {
+		s.host = host
+	}
+}
+
+// errInvalidHost is returned when the word placed in the Host header cannot be part of a host name.
+var errInvalidHost = errors.New("not a valid host")
+
+// setHost sets the Host header of the request, placing the given word where the FuzzKeyword is.
+func (s *Scanner) setHost(req *http.Request, word string) error {
+	if s.host == "" {
+		return nil
+	}
+
+	if strings.Contains(s.host, FuzzKeyword) && strings.ContainsAny(word, "/\\?#@: ") {
+		return errors.Wrapf(errInvalidHost, "%s cannot be placed in the Host header", word)
+	}
+
+	req.Host = strings.ReplaceAll(s.host, FuzzKeyword, word)
+
+	return nil
+}
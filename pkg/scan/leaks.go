@@ -0,0 +1,144 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LabelLeak is the label of the results exposing the version control or the metadata files of a directory,
+// see WithLeakChecks.
+const LabelLeak = "leak"
+
+var (
+	gitHEADRegex  = regexp.MustCompile(`^(ref: refs/|[0-9a-f]{40}\s*$)`)
+	svnEntryRegex = regexp.MustCompile(`^\d+\s*\n`)
+	envVarRegex   = regexp.MustCompile(`(?m)^\s*(export\s+)?[A-Za-z_][A-Za-z0-9_]*=`)
+)
+
+// leakCheck is a file exposing the content of a directory, recognized by its content not to mistake the
+// catch-all pages for it.
+type leakCheck struct {
+	name    string
+	path    string
+	matches func(body []byte) bool
+}
+
+var leakChecks = []leakCheck{
+	{name: "git", path: ".git/HEAD", matches: gitHEADRegex.Match},
+	{name: "svn", path: ".svn/wc.db", matches: func(body []byte) bool {
+		return bytes.HasPrefix(body, []byte("SQLite format 3\x00"))
+	}},
+	{name: "svn", path: ".svn/entries", matches: func(body []byte) bool {
+		return svnEntryRegex.Match(body) && bytes.Contains(body, []byte("\ndir\n"))
+	}},
+	{name: "hg", path: ".hg/requires", matches: func(body []byte) bool {
+		return bytes.Contains(body, []byte("revlogv1"))
+	}},
+	{name: "ds-store", path: ".DS_Store", matches: func(body []byte) bool {
+		return len(body) >= 8 && string(body[4:8]) == "Bud1"
+	}},
+	{name: "env", path: ".env", matches: func(body []byte) bool {
+		return envVarRegex.Match(body) && !bytes.Contains(bytes.ToLower(body), []byte("<html"))
+	}},
+}
+
+// WithLeakChecks looks for the exposed .git/, .svn/ and .hg/ folders and the exposed .DS_Store and .env files
+// in the root of the target and in every directory found; the leaks are reported with a high severity.
+func WithLeakChecks() ScannerOption {
+	return func(s *Scanner) {
+		s.leakChecks = true
+	}
+}
+
+// leaksChecked records the directories checked for leaks, not to check them again when they are found
+// through several paths, eg: /admin and /admin/.
+type leaksChecked struct {
+	mx          sync.Mutex
+	directories map[string]struct{}
+}
+
+func (c *leaksChecked) add(directory string) bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.directories == nil {
+		c.directories = make(map[string]struct{})
+	}
+
+	if _, found := c.directories[directory]; found {
+		return false
+	}
+
+	c.directories[directory] = struct{}{}
+
+	return true
+}
+
+// checkLeaks requests the leak checks in the given directory, the responses are evaluated by their content
+// rather than by the filters.
+func (s *Scanner) checkLeaks(
+	ctx context.Context,
+	l *logrus.Entry,
+	baseURL url.URL,
+	directory string,
+	results chan<- Result,
+) {
+	directory = strings.Trim(directory, "/")
+	if !s.leaksChecked.add(directory) {
+		return
+	}
+
+	for _, check := range leakChecks {
+		target := Target{Path: path.Join(directory, check.path), Method: http.MethodGet}
+		u := s.buildURL(baseURL, target)
+
+		req, err := s.newRequest(ctx, u, target)
+		if err != nil {
+			l.WithError(err).Warn("failed to build the leak request")
+
+			continue
+		}
+
+		res, err := s.do(req)
+		if err != nil {
+			l.WithError(err).WithField("leak", check.path).Debug("leak request failed")
+
+			continue
+		}
+
+		atomic.AddUint64(&s.requests, 1)
+
+		result := NewResult(target, res)
+		body := s.readBody(l, res)
+
+		if err := res.Body.Close(); err != nil {
+			l.WithError(err).Warn("failed to close response body")
+		}
+
+		if res.StatusCode != http.StatusOK || !check.matches(body) {
+			continue
+		}
+
+		result.Labels = append(result.Labels, LabelLeak)
+		result.Severity = SeverityHigh
+
+		if s.bodyStore != nil {
+			s.storeBody(l, body, &result)
+		}
+
+		l.WithFields(logrus.Fields{"leak": check.name, "url": u.String()}).Info("Leak found")
+
+		atomic.AddUint64(&s.results, 1)
+
+		results <- result
+	}
+}
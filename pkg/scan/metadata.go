@@ -0,0 +1,34 @@
+package scan
+
+import (
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/fingerprint"
+	"github.com/stefanoj3/dirstalk/pkg/scan/robots"
+)
+
+// Metadata describes a scan, it is stored alongside its results to allow correlating them with an engagement.
+type Metadata struct {
+	Name       string            `json:",omitempty"`
+	Tags       map[string]string `json:",omitempty"`
+	URL        string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Results    int
+	// InterruptionReason is set when the scan was stopped before completing, its results are partial.
+	InterruptionReason string `json:",omitempty"`
+	// TLS is the TLS connection negotiated with the target, it is nil for plain HTTP targets.
+	TLS *TLS `json:",omitempty"`
+	// Certificates are the certificates presented by the hosts reached over TLS, by host.
+	Certificates map[string]Certificate `json:",omitempty"`
+	// Robots are the entries of the robots.txt of the target, when it was fetched.
+	Robots []robots.Entry `json:",omitempty"`
+	// Technologies are the server and framework technologies recognized, when fingerprinting.
+	Technologies []fingerprint.Technology `json:",omitempty"`
+}
+
+// TLS describes the TLS connection negotiated with a target.
+type TLS struct {
+	Version     string
+	CipherSuite string
+}
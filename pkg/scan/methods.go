@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// ProbeMethods returns the methods that can be probed on the paths found, see WithMethodProbing.
+func ProbeMethods() []string {
+	return []string{http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodPatch}
+}
+
+// WithMethodProbing sends the given methods to every path found: the Allow header of the OPTIONS response is
+// reported, while the other methods are reported as permitted when they get a 2xx response, raising the severity
+// of the result. The methods are sent with an empty body, so they can alter the resources of the servers
+// accepting them.
+func WithMethodProbing(methods []string) ScannerOption {
+	return func(s *Scanner) {
+		s.probeMethods = methods
+	}
+}
+
+// methodsProbed keeps track of the URLs already probed, as a path is found once for every method scanned.
+type methodsProbed struct {
+	mx   sync.Mutex
+	urls map[string]struct{}
+}
+
+func (p *methodsProbed) first(u string) bool {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.urls == nil {
+		p.urls = make(map[string]struct{})
+	}
+
+	if _, ok := p.urls[u]; ok {
+		return false
+	}
+
+	p.urls[u] = struct{}{}
+
+	return true
+}
+
+// probeResultMethods sends the methods to probe to the URL of the result, recording the outcome in the result.
+func (s *Scanner) probeResultMethods(ctx context.Context, l *logrus.Entry, req *http.Request, result *Result) {
+	if !s.methodsProbed.first(req.URL.String()) {
+		return
+	}
+
+	for _, method := range s.probeMethods {
+		methodReq, err := http.NewRequestWithContext(ctx, method, req.URL.String(), nil)
+		if err != nil {
+			l.WithError(err).Warn("failed to build the method probe request")
+
+			continue
+		}
+
+		methodReq.Host = req.Host
+
+		// the request of the same method made while scanning would make the probe redundant
+		res, err := s.do(client.SkipRequestCache(methodReq))
+		if err != nil {
+			l.WithError(err).WithField("probe-method", method).Debug("method probe failed")
+
+			continue
+		}
+
+		atomic.AddUint64(&s.requests, 1)
+
+		if err := res.Body.Close(); err != nil {
+			l.WithError(err).Warn("failed to close response body")
+		}
+
+		if method == http.MethodOptions {
+			result.Allow = res.Header.Get("Allow")
+
+			continue
+		}
+
+		if res.StatusCode/100 != 2 {
+			continue
+		}
+
+		result.PermittedMethods = append(result.PermittedMethods, method)
+		result.Severity = EscalateSeverity(result.Severity, SeverityHigh)
+
+		l.WithFields(logrus.Fields{
+			"probe-method": method,
+			"status":       res.StatusCode,
+		}).Warn("Unexpectedly permitted method")
+	}
+
+	if result.Allow != "" {
+		l.WithField("allow", result.Allow).Info("Allowed methods")
+	}
+}
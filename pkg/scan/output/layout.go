@@ -0,0 +1,155 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+const layoutTimeFormat = "20060102T150405Z"
+
+// indexMx serializes the updates of the indexes, as the scans of several targets can end at the same time.
+var indexMx sync.Mutex
+
+// IndexEntry describes a scan stored in a Layout.
+type IndexEntry struct {
+	scan.Metadata
+	Directory string
+}
+
+// Layout organizes the artifacts of the scans in a directory, grouping them by host and start time as
+// <root>/<host>/<timestamp>/, while <root>/index.json lists every scan stored in the directory.
+type Layout struct {
+	root string
+	dir  string
+}
+
+// NewLayout creates the directory storing the artifacts of a scan of the given URL; a numeric suffix is
+// added to it when another scan of the same host started in the same second, eg: <timestamp>-2.
+func NewLayout(root string, u *url.URL, startedAt time.Time) (Layout, error) {
+	host := strings.NewReplacer(":", "_", "/", "_").Replace(u.Host)
+	hostDir := filepath.Join(root, host)
+
+	if err := os.MkdirAll(hostDir, 0o750); err != nil {
+		return Layout{}, errors.Wrapf(err, "failed to create output directory `%s`", hostDir)
+	}
+
+	l := Layout{root: root, dir: filepath.Join(hostDir, startedAt.UTC().Format(layoutTimeFormat))}
+
+	for attempt := 2; ; attempt++ {
+		err := os.Mkdir(l.dir, 0o750)
+		if err == nil {
+			return l, nil
+		}
+
+		if !os.IsExist(err) {
+			return Layout{}, errors.Wrapf(err, "failed to create output directory `%s`", l.dir)
+		}
+
+		l.dir = filepath.Join(hostDir, startedAt.UTC().Format(layoutTimeFormat)+"-"+strconv.Itoa(attempt))
+	}
+}
+
+// Dir returns the directory storing the artifacts of the scan.
+func (l Layout) Dir() string {
+	return l.dir
+}
+
+// ResultsPath returns the path of the file storing the results of the scan.
+func (l Layout) ResultsPath() string {
+	return filepath.Join(l.dir, "results.json")
+}
+
+// MetadataPath returns the path of the file storing the metadata of the scan.
+func (l Layout) MetadataPath() string {
+	return filepath.Join(l.dir, "metadata.json")
+}
+
+// ConfigPath returns the path of the file storing the configuration of the scan.
+func (l Layout) ConfigPath() string {
+	return filepath.Join(l.dir, "config.json")
+}
+
+// BodiesDir returns the directory storing the bodies of the results of the scan.
+func (l Layout) BodiesDir() string {
+	return filepath.Join(l.dir, "bodies")
+}
+
+// Store writes the body of the result in BodiesDir, named after its SHA-256 so that the identical bodies are
+// stored once, and returns its path relative to the directory of the scan; it implements scan.BodyStore.
+func (l Layout) Store(_ scan.Result, body []byte) (string, error) {
+	if err := os.MkdirAll(l.BodiesDir(), 0o750); err != nil {
+		return "", errors.Wrapf(err, "failed to create bodies directory `%s`", l.BodiesDir())
+	}
+
+	sum := sha256.Sum256(body)
+	name := hex.EncodeToString(sum[:])
+
+	path := filepath.Join(l.BodiesDir(), name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := ioutil.WriteFile(path, body, 0o600); err != nil {
+			return "", errors.Wrapf(err, "failed to write `%s`", path)
+		}
+	}
+
+	return "bodies/" + name, nil
+}
+
+// SaveConfig writes the configuration used for the scan.
+func (l Layout) SaveConfig(config interface{}) error {
+	return writeJSON(l.ConfigPath(), config)
+}
+
+// AddToIndex records the scan in the index of the root directory.
+func (l Layout) AddToIndex(metadata scan.Metadata) error {
+	indexPath := filepath.Join(l.root, "index.json")
+
+	indexMx.Lock()
+	defer indexMx.Unlock()
+
+	entries := make([]IndexEntry, 0, 1)
+
+	raw, err := ioutil.ReadFile(indexPath) // #nosec
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to read index `%s`", indexPath)
+	}
+
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return errors.Wrapf(err, "failed to parse index `%s`", indexPath)
+		}
+	}
+
+	directory, err := filepath.Rel(l.root, l.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to compute the scan directory")
+	}
+
+	entries = append(entries, IndexEntry{Metadata: metadata, Directory: filepath.ToSlash(directory)})
+
+	return writeJSON(indexPath, entries)
+}
+
+func writeJSON(path string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert `%s`", path)
+	}
+
+	if err := ioutil.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write `%s`", path)
+	}
+
+	return nil
+}
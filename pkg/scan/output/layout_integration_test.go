@@ -0,0 +1,108 @@
+package output_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutShouldGroupScansByHostAndTime(t *testing.T) {
+	root := t.TempDir()
+	startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	layout, err := output.NewLayout(root, test.MustParseURL(t, "http://localhost:8080/app/"), startedAt)
+	assert.NoError(t, err)
+
+	expectedDir := filepath.Join(root, "localhost_8080", "20200102T030405Z")
+	assert.Equal(t, expectedDir, layout.Dir())
+	assert.Equal(t, filepath.Join(expectedDir, "results.json"), layout.ResultsPath())
+	assert.Equal(t, filepath.Join(expectedDir, "metadata.json"), layout.MetadataPath())
+	assert.Equal(t, filepath.Join(expectedDir, "config.json"), layout.ConfigPath())
+
+	assert.NoError(t, layout.SaveConfig(map[string]int{"Threads": 3}))
+
+	rawConfig, err := ioutil.ReadFile(layout.ConfigPath())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Threads": 3}`, string(rawConfig))
+}
+
+func TestLayoutShouldListEveryScanInTheIndex(t *testing.T) {
+	root := t.TempDir()
+
+	first, err := output.NewLayout(root, test.MustParseURL(t, "http://first.local/"), time.Unix(0, 0))
+	assert.NoError(t, err)
+	assert.NoError(t, first.AddToIndex(scan.Metadata{URL: "http://first.local/", Results: 1}))
+
+	second, err := output.NewLayout(root, test.MustParseURL(t, "https://second.local/"), time.Unix(60, 0))
+	assert.NoError(t, err)
+	assert.NoError(t, second.AddToIndex(scan.Metadata{Name: "nightly", URL: "https://second.local/", Results: 2}))
+
+	raw, err := ioutil.ReadFile(filepath.Join(root, "index.json"))
+	assert.NoError(t, err)
+
+	entries := make([]output.IndexEntry, 0, 2)
+	assert.NoError(t, json.Unmarshal(raw, &entries))
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "first.local/19700101T000000Z", entries[0].Directory)
+	assert.Equal(t, 1, entries[0].Results)
+	assert.Equal(t, "second.local/19700101T000100Z", entries[1].Directory)
+	assert.Equal(t, "nightly", entries[1].Name)
+}
+
+func TestLayoutShouldNotShareTheDirectoryOfScansStartedTogether(t *testing.T) {
+	root := t.TempDir()
+	startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first, err := output.NewLayout(root, test.MustParseURL(t, "http://localhost/app/"), startedAt)
+	assert.NoError(t, err)
+
+	second, err := output.NewLayout(root, test.MustParseURL(t, "http://localhost/api/"), startedAt)
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(root, "localhost", "20200102T030405Z"), first.Dir())
+	assert.Equal(t, filepath.Join(root, "localhost", "20200102T030405Z-2"), second.Dir())
+}
+
+func TestLayoutShouldErrWhenTheIndexIsInvalid(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "index.json"), []byte("{"), 0o600))
+
+	layout, err := output.NewLayout(root, test.MustParseURL(t, "http://localhost/"), time.Now())
+	assert.NoError(t, err)
+
+	err = layout.AddToIndex(scan.Metadata{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse index")
+}
+
+func TestLayoutShouldStoreEveryBodyOnce(t *testing.T) {
+	layout, err := output.NewLayout(t.TempDir(), test.MustParseURL(t, "http://localhost/"), time.Unix(0, 0))
+	assert.NoError(t, err)
+
+	first, err := layout.Store(scan.Result{}, []byte("welcome"))
+	assert.NoError(t, err)
+
+	second, err := layout.Store(scan.Result{}, []byte("welcome"))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other, err := layout.Store(scan.Result{}, []byte("about us"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	body, err := ioutil.ReadFile(filepath.Join(layout.Dir(), filepath.FromSlash(first)))
+	assert.NoError(t, err)
+	assert.Equal(t, "welcome", string(body))
+
+	stored, err := ioutil.ReadDir(layout.BodiesDir())
+	assert.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
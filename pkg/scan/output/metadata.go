@@ -0,0 +1,16 @@
+package output
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+)
+
+// MetadataPath returns the path of the file storing the metadata of the results saved at the given path.
+func MetadataPath(resultsPath string) string {
+	return resultsPath + ".meta.json"
+}
+
+// SaveMetadata writes the metadata of a scan to the given path.
+func SaveMetadata(path string, metadata scan.Metadata) error {
+	return errors.Wrap(writeJSON(path, metadata), "failed to write scan metadata")
+}
@@ -0,0 +1,42 @@
+package output_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveMetadata(t *testing.T) {
+	path := output.MetadataPath(filepath.Join(t.TempDir(), "results.txt"))
+	assert.Equal(t, "results.txt.meta.json", filepath.Base(path))
+
+	metadata := scan.Metadata{
+		Name:       "weekly",
+		Tags:       map[string]string{"engagement": "ACME-42"},
+		URL:        "http://localhost/",
+		StartedAt:  time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC),
+		FinishedAt: time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC),
+		Results:    3,
+	}
+
+	assert.NoError(t, output.SaveMetadata(path, metadata))
+
+	raw, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+
+	saved := scan.Metadata{}
+	assert.NoError(t, json.Unmarshal(raw, &saved))
+	assert.Equal(t, metadata, saved)
+}
+
+func TestSaveMetadataShouldErrWhenInvalidPath(t *testing.T) {
+	err := output.SaveMetadata(filepath.Join(t.TempDir(), "missing", "results.meta.json"), scan.Metadata{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to write scan metadata")
+}
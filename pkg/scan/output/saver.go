@@ -41,10 +41,19 @@ func (f Saver) Save(r scan.Result) error {
 	return errors.Wrapf(err, "Saver: failed to write result: %s", rawResult)
 }
 
+// Close flushes the results written so far to the storage and closes the underlying writer.
 func (f Saver) Close() error {
 	if f.writeCloser == nil {
 		return errNilWriteCloser
 	}
 
+	if syncer, ok := f.writeCloser.(interface{ Sync() error }); ok {
+		if err := syncer.Sync(); err != nil {
+			_ = f.writeCloser.Close()
+
+			return errors.Wrap(err, "Saver: failed to flush results")
+		}
+	}
+
 	return f.writeCloser.Close()
 }
@@ -0,0 +1,57 @@
+package scan
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// methodOverrideHeaders are the headers some frameworks read the method of the request from.
+var methodOverrideHeaders = []string{"X-HTTP-Method-Override", "X-HTTP-Method", "X-Method-Override"}
+
+// WithMethodOverride retries the requests getting a 403 or a 405 response with the headers overriding their
+// method or their path, eg: a POST with X-HTTP-Method-Override: GET or a request to the root with
+// X-Original-URL: /admin, and reports the attempts getting a response below 400.
+func WithMethodOverride() ScannerOption {
+	return func(s *Scanner) {
+		s.methodOverride = true
+	}
+}
+
+// overrideTechniques returns the techniques to retry a request of the given method with.
+func overrideTechniques(method string) []bypassTechnique {
+	// the method to override is carried by a POST, unless it is the one rejected
+	carrier := http.MethodPost
+	if method == http.MethodPost {
+		carrier = http.MethodGet
+	}
+
+	techniques := make([]bypassTechnique, 0, len(methodOverrideHeaders)+2)
+
+	for _, header := range methodOverrideHeaders {
+		technique := withHeader(header, method)
+
+		techniques = append(techniques, bypassTechnique{
+			name: technique.name + " via " + carrier,
+			apply: func(req *http.Request) {
+				technique.apply(req)
+				req.Method = carrier
+			},
+		})
+	}
+
+	return append(techniques, withRewriteHeader("X-Original-URL"), withRewriteHeader("X-Rewrite-URL"))
+}
+
+// retryWithOverrides performs the rejected request again with every override technique and emits the
+// attempts that changed the outcome.
+func (s *Scanner) retryWithOverrides(l *logrus.Entry, req *http.Request, target Target, results chan<- Result) {
+	s.retryWithTechniques(
+		l,
+		req,
+		target,
+		overrideTechniques(req.Method),
+		func(status int) bool { return status < http.StatusBadRequest },
+		results,
+	)
+}
@@ -0,0 +1,69 @@
+package scan
+
+import (
+	"context"
+	"sync"
+)
+
+// pauseGate holds the requests while the scan is paused.
+type pauseGate struct {
+	mx sync.Mutex
+	// resumed is closed when the scan is resumed, nil while it is not paused
+	resumed chan struct{}
+}
+
+// wait blocks while the scan is paused or until the context is done.
+func (g *pauseGate) wait(ctx context.Context) error {
+	g.mx.Lock()
+	resumed := g.resumed
+	g.mx.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumed:
+		return nil
+	}
+}
+
+// Pause stops the scan from performing new requests until Resume is called, eg: to temporarily give the
+// bandwidth back; the requests in flight are completed. It returns false when the scan was already paused.
+func (s *Scanner) Pause() bool {
+	s.pause.mx.Lock()
+	defer s.pause.mx.Unlock()
+
+	if s.pause.resumed != nil {
+		return false
+	}
+
+	s.pause.resumed = make(chan struct{})
+
+	return true
+}
+
+// Resume lets a paused scan perform requests again, it returns false when the scan was not paused.
+func (s *Scanner) Resume() bool {
+	s.pause.mx.Lock()
+	defer s.pause.mx.Unlock()
+
+	if s.pause.resumed == nil {
+		return false
+	}
+
+	close(s.pause.resumed)
+	s.pause.resumed = nil
+
+	return true
+}
+
+// Paused tells whether the scan is paused, see Pause.
+func (s *Scanner) Paused() bool {
+	s.pause.mx.Lock()
+	defer s.pause.mx.Unlock()
+
+	return s.pause.resumed != nil
+}
@@ -0,0 +1,73 @@
+package scan
+
+import (
+	"path"
+	"strings"
+)
+
+// directoryKeywords scores the directory names containing them: the ones usually exposing administrative
+// features, APIs, credentials or backups are worth more than the ones serving static assets.
+var directoryKeywords = map[string]int{
+	"admin":     10,
+	"backup":    9,
+	"bak":       8,
+	"config":    8,
+	"secret":    8,
+	"private":   7,
+	"internal":  7,
+	"manage":    7,
+	"console":   7,
+	"dashboard": 6,
+	"api":       6,
+	"debug":     6,
+	"dev":       5,
+	"test":      5,
+	"stag":      5,
+	"old":       5,
+	"db":        5,
+	"upload":    5,
+	"user":      4,
+	"account":   4,
+	"login":     4,
+	"auth":      4,
+	"cgi":       3,
+	"include":   3,
+	"log":       3,
+	"tmp":       3,
+	"image":     -5,
+	"img":       -5,
+	"css":       -5,
+	"font":      -5,
+	"icon":      -5,
+	"static":    -4,
+	"asset":     -4,
+	"media":     -4,
+	"js":        -3,
+	"vendor":    -3,
+	"theme":     -3,
+}
+
+// WithPriorityScheduling recurses into the directories found by score, the directories named like admin/,
+// api/ or backup/ being scanned before the ones like images/ or css/, so that a scan stopped early still
+// covers the most interesting part of the target. The directories with the same score are recursed into in
+// the order they are found.
+func WithPriorityScheduling() ScannerOption {
+	return func(s *Scanner) {
+		s.queue = &branchQueue{prioritized: true}
+	}
+}
+
+// directoryScore sums the scores of the directoryKeywords found in the last segment of the path.
+func directoryScore(p string) int {
+	name := strings.ToLower(path.Base(strings.TrimSuffix(p, "/")))
+
+	score := 0
+
+	for keyword, value := range directoryKeywords {
+		if strings.Contains(name, keyword) {
+			score += value
+		}
+	}
+
+	return score
+}
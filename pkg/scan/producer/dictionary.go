@@ -2,26 +2,72 @@ package producer
 
 import (
 	"context"
+	"math/rand"
+	"strings"
 
 	"github.com/stefanoj3/dirstalk/pkg/scan"
 )
 
+// DictionaryProducerOption configures a DictionaryProducer.
+type DictionaryProducerOption func(*DictionaryProducer)
+
+// WithExtensions produces every entry of the dictionary also with each of the extensions appended,
+// eg: admin, admin.php and admin.bak for the extensions .php and .bak; the leading dot is optional.
+// The entries ending with a slash are produced as they are.
+func WithExtensions(extensions []string) DictionaryProducerOption {
+	return func(p *DictionaryProducer) {
+		for _, extension := range extensions {
+			extension = strings.TrimPrefix(strings.TrimSpace(extension), ".")
+			if extension == "" {
+				continue
+			}
+
+			p.extensions = append(p.extensions, "."+extension)
+		}
+	}
+}
+
+// WithShuffle produces the entries of the dictionary in a random order, which is the same for the same seed,
+// eg: to spread the requests across the application instead of following the dictionary alphabetically.
+// The variants of an entry, see WithExtensions, are still produced one after the other.
+func WithShuffle(seed int64) DictionaryProducerOption {
+	return func(p *DictionaryProducer) {
+		dictionary := make([]string, len(p.dictionary))
+		copy(dictionary, p.dictionary)
+
+		r := rand.New(rand.NewSource(seed)) // #nosec
+		r.Shuffle(len(dictionary), func(i, j int) {
+			dictionary[i], dictionary[j] = dictionary[j], dictionary[i]
+		})
+
+		p.dictionary = dictionary
+	}
+}
+
 func NewDictionaryProducer(
 	methods []string,
 	dictionary []string,
 	depth int,
+	opts ...DictionaryProducerOption,
 ) *DictionaryProducer {
-	return &DictionaryProducer{
+	p := &DictionaryProducer{
 		methods:    methods,
 		dictionary: dictionary,
 		depth:      depth,
 	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 type DictionaryProducer struct {
 	methods    []string
 	dictionary []string
 	depth      int
+	extensions []string
 }
 
 func (p *DictionaryProducer) Produce(ctx context.Context) <-chan scan.Target {
@@ -31,15 +77,17 @@ func (p *DictionaryProducer) Produce(ctx context.Context) <-chan scan.Target {
 		defer close(targets)
 
 		for _, entry := range p.dictionary {
-			for _, method := range p.methods {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					targets <- scan.Target{
-						Path:   entry,
-						Method: method,
-						Depth:  p.depth,
+			for _, path := range p.expand(entry) {
+				for _, method := range p.methods {
+					select {
+					case <-ctx.Done():
+						return
+					default:
+						targets <- scan.Target{
+							Path:   path,
+							Method: method,
+							Depth:  p.depth,
+						}
 					}
 				}
 			}
@@ -48,3 +96,28 @@ func (p *DictionaryProducer) Produce(ctx context.Context) <-chan scan.Target {
 
 	return targets
 }
+
+// Len returns the amount of targets produced, that is requested in every directory scanned.
+func (p *DictionaryProducer) Len() int {
+	targets := 0
+	for _, entry := range p.dictionary {
+		targets += len(p.expand(entry)) * len(p.methods)
+	}
+
+	return targets
+}
+
+func (p *DictionaryProducer) expand(entry string) []string {
+	if len(p.extensions) == 0 || strings.HasSuffix(entry, "/") {
+		return []string{entry}
+	}
+
+	paths := make([]string, 0, len(p.extensions)+1)
+	paths = append(paths, entry)
+
+	for _, extension := range p.extensions {
+		paths = append(paths, entry+extension)
+	}
+
+	return paths
+}
@@ -2,6 +2,7 @@ package producer_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -82,3 +83,52 @@ func TestDictionaryProducerCanBeCanceled(t *testing.T) {
 	// 11 is the size of the producer buffer
 	assert.True(t, resultsCount <= 11)
 }
+
+func TestDictionaryProducerWithExtensionsShouldProduceTheExpandedEntries(t *testing.T) {
+	t.Parallel()
+
+	sut := producer.NewDictionaryProducer(
+		[]string{http.MethodGet},
+		[]string{"/home", "/assets/"},
+		0,
+		producer.WithExtensions([]string{".php", "bak", " ", ".zip"}),
+	)
+
+	results := make([]string, 0)
+	for r := range sut.Produce(context.Background()) {
+		results = append(results, r.Path)
+	}
+
+	assert.Equal(t, []string{"/home", "/home.php", "/home.bak", "/home.zip", "/assets/"}, results)
+}
+
+func TestDictionaryProducerWithShuffleShouldProduceTheSameOrderForTheSameSeed(t *testing.T) {
+	t.Parallel()
+
+	dictionary := make([]string, 0, 50)
+	for i := 0; i < 50; i++ {
+		dictionary = append(dictionary, fmt.Sprintf("/%d", i))
+	}
+
+	produce := func(seed int64) []string {
+		sut := producer.NewDictionaryProducer([]string{http.MethodGet}, dictionary, 0, producer.WithShuffle(seed))
+
+		results := make([]string, 0, len(dictionary))
+		for r := range sut.Produce(context.Background()) {
+			results = append(results, r.Path)
+		}
+
+		return results
+	}
+
+	shuffled := produce(42)
+
+	assert.Equal(t, shuffled, produce(42))
+	assert.NotEqual(t, shuffled, produce(43))
+	assert.NotEqual(t, dictionary, shuffled)
+	assert.ElementsMatch(t, dictionary, shuffled)
+
+	// the dictionary given is left untouched
+	assert.Equal(t, "/0", dictionary[0])
+	assert.Equal(t, "/49", dictionary[49])
+}
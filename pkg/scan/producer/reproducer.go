@@ -2,22 +2,52 @@ package producer
 
 import (
 	"context"
-	"sync"
+	"strings"
 
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
 	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
 )
 
 const defaultChannelBuffer = 25
 
+// ReProducerOption configures a ReProducer.
+type ReProducerOption func(*ReProducer)
+
+// WithBloomFilter remembers the directories already recursed into with a bloom filter sized for the given
+// amount of directories, instead of keeping all of them in memory, eg: for the recursive scans of dictionaries
+// with millions of entries. The memory used does not grow with the directories found, at the cost of skipping
+// a directory with the given probability, eg: 0.001, as it is wrongly told to be already recursed into; the
+// probability grows once the directories found exceed the capacity.
+func WithBloomFilter(capacity uint, errorRate float64) ReProducerOption {
+	return func(r *ReProducer) {
+		r.newVisitedSet = func() visitedSet {
+			return bloomVisitedSet{filter: bloom.New(capacity, errorRate)}
+		}
+	}
+}
+
 func NewReProducer(
 	producer scan.Producer,
+	opts ...ReProducerOption,
 ) *ReProducer {
-	return &ReProducer{producer: producer}
+	r := &ReProducer{
+		producer: producer,
+		newVisitedSet: func() visitedSet {
+			return &exactVisitedSet{}
+		},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 type ReProducer struct {
-	producer scan.Producer
+	producer      scan.Producer
+	newVisitedSet func() visitedSet
 }
 
 // Reproduce will check if it is possible to go deeper on the result provided, if so will.
@@ -26,7 +56,7 @@ func (r *ReProducer) Reproduce(ctx context.Context) func(r scan.Result) <-chan s
 }
 
 func (r *ReProducer) buildReproducer(ctx context.Context) func(result scan.Result) <-chan scan.Target {
-	resultRegistry := sync.Map{}
+	visited := r.newVisitedSet()
 
 	return func(result scan.Result) <-chan scan.Target {
 		resultChannel := make(chan scan.Target, defaultChannelBuffer)
@@ -43,13 +73,11 @@ func (r *ReProducer) buildReproducer(ctx context.Context) func(result scan.Resul
 				return
 			}
 
-			_, inRegistry := resultRegistry.Load(result.Target.Path)
-			if inRegistry {
+			// a directory is reproduced once, whether it was found with a trailing slash or not
+			if visited.visit(strings.TrimSuffix(result.Target.Path, "/")) {
 				return
 			}
 
-			resultRegistry.Store(result.Target.Path, nil)
-
 			for target := range r.producer.Produce(ctx) {
 				newTarget := result.Target
 				newTarget.Depth--
@@ -2,6 +2,7 @@ package producer_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sort"
 	"testing"
@@ -122,6 +123,38 @@ func TestReProducerShouldProduceNothingForDepthZero(t *testing.T) {
 	assert.Len(t, targets, 0)
 }
 
+func TestReProducerWithBloomFilterShouldReproduceEveryDirectoryOnce(t *testing.T) {
+	t.Parallel()
+
+	dictionaryProducer := producer.NewDictionaryProducer([]string{http.MethodGet}, []string{"/home"}, 1)
+
+	sut := producer.NewReProducer(dictionaryProducer, producer.WithBloomFilter(1000, 0.001))
+
+	reproducerFunc := sut.Reproduce(context.Background())
+
+	reproduced := 0
+
+	for i := 0; i < 1000; i++ {
+		for _, path := range []string{fmt.Sprintf("/dir%d", i), fmt.Sprintf("/dir%d/", i)} {
+			result := scan.NewResult(
+				scan.Target{Path: path, Method: http.MethodGet, Depth: 1},
+				&http.Response{
+					StatusCode: http.StatusOK,
+					Request:    &http.Request{URL: test.MustParseURL(t, "http://mysite"+path)},
+				},
+			)
+
+			for range reproducerFunc(result) {
+				reproduced++
+			}
+		}
+	}
+
+	// a few directories can be wrongly told to be already reproduced, none is reproduced twice
+	assert.LessOrEqual(t, reproduced, 1000)
+	assert.GreaterOrEqual(t, reproduced, 990)
+}
+
 func BenchmarkReProducer(b *testing.B) {
 	methods := []string{http.MethodGet, http.MethodPost}
 	dictionary := []string{"/home", "/about"}
@@ -0,0 +1,33 @@
+package producer
+
+import (
+	"sync"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/bloom"
+)
+
+// visitedSet remembers the directories already reproduced.
+type visitedSet interface {
+	// visit marks the path as visited, telling whether it already was.
+	visit(path string) bool
+}
+
+// exactVisitedSet keeps every path visited in memory.
+type exactVisitedSet struct {
+	paths sync.Map
+}
+
+func (s *exactVisitedSet) visit(path string) bool {
+	_, visited := s.paths.LoadOrStore(path, nil)
+
+	return visited
+}
+
+// bloomVisitedSet remembers the paths visited in a fixed amount of memory, see WithBloomFilter.
+type bloomVisitedSet struct {
+	filter *bloom.Filter
+}
+
+func (s bloomVisitedSet) visit(path string) bool {
+	return s.filter.Add(path)
+}
@@ -0,0 +1,217 @@
+package scan
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Progress tells how far a scan went, to resume it later, see WithProgressTracking.
+type Progress struct {
+	// Produced is the amount of targets of the producer scanned, along with all the ones before them.
+	Produced uint64
+	// Branches are the directories found whose recursion is not complete.
+	Branches []BranchProgress `json:",omitempty"`
+}
+
+// BranchProgress is a directory found whose recursion is not complete.
+type BranchProgress struct {
+	Result Result
+	// Reproduced is the amount of targets of the directory already scanned, the first ones it reproduces.
+	Reproduced uint64
+}
+
+// WithProgressTracking keeps track of the progress of the scan, see Scanner.Progress, resuming it from the
+// given one, eg: saved when a previous scan was interrupted; the zero value starts the scan from scratch.
+// The targets of the producer are skipped up to the ones already scanned, then the directories whose
+// recursion was not complete are recursed into, skipping the targets they already reproduced.
+func WithProgressTracking(resumed Progress) ScannerOption {
+	return func(s *Scanner) {
+		s.progress = &progressTracker{
+			resumed:   resumed,
+			produced:  resumed.Produced,
+			completed: make(map[uint64]struct{}),
+			branches:  make(map[*branch]struct{}),
+		}
+	}
+}
+
+// branch is a directory to recurse into.
+type branch struct {
+	result Result
+	// reproduced is the amount of targets of the branch already scanned, accessed atomically
+	reproduced uint64
+}
+
+type progressTracker struct {
+	resumed Progress
+	// sequence is the position of the last target produced, only accessed while numbering the targets
+	sequence uint64
+
+	mx sync.Mutex
+	// produced is the position of the last target scanned along with all the ones before it
+	produced uint64
+	// completed holds the positions of the targets scanned after produced
+	completed map[uint64]struct{}
+	branches  map[*branch]struct{}
+}
+
+// number assigns to the targets of the producer their position, skipping the ones already scanned.
+func (p *progressTracker) number(targets <-chan Target) <-chan Target {
+	numbered := make(chan Target)
+
+	go func() {
+		defer close(numbered)
+
+		for target := range targets {
+			p.sequence++
+
+			if p.sequence <= p.resumed.Produced {
+				continue
+			}
+
+			target.sequence = p.sequence
+			numbered <- target
+		}
+	}()
+
+	return numbered
+}
+
+// complete records that the target at the given position was scanned, 0 being the targets not produced
+// by the producer.
+func (p *progressTracker) complete(sequence uint64) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.completeLocked(sequence)
+}
+
+func (p *progressTracker) completeLocked(sequence uint64) {
+	if sequence <= p.produced {
+		return
+	}
+
+	p.completed[sequence] = struct{}{}
+
+	for {
+		if _, found := p.completed[p.produced+1]; !found {
+			return
+		}
+
+		delete(p.completed, p.produced+1)
+		p.produced++
+	}
+}
+
+// track records the branch as not complete, completing the target at the given position which found it:
+// what is left of the target is the recursion into the branch.
+func (p *progressTracker) track(b *branch, sequence uint64) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.branches[b] = struct{}{}
+
+	if sequence > 0 {
+		p.completeLocked(sequence)
+	}
+}
+
+func (p *progressTracker) untrack(b *branch) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	delete(p.branches, b)
+}
+
+func (p *progressTracker) snapshot() Progress {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	progress := Progress{Produced: p.produced}
+
+	for b := range p.branches {
+		progress.Branches = append(progress.Branches, BranchProgress{
+			Result:     b.result,
+			Reproduced: atomic.LoadUint64(&b.reproduced),
+		})
+	}
+
+	sort.Slice(progress.Branches, func(i, j int) bool {
+		return progress.Branches[i].Result.URL.String() < progress.Branches[j].Result.URL.String()
+	})
+
+	return progress
+}
+
+// Progress returns how far the scan went, to resume it later; it is empty unless WithProgressTracking is used.
+func (s *Scanner) Progress() Progress {
+	if s.progress == nil {
+		return Progress{}
+	}
+
+	return s.progress.snapshot()
+}
+
+// interrupted tells whether the scan was stopped, in which case the targets being scanned are not complete.
+func (s *Scanner) interrupted(ctx context.Context) bool {
+	return ctx.Err() != nil || s.Err() != nil || s.Stopped()
+}
+
+// completeTarget records that the target was scanned, unless the scan was interrupted meanwhile.
+func (s *Scanner) completeTarget(ctx context.Context, target Target) {
+	if s.progress != nil && target.sequence > 0 && !s.interrupted(ctx) {
+		s.progress.complete(target.sequence)
+	}
+}
+
+func (s *Scanner) trackBranch(b *branch, target Target) {
+	if s.progress != nil {
+		s.progress.track(b, target.sequence)
+	}
+}
+
+// untrackBranches records that the recursion into the branches is complete, unless the scan was interrupted.
+func (s *Scanner) untrackBranches(ctx context.Context, branches ...*branch) {
+	if s.progress == nil || s.interrupted(ctx) {
+		return
+	}
+
+	for _, b := range branches {
+		s.progress.untrack(b)
+	}
+}
+
+// resumeBranches recurses into the branches whose recursion was not complete when the progress was saved.
+func (s *Scanner) resumeBranches(
+	ctx context.Context,
+	baseURL url.URL,
+	wg *sync.WaitGroup,
+	reproducer func(r Result) <-chan Target,
+	results chan<- Result,
+) {
+	for _, resumed := range s.progress.resumed.Branches {
+		b := &branch{result: resumed.Result, reproduced: resumed.Reproduced}
+
+		s.trackBranch(b, Target{})
+
+		if s.queue != nil {
+			s.queue.push(b)
+
+			continue
+		}
+
+		s.workers.acquire()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer s.workers.release()
+
+			s.recurse(ctx, baseURL, b, reproducer, results)
+		}()
+	}
+}
@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Range is an inclusive range of values, eg: 100-200, a single value being a range having the same bounds.
+type Range struct {
+	Min int64
+	Max int64
+}
+
+// ParseRanges parses a comma separated list of values and ranges, eg: 0,4242,100-200.
+func ParseRanges(raw string) ([]Range, error) {
+	ranges := make([]Range, 0)
+
+	for _, rawRange := range strings.Split(raw, ",") {
+		rawRange = strings.TrimSpace(rawRange)
+		if rawRange == "" {
+			continue
+		}
+
+		rawMin, rawMax, isRange := strings.Cut(rawRange, "-")
+		if !isRange {
+			rawMax = rawMin
+		}
+
+		min, minErr := strconv.ParseInt(strings.TrimSpace(rawMin), 10, 64)
+		max, maxErr := strconv.ParseInt(strings.TrimSpace(rawMax), 10, 64)
+
+		if minErr != nil || maxErr != nil || min < 0 || min > max {
+			return nil, errors.Errorf("invalid range `%s`, it must be a value or a range like 100-200", rawRange)
+		}
+
+		ranges = append(ranges, Range{Min: min, Max: max})
+	}
+
+	return ranges, nil
+}
+
+// Contains tells whether the value is within the range.
+func (r Range) Contains(value int64) bool {
+	return value >= r.Min && value <= r.Max
+}
+
+func (r Range) String() string {
+	if r.Min == r.Max {
+		return strconv.FormatInt(r.Min, 10)
+	}
+
+	return strconv.FormatInt(r.Min, 10) + "-" + strconv.FormatInt(r.Max, 10)
+}
+
+// InRanges tells whether the value is within any of the ranges.
+func InRanges(ranges []Range, value int64) bool {
+	for _, r := range ranges {
+		if r.Contains(value) {
+			return true
+		}
+	}
+
+	return false
+}
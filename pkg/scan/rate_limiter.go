@@ -0,0 +1,110 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// WithRateLimit limits the requests performed by all the workers together to the given amount per second.
+func WithRateLimit(requestsPerSecond float64) ScannerOption {
+	return func(s *Scanner) {
+		s.rateLimiter = newRateLimiter(requestsPerSecond)
+	}
+}
+
+// rateLimiter is a token bucket holding a single token, so that the requests are evenly spaced
+// instead of being sent in bursts.
+type rateLimiter struct {
+	mx       sync.Mutex
+	interval time.Duration
+	// next is when the next token is available
+	next time.Time
+	// baseline is the configured interval, the adaptive rate never goes below it
+	baseline  time.Duration
+	successes int
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	interval := time.Duration(float64(time.Second) / requestsPerSecond)
+
+	return &rateLimiter{interval: interval, baseline: interval}
+}
+
+// wait blocks until a token is available or the context is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	r.mx.Lock()
+
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+
+	slot := r.next
+	r.next = r.next.Add(r.interval)
+
+	r.mx.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do performs the request once the circuit breaker, the delay and the rate limit allow it, the requests
+// throttled by the target are performed again when the adaptive rate is enabled.
+func (s *Scanner) do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := s.pause.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		if s.circuitBreaker != nil {
+			if err := s.circuitBreaker.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.sleepBeforeRequest(req.Context()); err != nil {
+			return nil, err
+		}
+
+		if s.rateLimiter != nil {
+			if err := s.rateLimiter.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.consumeRequestBudget(); err != nil {
+			return nil, err
+		}
+
+		res, err := s.doWithinConcurrencyLimit(req)
+		if err != nil || !s.adaptiveRate || !s.adaptRate(res) || attempt == adaptiveMaxAttempts {
+			return res, err
+		}
+
+		atomic.AddUint64(&s.requests, 1)
+
+		if err := res.Body.Close(); err != nil {
+			s.logger.WithError(err).Warn("failed to close response body")
+		}
+
+		// the request was already made, the request cache would otherwise consider it redundant
+		req = client.SkipRequestCache(cloneRequest(req.Context(), req))
+	}
+}
@@ -0,0 +1,150 @@
+package scan
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// regexPathPatternPrefix marks the path patterns written as regexes instead of globs.
+const regexPathPatternPrefix = "regex:"
+
+// PathPattern matches the paths of the targets, see ParsePathPattern.
+type PathPattern struct {
+	raw   string
+	regex *regexp.Regexp
+}
+
+// ParsePathPattern parses a glob matched against the whole path, eg: /static or */assets, where * matches
+// any sequence of characters, slashes included, and ? any single character; the patterns prefixed with
+// "regex:" are regexes instead, eg: regex:^/(img|css)/. The paths are matched with a leading slash and
+// without the trailing one.
+func ParsePathPattern(raw string) (PathPattern, error) {
+	if rawRegex := strings.TrimPrefix(raw, regexPathPatternPrefix); rawRegex != raw {
+		regex, err := regexp.Compile(rawRegex)
+		if err != nil {
+			return PathPattern{}, errors.Wrapf(err, "invalid path pattern `%s`", raw)
+		}
+
+		return PathPattern{raw: raw, regex: regex}, nil
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return PathPattern{}, errors.New("the path pattern is empty")
+	}
+
+	glob := strings.TrimSuffix(raw, "/")
+	if !strings.HasPrefix(glob, "/") && !strings.HasPrefix(glob, "*") {
+		glob = "/" + glob
+	}
+
+	var b strings.Builder
+
+	b.WriteString("^")
+
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return PathPattern{raw: raw, regex: regexp.MustCompile(b.String())}, nil
+}
+
+// Match tells whether the path matches the pattern.
+func (p PathPattern) Match(targetPath string) bool {
+	return p.regex.MatchString(normalizePatternPath(targetPath))
+}
+
+func (p PathPattern) String() string {
+	return p.raw
+}
+
+// MarshalText stores the pattern as it was written, eg: along with the configuration of the scan.
+func (p PathPattern) MarshalText() ([]byte, error) {
+	return []byte(p.raw), nil
+}
+
+func normalizePatternPath(targetPath string) string {
+	return "/" + strings.Trim(targetPath, "/")
+}
+
+// DepthOverride replaces the depth of the scan below the paths matching its pattern.
+type DepthOverride struct {
+	Pattern PathPattern
+	Depth   int
+}
+
+// ParseDepthOverride parses a depth override in the format pattern=depth, eg: /api/*=5, see ParsePathPattern.
+func ParseDepthOverride(raw string) (DepthOverride, error) {
+	i := strings.LastIndex(raw, "=")
+	if i < 0 {
+		return DepthOverride{}, errors.Errorf("the depth override `%s` must be in the format pattern=depth", raw)
+	}
+
+	depth, err := strconv.Atoi(strings.TrimSpace(raw[i+1:]))
+	if err != nil || depth < 0 {
+		return DepthOverride{}, errors.Errorf("the depth of `%s` must be a non-negative number", raw)
+	}
+
+	pattern, err := ParsePathPattern(raw[:i])
+	if err != nil {
+		return DepthOverride{}, err
+	}
+
+	return DepthOverride{Pattern: pattern, Depth: depth}, nil
+}
+
+func (o DepthOverride) String() string {
+	return o.Pattern.String() + "=" + strconv.Itoa(o.Depth)
+}
+
+// WithRecursionExclusions never recurses into the paths matching any of the patterns, eg: /static or */assets.
+func WithRecursionExclusions(patterns []PathPattern) ScannerOption {
+	return func(s *Scanner) {
+		s.recursionExclusions = patterns
+	}
+}
+
+// WithDepthOverrides replaces the depth of the scan below the paths matching the overrides, eg: to scan deeper
+// an API and less a CDN-like directory. The first override matching a path is applied, and only when the parent
+// of the path does not match it as well, so that the depth keeps decreasing within the overridden branch.
+func WithDepthOverrides(overrides []DepthOverride) ScannerOption {
+	return func(s *Scanner) {
+		s.depthOverrides = overrides
+	}
+}
+
+// recursionTarget returns the target to recurse into, with the depth overridden when needed, false when the
+// target must not be recursed into.
+func (s *Scanner) recursionTarget(target Target) (Target, bool) {
+	for _, pattern := range s.recursionExclusions {
+		if pattern.Match(target.Path) {
+			return target, false
+		}
+	}
+
+	for _, override := range s.depthOverrides {
+		if !override.Pattern.Match(target.Path) {
+			continue
+		}
+
+		if !override.Pattern.Match(path.Dir(normalizePatternPath(target.Path))) {
+			target.Depth = override.Depth
+		}
+
+		break
+	}
+
+	return target, true
+}
@@ -0,0 +1,148 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// Redirect is a hop of the redirect chain of a result.
+type Redirect struct {
+	URL        string
+	StatusCode int `json:",omitempty"`
+	// OffTarget tells the hop points outside of the target, so it was not requested
+	OffTarget bool `json:",omitempty"`
+}
+
+// WithRedirectFollowing follows up to maxRedirects redirects of every result, recording the chain, the final
+// URL and its status in the result. The redirects pointing to another host or out of the scope, see WithScope,
+// are recorded but not followed.
+func WithRedirectFollowing(maxRedirects int) ScannerOption {
+	return func(s *Scanner) {
+		s.maxRedirects = maxRedirects
+	}
+}
+
+// followRedirects follows the redirect chain starting from the given response, recording it in the result.
+func (s *Scanner) followRedirects(
+	ctx context.Context,
+	l *logrus.Entry,
+	req *http.Request,
+	res *http.Response,
+	result *Result,
+) {
+	targetHost := req.URL.Host
+
+	for hops := 0; isRedirect(res); hops++ {
+		if hops == s.maxRedirects {
+			l.WithField("max-redirects", s.maxRedirects).Debug("stopped following the redirects")
+
+			break
+		}
+
+		location, err := res.Location()
+		if err != nil {
+			l.WithError(err).Debug("failed to parse location for redirect")
+
+			break
+		}
+
+		if location.Host != targetHost || !s.inScope(location) {
+			result.Redirects = append(result.Redirects, Redirect{URL: location.String(), OffTarget: true})
+
+			break
+		}
+
+		res, err = s.doRedirect(redirectRequest(ctx, req, res.StatusCode, location))
+		if err != nil && strings.Contains(err.Error(), client.ErrRequestOutOfScope.Error()) {
+			result.Redirects = append(result.Redirects, Redirect{URL: location.String(), OffTarget: true})
+
+			break
+		}
+
+		if err != nil {
+			l.WithError(err).WithField("location", location.String()).Debug("failed to follow redirect")
+
+			break
+		}
+
+		req = res.Request
+
+		result.Redirects = append(result.Redirects, Redirect{URL: location.String(), StatusCode: res.StatusCode})
+		result.FinalURL, result.FinalStatusCode = location.String(), res.StatusCode
+	}
+
+	if len(result.Redirects) > 0 {
+		l.WithFields(logrus.Fields{
+			"redirects":    stringifyRedirects(result.Redirects),
+			"final-url":    result.FinalURL,
+			"final-status": result.FinalStatusCode,
+		}).Info("Followed redirects")
+	}
+}
+
+func (s *Scanner) doRedirect(req *http.Request) (*http.Response, error) {
+	// the location can be a path scanned already, the request cache would consider it redundant
+	res, err := s.do(client.SkipRequestCache(req))
+	if err != nil {
+		return nil, err
+	}
+
+	atomic.AddUint64(&s.requests, 1)
+
+	return res, res.Body.Close()
+}
+
+// redirectRequest builds the request following the redirect, the way the http.Client does it: the 301, 302
+// and 303 redirects of the requests other than GET and HEAD are followed with a GET without body.
+func redirectRequest(ctx context.Context, req *http.Request, statusCode int, location *url.URL) *http.Request {
+	redirectReq := cloneRequest(ctx, req)
+	redirectReq.URL = location
+
+	if statusCode == http.StatusTemporaryRedirect || statusCode == http.StatusPermanentRedirect {
+		return redirectReq
+	}
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		redirectReq.Method = http.MethodGet
+		redirectReq.Body, redirectReq.GetBody, redirectReq.ContentLength = nil, nil, 0
+		redirectReq.Header.Del("Content-Type")
+	}
+
+	return redirectReq
+}
+
+func isRedirect(res *http.Response) bool {
+	switch res.StatusCode {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return res.Header.Get("Location") != ""
+	default:
+		return false
+	}
+}
+
+func stringifyRedirects(redirects []Redirect) string {
+	hops := make([]string, 0, len(redirects))
+
+	for _, r := range redirects {
+		if r.OffTarget {
+			hops = append(hops, r.URL+" [off-target]")
+
+			continue
+		}
+
+		hops = append(hops, r.URL+" ["+strconv.Itoa(r.StatusCode)+"]")
+	}
+
+	return strings.Join(hops, " -> ")
+}
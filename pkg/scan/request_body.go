@@ -0,0 +1,87 @@
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// WithBodyTemplate sends the given body with every request, the paths of the dictionary are placed where the
+// FuzzKeyword is, eg: {"username": "FUZZ"}. They are escaped according to the content type when it is JSON or
+// a form, which is sent as the Content-Type header unless empty.
+func WithBodyTemplate(template, contentType string) ScannerOption {
+	return func(s *Scanner) {
+		s.bodyTemplate = template
+		s.contentType = contentType
+	}
+}
+
+// newRequest builds the request of the target, along with its body when there is a body template and its
+// Host header when there is one.
+func (s *Scanner) newRequest(ctx context.Context, u url.URL, target Target) (*http.Request, error) {
+	word := strings.TrimPrefix(target.Path, "/")
+
+	if s.bodyTemplate == "" {
+		req, err := http.NewRequestWithContext(ctx, target.Method, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.setHost(req, word); err != nil {
+			return nil, err
+		}
+
+		return req, nil
+	}
+
+	body := strings.ReplaceAll(s.bodyTemplate, FuzzKeyword, s.escapeBodyValue(word))
+
+	req, err := http.NewRequestWithContext(ctx, target.Method, u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setHost(req, word); err != nil {
+		return nil, err
+	}
+
+	if s.contentType != "" {
+		req.Header.Set("Content-Type", s.contentType)
+	}
+
+	// the requests differ only by their body, the request cache would consider them redundant
+	if strings.Contains(s.bodyTemplate, FuzzKeyword) {
+		req = client.SkipRequestCache(req)
+	}
+
+	return req, nil
+}
+
+func (s *Scanner) escapeBodyValue(value string) string {
+	switch {
+	case strings.Contains(s.contentType, "json"):
+		escaped, _ := json.Marshal(value) //nolint:errchkjson
+
+		return string(escaped[1 : len(escaped)-1])
+	case strings.Contains(s.contentType, "x-www-form-urlencoded"):
+		return url.QueryEscape(value)
+	default:
+		return value
+	}
+}
+
+// cloneRequest clones the request to perform it again, with its body from the start.
+func cloneRequest(ctx context.Context, req *http.Request) *http.Request {
+	clone := req.Clone(ctx)
+
+	// the bodies are built out of strings, so they can always be read again
+	if req.GetBody != nil {
+		clone.Body, _ = req.GetBody()
+	}
+
+	return clone
+}
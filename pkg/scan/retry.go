@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// WithRetries performs again the requests failing because of a network error or a 5xx response, up to
+// the given amount of times. The attempts are spaced by an exponential backoff starting from the given one.
+func WithRetries(retries int, backoff time.Duration) ScannerOption {
+	return func(s *Scanner) {
+		s.retries = retries
+		s.retryBackoff = backoff
+	}
+}
+
+// doWithRetries performs the request, retrying it when it fails transiently, and returns how many times
+// it was retried. The last response or error is returned when all the attempts fail.
+func (s *Scanner) doWithRetries(
+	ctx context.Context,
+	l *logrus.Entry,
+	req *http.Request,
+) (res *http.Response, retries int, err error) {
+	res, err = s.do(req)
+
+	for ; retries < s.retries && shouldRetry(res, err); retries++ {
+		atomic.AddUint64(&s.requests, 1)
+
+		if res != nil {
+			if closeErr := res.Body.Close(); closeErr != nil {
+				l.WithError(closeErr).Warn("failed to close response body")
+			}
+		}
+
+		backoff := s.retryBackoff << retries
+
+		l.WithError(err).WithField("backoff", backoff).Debug("retrying request")
+
+		select {
+		case <-ctx.Done():
+			return nil, retries, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		// the request was already made, the request cache would otherwise consider it redundant
+		res, err = s.do(client.SkipRequestCache(cloneRequest(ctx, req)))
+	}
+
+	return res, retries, err
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, ErrRequestBudgetExhausted) &&
+			!strings.Contains(err.Error(), client.ErrRequestRedundant.Error()) &&
+			!strings.Contains(err.Error(), client.ErrRequestOutOfScope.Error())
+	}
+
+	return res.StatusCode >= http.StatusInternalServerError
+}
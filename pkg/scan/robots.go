@@ -0,0 +1,18 @@
+package scan
+
+import (
+	"net/url"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/robots"
+)
+
+// WithRobotsRespected skips the targets whose path the given robots.txt disallows.
+func WithRobotsRespected(rules *robots.Robots) ScannerOption {
+	return func(s *Scanner) {
+		s.robotsRules = rules
+	}
+}
+
+func (s *Scanner) allowedByRobots(u *url.URL) bool {
+	return s.robotsRules == nil || s.robotsRules.Allowed(u.EscapedPath())
+}
@@ -0,0 +1,192 @@
+package robots
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ModeSeed adds the paths of robots.txt to the dictionary.
+	ModeSeed = "seed"
+	// ModeRespect skips the paths robots.txt disallows.
+	ModeRespect = "respect"
+	// ModeIgnore does not fetch robots.txt.
+	ModeIgnore = "ignore"
+)
+
+// Modes returns the available ways of handling robots.txt.
+func Modes() []string {
+	return []string{ModeSeed, ModeRespect, ModeIgnore}
+}
+
+// Doer performs the http requests.
+type Doer interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Entry is an Allow or Disallow rule of robots.txt.
+type Entry struct {
+	Directive string
+	Path      string
+}
+
+// Robots holds the rules of robots.txt applying to every user agent.
+type Robots struct {
+	Entries []Entry
+	// Sitemaps are the sitemaps listed, whatever the user agent.
+	Sitemaps []string `json:",omitempty"`
+
+	rules []rule
+}
+
+type rule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// Fetch retrieves and parses the robots.txt of the host of the given URL, a missing robots.txt allows everything.
+func Fetch(ctx context.Context, doer Doer, u *url.URL) (*Robots, error) {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "robots: failed to build the request")
+	}
+
+	res, err := doer.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "robots: failed to get `%s`", robotsURL.String())
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode/100 != 2 {
+		return &Robots{}, nil
+	}
+
+	return Parse(res.Body)
+}
+
+// Parse reads the rules of the group of the "*" user agent.
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+
+	// consecutive user-agent lines share the rules that follow them
+	inGroup, groupStarted := false, false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		field, value := strings.ToLower(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1])
+
+		switch field {
+		case "user-agent":
+			if groupStarted {
+				inGroup, groupStarted = false, false
+			}
+
+			inGroup = inGroup || value == "*"
+		case "allow", "disallow":
+			groupStarted = true
+
+			if !inGroup || value == "" {
+				continue
+			}
+
+			robots.add(field == "allow", value)
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "robots: failed to read")
+	}
+
+	return robots, nil
+}
+
+func (r *Robots) add(allow bool, path string) {
+	directive := "Disallow"
+	if allow {
+		directive = "Allow"
+	}
+
+	r.Entries = append(r.Entries, Entry{Directive: directive, Path: path})
+
+	pattern := regexp.QuoteMeta(path)
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+
+	if strings.HasSuffix(pattern, `\$`) {
+		pattern = strings.TrimSuffix(pattern, `\$`) + "$"
+	}
+
+	r.rules = append(r.rules, rule{allow: allow, length: len(path), pattern: regexp.MustCompile("^" + pattern)})
+}
+
+// Allowed tells whether the given path can be requested: the longest rule matching it wins, Allow winning
+// the ties, as in RFC 9309.
+func (r *Robots) Allowed(path string) bool {
+	allowed, length := true, -1
+
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allowed, length = rule.allow, rule.length
+		}
+	}
+
+	return allowed
+}
+
+// Paths returns the paths of the entries found under the given base path, relative to it; the wildcards
+// are cut off, as they cannot be requested.
+func (r *Robots) Paths(basePath string) []string {
+	if !strings.HasSuffix(basePath, "/") {
+		basePath += "/"
+	}
+
+	paths := make([]string, 0, len(r.Entries))
+	seen := make(map[string]struct{}, len(r.Entries))
+
+	for _, e := range r.Entries {
+		p := strings.TrimSuffix(e.Path, "$")
+		if i := strings.Index(p, "*"); i >= 0 {
+			p = p[:i]
+		}
+
+		if !strings.HasPrefix(p, basePath) {
+			continue
+		}
+
+		p = strings.TrimPrefix(p, basePath)
+		if _, found := seen[p]; found || p == "" {
+			continue
+		}
+
+		seen[p] = struct{}{}
+		paths = append(paths, p)
+	}
+
+	return paths
+}
@@ -0,0 +1,72 @@
+package robots_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan/robots"
+	"github.com/stretchr/testify/assert"
+)
+
+const robotsTxt = `# comment
+User-agent: Googlebot
+Disallow: /google-only
+
+User-agent: Bingbot
+User-agent: *
+Disallow: /admin/ # the admin area
+Allow: /admin/public
+Disallow: /*.bak$
+Disallow:
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	sut, err := robots.Parse(strings.NewReader(robotsTxt))
+	assert.NoError(t, err)
+
+	expectedEntries := []robots.Entry{
+		{Directive: "Disallow", Path: "/admin/"},
+		{Directive: "Allow", Path: "/admin/public"},
+		{Directive: "Disallow", Path: "/*.bak$"},
+	}
+	assert.Equal(t, expectedEntries, sut.Entries)
+	assert.Equal(t, []string{"https://example.com/sitemap.xml"}, sut.Sitemaps)
+}
+
+func TestAllowed(t *testing.T) {
+	t.Parallel()
+
+	sut, err := robots.Parse(strings.NewReader(robotsTxt))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/", expected: true},
+		{path: "/google-only", expected: true},
+		{path: "/admin/", expected: false},
+		{path: "/admin/users", expected: false},
+		{path: "/admin/public/index.html", expected: true},
+		{path: "/backup/db.bak", expected: false},
+		{path: "/backup/db.bak.txt", expected: true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, sut.Allowed(tc.path), tc.path)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	t.Parallel()
+
+	sut, err := robots.Parse(strings.NewReader(robotsTxt))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"admin/", "admin/public"}, sut.Paths("/"))
+	assert.Equal(t, []string{"public"}, sut.Paths("/admin"))
+}
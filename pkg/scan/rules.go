@@ -0,0 +1,88 @@
+package scan
+
+// Action tells how to handle a result.
+type Action string
+
+const (
+	// ActionReport emits the result.
+	ActionReport Action = "report"
+	// ActionIgnore drops the result.
+	ActionIgnore Action = "ignore"
+	// ActionRecurse scans the paths below the result.
+	ActionRecurse Action = "recurse"
+	// ActionRetryWithBypass retries the request with techniques known to bypass access controls
+	// and emits the attempts that succeeded.
+	ActionRetryWithBypass Action = "retry-with-bypass"
+	// ActionEscalateSeverity flags the result with high severity.
+	ActionEscalateSeverity Action = "escalate-severity"
+)
+
+// Actions returns the available actions.
+func Actions() []Action {
+	return []Action{ActionReport, ActionIgnore, ActionRecurse, ActionRetryWithBypass, ActionEscalateSeverity}
+}
+
+// Rules decides the actions to apply to a result, found is false when no rule applies to it.
+type Rules interface {
+	Match(Result) (actions []Action, found bool)
+}
+
+// WithRules applies the actions decided by the rules to the results, the results not matching
+// any rule are handled by the ResultFilter.
+func WithRules(rules Rules) ScannerOption {
+	return func(s *Scanner) {
+		s.rules = rules
+	}
+}
+
+// WithRecursionStatuses recurses only into the results whose status code is within the ranges, eg: 200 and
+// 301 but not 403; the results matching a rule are recursed into as the rule decides.
+func WithRecursionStatuses(statuses []Range) ScannerOption {
+	return func(s *Scanner) {
+		s.recursionStatuses = statuses
+	}
+}
+
+type decision struct {
+	report   bool
+	recurse  bool
+	bypass   bool
+	escalate bool
+}
+
+func (s *Scanner) decide(result Result) decision {
+	if s.rules != nil {
+		if actions, found := s.rules.Match(result); found {
+			return decisionFromActions(actions)
+		}
+	}
+
+	ignored := s.resultFilter.ShouldIgnore(result)
+
+	recurse := !ignored
+	if len(s.recursionStatuses) > 0 {
+		recurse = recurse && InRanges(s.recursionStatuses, int64(result.StatusCode))
+	}
+
+	return decision{report: !ignored, recurse: recurse}
+}
+
+func decisionFromActions(actions []Action) decision {
+	d := decision{}
+
+	for _, action := range actions {
+		switch action {
+		case ActionReport:
+			d.report = true
+		case ActionRecurse:
+			d.recurse = true
+		case ActionRetryWithBypass:
+			d.bypass = true
+		case ActionEscalateSeverity:
+			d.escalate = true
+		case ActionIgnore:
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"io/ioutil"
+	"net/http"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"gopkg.in/yaml.v3"
+)
+
+// Condition describes the responses a ClassificationRule applies to, every condition set has to be met.
+type Condition struct {
+	// StatusFrom and StatusTo are the range of status codes matched, 0 to match any status code.
+	StatusFrom int
+	StatusTo   int
+	// Headers maps the name of a header to the regex its value has to match.
+	Headers map[string]*regexp.Regexp
+	// Body is the regex the body has to match, nil to match any body.
+	Body *regexp.Regexp
+	// MinSize and MaxSize are the bounds of the size of the body, 0 for no bound.
+	MinSize int64
+	MaxSize int64
+}
+
+// ClassificationRule attaches its label, and optionally a severity, to the results meeting its condition.
+type ClassificationRule struct {
+	Label     string
+	Severity  string
+	Condition Condition
+}
+
+type rawClassificationRules struct {
+	Classifiers []struct {
+		Label    string `yaml:"label"`
+		Severity string `yaml:"severity"`
+		Match    struct {
+			Status  string            `yaml:"status"`
+			Headers map[string]string `yaml:"headers"`
+			Body    string            `yaml:"body"`
+			MinSize int64             `yaml:"min-size"`
+			MaxSize int64             `yaml:"max-size"`
+		} `yaml:"match"`
+	} `yaml:"classifiers"`
+}
+
+// LoadClassifierFromFile reads the classification rules from a yaml file, eg:
+//
+//	classifiers:
+//	  - label: stacktrace
+//	    severity: medium
+//	    match:
+//	      status: 500-599
+//	      body: '(?i)(traceback|stack trace|exception)'
+//	  - label: login-page
+//	    match:
+//	      body: '(?i)<input[^>]+type="password"'
+func LoadClassifierFromFile(path string) (Classifier, error) {
+	raw, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return Classifier{}, errors.Wrapf(err, "failed to read classification rules `%s`", path)
+	}
+
+	rawRules := rawClassificationRules{}
+	if err := yaml.Unmarshal(raw, &rawRules); err != nil {
+		return Classifier{}, errors.Wrapf(err, "failed to parse classification rules `%s`", path)
+	}
+
+	rules := make([]ClassificationRule, 0, len(rawRules.Classifiers))
+
+	for i, rawRule := range rawRules.Classifiers {
+		rule := ClassificationRule{
+			Label:    rawRule.Label,
+			Severity: rawRule.Severity,
+			Condition: Condition{
+				Headers: make(map[string]*regexp.Regexp, len(rawRule.Match.Headers)),
+				MinSize: rawRule.Match.MinSize,
+				MaxSize: rawRule.Match.MaxSize,
+			},
+		}
+
+		if err := validateClassificationRule(rule); err != nil {
+			return Classifier{}, errors.Wrapf(err, "invalid classifier %d", i+1)
+		}
+
+		if rawRule.Match.Status != "" {
+			rule.Condition.StatusFrom, rule.Condition.StatusTo, err = parseStatusRange(rawRule.Match.Status)
+			if err != nil {
+				return Classifier{}, errors.Wrapf(err, "invalid classifier %d", i+1)
+			}
+		}
+
+		for name, pattern := range rawRule.Match.Headers {
+			if rule.Condition.Headers[name], err = regexp.Compile(pattern); err != nil {
+				return Classifier{}, errors.Wrapf(err, "invalid classifier %d: invalid regex for header %s", i+1, name)
+			}
+		}
+
+		if rawRule.Match.Body != "" {
+			if rule.Condition.Body, err = regexp.Compile(rawRule.Match.Body); err != nil {
+				return Classifier{}, errors.Wrapf(err, "invalid classifier %d: invalid body regex", i+1)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return NewClassifier(rules), nil
+}
+
+func validateClassificationRule(rule ClassificationRule) error {
+	if rule.Label == "" {
+		return errors.New("a label is required")
+	}
+
+	if rule.Severity == "" {
+		return nil
+	}
+
+	for _, severity := range scan.Severities() {
+		if rule.Severity == severity {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown severity `%s`, valid values are %v", rule.Severity, scan.Severities())
+}
+
+// NewClassifier creates a classifier attaching the labels of all the rules met by a result.
+func NewClassifier(rules []ClassificationRule) Classifier {
+	return Classifier{rules: rules}
+}
+
+type Classifier struct {
+	rules []ClassificationRule
+}
+
+func (c Classifier) Classify(result scan.Result, header http.Header, body []byte) scan.Classification {
+	classification := scan.Classification{}
+
+	for _, rule := range c.rules {
+		if !rule.Condition.matches(result, header, body) {
+			continue
+		}
+
+		classification.Labels = append(classification.Labels, rule.Label)
+
+		classification.Severity = scan.EscalateSeverity(classification.Severity, rule.Severity)
+	}
+
+	return classification
+}
+
+func (c Condition) matches(result scan.Result, header http.Header, body []byte) bool {
+	if c.StatusFrom != 0 && (result.StatusCode < c.StatusFrom || result.StatusCode > c.StatusTo) {
+		return false
+	}
+
+	for name, expression := range c.Headers {
+		values, found := header[http.CanonicalHeaderKey(name)]
+		if !found || !anyMatches(expression, values) {
+			return false
+		}
+	}
+
+	size := result.ContentLength
+	if size < 0 {
+		size = int64(len(body))
+	}
+
+	if (c.MinSize > 0 && size < c.MinSize) || (c.MaxSize > 0 && size > c.MaxSize) {
+		return false
+	}
+
+	return c.Body == nil || c.Body.Match(body)
+}
+
+func anyMatches(expression *regexp.Regexp, values []string) bool {
+	for _, v := range values {
+		if expression.MatchString(v) {
+			return true
+		}
+	}
+
+	return false
+}
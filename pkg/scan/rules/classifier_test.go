@@ -0,0 +1,128 @@
+package rules_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadClassifierFromFile(t *testing.T) {
+	sut, err := rules.LoadClassifierFromFile("testdata/classify.yaml")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name                   string
+		result                 scan.Result
+		header                 http.Header
+		body                   string
+		expectedClassification scan.Classification
+	}{
+		{
+			name:   "stacktrace",
+			result: scan.Result{StatusCode: http.StatusInternalServerError, ContentLength: -1},
+			body:   "Traceback (most recent call last):",
+			expectedClassification: scan.Classification{
+				Labels:   []string{"stacktrace"},
+				Severity: scan.SeverityMedium,
+			},
+		},
+		{
+			name:                   "exception with a successful status",
+			result:                 scan.Result{StatusCode: http.StatusOK, ContentLength: -1},
+			body:                   "Traceback (most recent call last):",
+			expectedClassification: scan.Classification{},
+		},
+		{
+			name:   "login page of jenkins",
+			result: scan.Result{StatusCode: http.StatusOK, ContentLength: -1},
+			header: http.Header{"X-Jenkins": []string{"2.361"}},
+			body:   `<form><input name="j_password" type="password"></form>`,
+			expectedClassification: scan.Classification{
+				Labels:   []string{"login-page", "jenkins"},
+				Severity: scan.SeverityHigh,
+			},
+		},
+		{
+			name:                   "large body",
+			result:                 scan.Result{StatusCode: http.StatusOK, ContentLength: -1},
+			body:                   strings.Repeat("a", 1000),
+			expectedClassification: scan.Classification{Labels: []string{"large"}},
+		},
+		{
+			name:                   "large declared content length",
+			result:                 scan.Result{StatusCode: http.StatusOK, ContentLength: 5000},
+			expectedClassification: scan.Classification{Labels: []string{"large"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := sut.Classify(tc.result, tc.header, []byte(tc.body))
+			assert.Equal(t, tc.expectedClassification, actual)
+		})
+	}
+}
+
+func TestLoadClassifierFromFileShouldErrForInvalidRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         string
+		expectedError string
+	}{
+		{
+			name:          "missing label",
+			rules:         "classifiers:\n  - match:\n      status: 200\n",
+			expectedError: "invalid classifier 1: a label is required",
+		},
+		{
+			name:          "unknown severity",
+			rules:         "classifiers:\n  - label: a\n    severity: critical\n",
+			expectedError: "unknown severity `critical`",
+		},
+		{
+			name:          "invalid status",
+			rules:         "classifiers:\n  - label: a\n    match:\n      status: 5xx\n",
+			expectedError: "invalid status `5xx`",
+		},
+		{
+			name:          "invalid body regex",
+			rules:         "classifiers:\n  - label: a\n    match:\n      body: '(['\n",
+			expectedError: "invalid body regex",
+		},
+		{
+			name:          "invalid header regex",
+			rules:         "classifiers:\n  - label: a\n    match:\n      headers:\n        Server: '(['\n",
+			expectedError: "invalid regex for header Server",
+		},
+		{
+			name:          "invalid yaml",
+			rules:         "classifiers: [",
+			expectedError: "failed to parse classification rules",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "classify.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tc.rules), 0o600))
+
+			_, err := rules.LoadClassifierFromFile(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package rules
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"gopkg.in/yaml.v3"
+)
+
+// StatusRule applies its actions to the results whose status code is between From and To
+// and, when Label is set, that were labeled with it by the Classifier.
+type StatusRule struct {
+	From    int
+	To      int
+	Label   string
+	Actions []scan.Action
+}
+
+type rawStatusRules struct {
+	Rules []struct {
+		Status  string        `yaml:"status"`
+		Label   string        `yaml:"label"`
+		Actions []scan.Action `yaml:"actions"`
+	} `yaml:"rules"`
+}
+
+// LoadStatusRulesFromFile reads the rules from a yaml file, eg:
+//
+//	rules:
+//	  - status: 403
+//	    actions: [report, retry-with-bypass]
+//	  - status: 500-599
+//	    actions: [report, escalate-severity]
+//	  - label: login-page
+//	    actions: [ignore]
+func LoadStatusRulesFromFile(path string) (StatusRules, error) {
+	raw, err := ioutil.ReadFile(path) // #nosec
+	if err != nil {
+		return StatusRules{}, errors.Wrapf(err, "failed to read rules `%s`", path)
+	}
+
+	rawRules := rawStatusRules{}
+	if err := yaml.Unmarshal(raw, &rawRules); err != nil {
+		return StatusRules{}, errors.Wrapf(err, "failed to parse rules `%s`", path)
+	}
+
+	rules := make([]StatusRule, 0, len(rawRules.Rules))
+
+	for i, rawRule := range rawRules.Rules {
+		rule, err := newStatusRule(rawRule.Status, rawRule.Label, rawRule.Actions)
+		if err != nil {
+			return StatusRules{}, errors.Wrapf(err, "invalid rule %d", i+1)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return NewStatusRules(rules), nil
+}
+
+func newStatusRule(status, label string, actions []scan.Action) (StatusRule, error) {
+	from, to := 0, 0
+
+	switch {
+	case status != "":
+		var err error
+		if from, to, err = parseStatusRange(status); err != nil {
+			return StatusRule{}, err
+		}
+	case label == "":
+		return StatusRule{}, errors.New("a status or a label is required")
+	}
+
+	if len(actions) == 0 {
+		return StatusRule{}, errors.New("at least one action is required")
+	}
+
+	for _, action := range actions {
+		if !isValidAction(action) {
+			return StatusRule{}, errors.Errorf("unknown action `%s`, valid values are %v", action, scan.Actions())
+		}
+	}
+
+	return StatusRule{From: from, To: to, Label: label, Actions: actions}, nil
+}
+
+func parseStatusRange(status string) (int, int, error) {
+	bounds := strings.SplitN(status, "-", 2)
+
+	from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid status `%s`, expected a status code or a range like 500-599", status)
+	}
+
+	to := from
+
+	if len(bounds) == 2 {
+		if to, err = strconv.Atoi(strings.TrimSpace(bounds[1])); err != nil || to < from {
+			return 0, 0, errors.Errorf("invalid status `%s`, expected a status code or a range like 500-599", status)
+		}
+	}
+
+	return from, to, nil
+}
+
+func isValidAction(action scan.Action) bool {
+	for _, a := range scan.Actions() {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewStatusRules creates rules matched in order, the first rule met by the result applies.
+func NewStatusRules(rules []StatusRule) StatusRules {
+	return StatusRules{rules: rules}
+}
+
+type StatusRules struct {
+	rules []StatusRule
+}
+
+func (r StatusRules) Match(result scan.Result) ([]scan.Action, bool) {
+	for _, rule := range r.rules {
+		if rule.matches(result) {
+			return rule.Actions, true
+		}
+	}
+
+	return nil, false
+}
+
+func (r StatusRule) matches(result scan.Result) bool {
+	// a rule without status only matches on the label
+	if r.From != 0 && (result.StatusCode < r.From || result.StatusCode > r.To) {
+		return false
+	}
+
+	if r.Label == "" {
+		return true
+	}
+
+	for _, label := range result.Labels {
+		if label == r.Label {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package rules_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStatusRulesFromFile(t *testing.T) {
+	sut, err := rules.LoadStatusRulesFromFile("testdata/rules.yaml")
+	assert.NoError(t, err)
+
+	bypass := []scan.Action{scan.ActionReport, scan.ActionRetryWithBypass}
+
+	testCases := []struct {
+		statusCode      int
+		expectedActions []scan.Action
+		expectedFound   bool
+	}{
+		{statusCode: 404, expectedActions: []scan.Action{scan.ActionIgnore}, expectedFound: true},
+		{statusCode: 401, expectedActions: bypass, expectedFound: true},
+		{statusCode: 403, expectedActions: bypass, expectedFound: true},
+		{statusCode: 503, expectedActions: []scan.Action{scan.ActionReport, scan.ActionEscalateSeverity}, expectedFound: true},
+		{statusCode: 204, expectedActions: []scan.Action{scan.ActionReport, scan.ActionRecurse}, expectedFound: true},
+		{statusCode: 301, expectedFound: false},
+	}
+
+	for _, tc := range testCases {
+		actions, found := sut.Match(scan.Result{StatusCode: tc.statusCode})
+
+		assert.Equal(t, tc.expectedFound, found, "status %d", tc.statusCode)
+		assert.Equal(t, tc.expectedActions, actions, "status %d", tc.statusCode)
+	}
+}
+
+func TestStatusRulesShouldApplyTheFirstMatchingRule(t *testing.T) {
+	sut := rules.NewStatusRules([]rules.StatusRule{
+		{From: 403, To: 403, Actions: []scan.Action{scan.ActionIgnore}},
+		{From: 400, To: 499, Actions: []scan.Action{scan.ActionReport}},
+	})
+
+	actions, found := sut.Match(scan.Result{StatusCode: 403})
+	assert.True(t, found)
+	assert.Equal(t, []scan.Action{scan.ActionIgnore}, actions)
+}
+
+func TestStatusRulesCanMatchTheLabels(t *testing.T) {
+	sut := rules.NewStatusRules([]rules.StatusRule{
+		{Label: "login-page", Actions: []scan.Action{scan.ActionIgnore}},
+		{From: 500, To: 599, Label: "stacktrace", Actions: []scan.Action{scan.ActionReport}},
+	})
+
+	actions, found := sut.Match(scan.Result{StatusCode: 200, Labels: []string{"login-page"}})
+	assert.True(t, found)
+	assert.Equal(t, []scan.Action{scan.ActionIgnore}, actions)
+
+	_, found = sut.Match(scan.Result{StatusCode: 200, Labels: []string{"stacktrace"}})
+	assert.False(t, found)
+
+	actions, found = sut.Match(scan.Result{StatusCode: 500, Labels: []string{"stacktrace"}})
+	assert.True(t, found)
+	assert.Equal(t, []scan.Action{scan.ActionReport}, actions)
+}
+
+func TestLoadStatusRulesFromFileShouldErrForInvalidRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rules         string
+		expectedError string
+	}{
+		{
+			name:          "invalid status",
+			rules:         "rules:\n  - status: abc\n    actions: [report]\n",
+			expectedError: "invalid rule 1: invalid status `abc`",
+		},
+		{
+			name:          "inverted range",
+			rules:         "rules:\n  - status: 599-500\n    actions: [report]\n",
+			expectedError: "invalid status `599-500`",
+		},
+		{
+			name:          "unknown action",
+			rules:         "rules:\n  - status: 200\n    actions: [report]\n  - status: 500\n    actions: [panic]\n",
+			expectedError: "invalid rule 2: unknown action `panic`",
+		},
+		{
+			name:          "no status nor label",
+			rules:         "rules:\n  - actions: [report]\n",
+			expectedError: "a status or a label is required",
+		},
+		{
+			name:          "no actions",
+			rules:         "rules:\n  - status: 200\n",
+			expectedError: "at least one action is required",
+		},
+		{
+			name:          "invalid yaml",
+			rules:         "rules: [",
+			expectedError: "failed to parse rules",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // Pinning ranged variable, more info: https://github.com/kyoh86/scopelint
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tc.rules), 0o600))
+
+			_, err := rules.LoadStatusRulesFromFile(path)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
@@ -2,39 +2,139 @@ package scan
 
 import (
 	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
 	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+	"github.com/stefanoj3/dirstalk/pkg/scan/fingerprint"
+	"github.com/stefanoj3/dirstalk/pkg/scan/robots"
 )
 
+// DefaultMaxBodySize is the amount of bytes of a response body inspected by the extractors and the classifier,
+// unless changed through WithMaxBodySize.
+const DefaultMaxBodySize = 10 << 20
+
 // Target represents the target to scan.
 type Target struct {
 	Path   string
 	Method string
 	Depth  int
+
+	// sequence is the position of the target among the ones of the producer, set when tracking the progress
+	sequence uint64
 }
 
 // Result represents the result of the scan of a single URL.
 type Result struct {
-	Target        Target
-	StatusCode    int
-	URL           url.URL
-	ContentLength int64
+	Target           Target
+	StatusCode       int
+	URL              url.URL
+	ContentLength    int64
+	ContentType      string                   `json:",omitempty"`
+	Words            int                      `json:",omitempty"`
+	Lines            int                      `json:",omitempty"`
+	Host             string                   `json:",omitempty"`
+	Protocol         string                   `json:",omitempty"`
+	ScanName         string                   `json:",omitempty"`
+	Tags             map[string]string        `json:",omitempty"`
+	Extracted        map[string][]string      `json:",omitempty"`
+	Secrets          map[string][]string      `json:",omitempty"`
+	Severity         string                   `json:",omitempty"`
+	Bypass           string                   `json:",omitempty"`
+	Labels           []string                 `json:",omitempty"`
+	Retries          int                      `json:",omitempty"`
+	Allow            string                   `json:",omitempty"`
+	PermittedMethods []string                 `json:",omitempty"`
+	Redirects        []Redirect               `json:",omitempty"`
+	FinalURL         string                   `json:",omitempty"`
+	FinalStatusCode  int                      `json:",omitempty"`
+	Technologies     []fingerprint.Technology `json:",omitempty"`
+	// Headers are the interesting headers of the response, see InterestingHeaders.
+	Headers map[string]string `json:",omitempty"`
+	// BodyFile is where the body of the response was stored, see WithBodyStore.
+	BodyFile string `json:",omitempty"`
+}
+
+// InterestingHeaders returns the response headers recorded in the results, as they reveal the technologies
+// of the target or where it redirects to.
+func InterestingHeaders() []string {
+	return []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version", "Location"}
+}
+
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	// SeverityHigh is the severity of the results exposing secrets.
+	SeverityHigh = "high"
+)
+
+// Severities returns the available severities, from the lowest to the highest.
+func Severities() []string {
+	return []string{SeverityLow, SeverityMedium, SeverityHigh}
+}
+
+// EscalateSeverity returns the highest of the given severities.
+func EscalateSeverity(current, severity string) string {
+	rank := func(s string) int {
+		for i, known := range Severities() {
+			if s == known {
+				return i + 1
+			}
+		}
+
+		return 0
+	}
+
+	if rank(severity) > rank(current) {
+		return severity
+	}
+
+	return current
 }
 
 // NewResult creates a new instance of the Result entity based on the Target and Response.
 func NewResult(target Target, response *http.Response) Result {
-	return Result{
+	result := Result{
 		Target:        target,
 		StatusCode:    response.StatusCode,
 		URL:           *response.Request.URL,
 		ContentLength: response.ContentLength,
+		ContentType:   response.Header.Get("Content-Type"),
+		Protocol:      response.Proto,
+	}
+
+	// the position of the target is not part of the result, nor of the targets reproduced out of it
+	result.Target.sequence = 0
+
+	if host := response.Request.Host; host != "" && host != response.Request.URL.Host {
+		result.Host = host
+	}
+
+	for _, name := range InterestingHeaders() {
+		values := response.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if result.Headers == nil {
+			result.Headers = make(map[string]string)
+		}
+
+		result.Headers[name] = strings.Join(values, ", ")
 	}
+
+	return result
 }
 
 func NewScanner(
@@ -43,72 +143,292 @@ func NewScanner(
 	reproducer ReProducer,
 	resultFilter ResultFilter,
 	logger *logrus.Logger,
+	opts ...ScannerOption,
 ) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		httpClient:   httpClient,
 		producer:     producer,
 		reproducer:   reproducer,
 		resultFilter: resultFilter,
 		logger:       logger,
+		workers:      newWorkerLimiter(1),
+		branches:     newBranchRegistry(),
+		maxBodySize:  DefaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// the adaptive rate slows down the scan through the rate limiter, even when no rate limit is set
+	if s.adaptiveRate && s.rateLimiter == nil {
+		s.rateLimiter = &rateLimiter{}
 	}
+
+	return s
 }
 
 type Scanner struct {
-	httpClient   Doer
-	producer     Producer
-	reproducer   ReProducer
-	resultFilter ResultFilter
-	logger       *logrus.Logger
+	// accessed atomically, kept first to be 64-bit aligned on 32-bit platforms
+	requests uint64
+	errors   uint64
+	results  uint64
+	// budgetConsumed counts the requests reserved out of maxRequests
+	budgetConsumed uint64
+
+	httpClient      Doer
+	producer        Producer
+	reproducer      ReProducer
+	resultFilter    ResultFilter
+	logger          *logrus.Logger
+	workers         *workerLimiter
+	branches        *branchRegistry
+	extractor       Extractor
+	secretExtractor Extractor
+	rules           Rules
+	classifier      Classifier
+	retries         int
+	retryBackoff    time.Duration
+	rateLimiter     *rateLimiter
+	delay           time.Duration
+	delayJitter     time.Duration
+	adaptiveRate    bool
+	circuitBreaker  *circuitBreaker
+	maxErrors       int
+	maxRequests     uint64
+	progress        *progressTracker
+	pause           pauseGate
+	limiter         *ConcurrencyLimiter
+	fuzzingURL      *url.URL
+	bodyTemplate    string
+	contentType     string
+	maxBodySize     int64
+	host            string
+	abortMx         sync.Mutex
+	abortErr        error
+	cancelScan      context.CancelFunc
+	stopDispatch    context.CancelFunc
+	stopped         int32
+	startedAt       atomic.Value
+	negotiatedTLS   atomic.Value
+	certificates    certificateRegistry
+	calibrations    *calibrations
+	bodyMetrics     bool
+	backupVariants  bool
+	leakChecks      bool
+	leaksChecked    leaksChecked
+	fingerprinting  bool
+	technologies    technologyRegistry
+	bodyStore       BodyStore
+	probeMethods    []string
+	methodOverride  bool
+	maxRedirects    int
+	scope           client.Scope
+	robotsRules     *robots.Robots
+	methodsProbed   methodsProbed
+	trailingSlash   bool
+	queue           *branchQueue
+
+	recursionStatuses   []Range
+	recursionExclusions []PathPattern
+	depthOverrides      []DepthOverride
+
+	matchedBodyRegexes  []*regexp.Regexp
+	filteredBodyRegexes []*regexp.Regexp
+
+	// calibrationSimilarity is the minimum percentage of words shared with the calibration samples
+	// for the results to be ignored, 0 when disabled
+	calibrationSimilarity int
+}
+
+// Stats represents the progress of a scan.
+type Stats struct {
+	Requests       uint64
+	Errors         uint64
+	Results        uint64
+	Workers        int
+	ActiveWorkers  int
+	ActiveBranches []string
+	Paused         bool
+	Elapsed        time.Duration
 }
 
 func (s *Scanner) Scan(ctx context.Context, baseURL *url.URL, workers int) <-chan Result {
 	resultChannel := make(chan Result, workers)
 
-	u := normalizeBaseURL(*baseURL)
+	u := *baseURL
+
+	// the url is requested as is when fuzzing, while the paths are otherwise appended to it
+	if s.isFuzzing(u) {
+		if u.Path == "" {
+			u.Path = "/"
+		}
+
+		s.fuzzingURL = &u
+	} else {
+		u = normalizeBaseURL(u)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	// the targets are dispatched until Stop is called, while the requests in flight complete unless ctx is done
+	dispatchCtx, stopDispatch := context.WithCancel(ctx)
+
+	s.abortMx.Lock()
+	s.cancelScan = cancel
+	s.stopDispatch = stopDispatch
+	s.abortMx.Unlock()
+
+	s.SetWorkers(workers)
+	s.startedAt.Store(time.Now())
 
 	wg := sync.WaitGroup{}
 
-	producerChannel := s.producer.Produce(ctx)
-	reproducer := s.reproducer.Reproduce(ctx)
+	producerChannel := s.producer.Produce(dispatchCtx)
+	reproducer := s.reproducer.Reproduce(dispatchCtx)
 
-	wg.Add(workers)
+	if s.progress != nil {
+		producerChannel = s.progress.number(producerChannel)
+	}
+
+	// dispatch scans the targets, skipping the given amount of them which were already scanned
+	dispatch := func(targets <-chan Target, skip uint64) {
+		for target := range targets {
+			if skip > 0 {
+				skip--
+
+				continue
+			}
 
-	for i := 0; i < workers; i++ {
-		go func() {
-			defer wg.Done()
+			// the remaining targets of a skipped branch still have to be drained
+			if s.queue != nil && s.branches.isSkipped(target.Path) {
+				continue
+			}
+
+			// the targets left once the request budget is exhausted or the scan stopped are drained as well
+			if (s.maxRequests > 0 && s.Err() != nil) || s.Stopped() {
+				continue
+			}
 
-			for {
-				select {
-				case <-ctx.Done():
-					s.logger.Debug("terminating worker: context cancellation")
-				case target, ok := <-producerChannel:
-					if !ok {
-						s.logger.Debug("terminating worker: producer channel closed")
+			s.workers.acquire()
 
-						return
-					}
+			// the scan may have been stopped while waiting for a worker
+			if s.Stopped() {
+				s.workers.release()
 
-					s.processTarget(ctx, u, target, reproducer, resultChannel)
-				}
+				continue
 			}
-		}()
+
+			wg.Add(1)
+
+			go func(target Target) {
+				defer wg.Done()
+				defer s.workers.release()
+
+				s.processTarget(ctx, u, target, reproducer, resultChannel)
+				s.completeTarget(ctx, target)
+			}(target)
+		}
 	}
 
 	go func() {
+		if s.fingerprinting && s.fuzzingURL == nil {
+			s.fingerprintTarget(ctx, u)
+		}
+
+		if s.leakChecks && s.fuzzingURL == nil {
+			s.checkLeaks(ctx, s.logger.WithField("path", ""), u, "", resultChannel)
+		}
+
+		if s.progress != nil {
+			s.resumeBranches(ctx, u, &wg, reproducer, resultChannel)
+		}
+
+		dispatch(producerChannel, 0)
+
+		s.logger.Debug("producer channel closed, waiting for the workers to terminate")
+
+		wg.Wait()
+
+		if s.queue != nil {
+			s.scanQueuedBranches(dispatchCtx, &wg, dispatch, reproducer)
+		}
+
+		// the targets dispatched before the scan stopped may still be in flight
 		wg.Wait()
+
+		stopDispatch()
+		cancel()
 		close(resultChannel)
 	}()
 
 	return resultChannel
 }
 
+// SetWorkers changes the number of targets processed concurrently, it can be called while scanning.
+func (s *Scanner) SetWorkers(workers int) {
+	s.workers.setLimit(workers)
+}
+
+// Workers returns the number of targets that can be processed concurrently.
+func (s *Scanner) Workers() int {
+	limit, _ := s.workers.state()
+
+	return limit
+}
+
+// SkipActiveBranches stops the recursion into the deepest paths currently being scanned and returns them.
+func (s *Scanner) SkipActiveBranches() []string {
+	return s.branches.skipDeepest()
+}
+
+// NegotiatedTLS returns the TLS version and cipher suite negotiated with the target in the first TLS connection,
+// nil when no TLS connection was made.
+func (s *Scanner) NegotiatedTLS() *TLS {
+	negotiated, _ := s.negotiatedTLS.Load().(*TLS)
+
+	return negotiated
+}
+
+func (s *Scanner) recordNegotiatedTLS(state *tls.ConnectionState) {
+	if state == nil || s.negotiatedTLS.Load() != nil {
+		return
+	}
+
+	s.negotiatedTLS.CompareAndSwap(nil, &TLS{
+		Version:     client.TLSVersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	})
+}
+
+// Stats returns the progress of the scan.
+func (s *Scanner) Stats() Stats {
+	limit, active := s.workers.state()
+
+	stats := Stats{
+		Requests:       atomic.LoadUint64(&s.requests),
+		Errors:         atomic.LoadUint64(&s.errors),
+		Results:        atomic.LoadUint64(&s.results),
+		Workers:        limit,
+		ActiveWorkers:  active,
+		ActiveBranches: s.branches.activeBranches(),
+		Paused:         s.Paused(),
+	}
+
+	if startedAt, ok := s.startedAt.Load().(time.Time); ok {
+		stats.Elapsed = time.Since(startedAt)
+	}
+
+	return stats
+}
+
 func (s *Scanner) processTarget(
 	ctx context.Context,
 	baseURL url.URL,
 	target Target,
 	reproducer func(r Result) <-chan Target,
 	results chan<- Result,
-) {
+) int {
 	l := s.logger.WithFields(logrus.Fields{
 		"method": target.Method,
 		"depth":  target.Depth,
@@ -117,16 +437,34 @@ func (s *Scanner) processTarget(
 
 	l.Debug("Working")
 
-	u := buildURL(baseURL, target)
+	u := s.buildURL(baseURL, target)
+
+	req, err := s.newRequest(ctx, u, target)
+	if errors.Is(err, errInvalidHost) {
+		l.WithError(err).Debug("skipping, the path is not a host")
+
+		return 0
+	}
 
-	req, err := http.NewRequestWithContext(ctx, target.Method, u.String(), nil)
 	if err != nil {
 		l.WithError(err).Error("failed to build request")
 
-		return
+		return 0
 	}
 
-	s.processRequest(ctx, l, req, target, results, reproducer, baseURL)
+	if !s.allowedByRobots(req.URL) {
+		l.Debug("skipping, disallowed by robots.txt")
+
+		return 0
+	}
+
+	statusCode, redirected := s.processRequest(ctx, l, req, target, results, reproducer, baseURL)
+
+	if s.trailingSlash && statusCode != 0 && !redirected && needsTrailingSlash(target) && !s.fuzzingValues() {
+		s.processTrailingSlash(ctx, l, baseURL, target, statusCode, reproducer, results)
+	}
+
+	return statusCode
 }
 
 func (s *Scanner) processRequest(
@@ -137,39 +475,269 @@ func (s *Scanner) processRequest(
 	results chan<- Result,
 	reproducer func(r Result) <-chan Target,
 	baseURL url.URL,
-) {
-	res, err := s.httpClient.Do(req)
-	if err != nil && strings.Contains(err.Error(), client.ErrRequestRedundant.Error()) {
-		l.WithError(err).Debug("skipping, request was already made")
-
-		return
+) (statusCode int, redirected bool) {
+	res, retries, err := s.doWithRetries(ctx, l, req)
+	if skipped(l, err) {
+		return 0, false
 	}
 
+	atomic.AddUint64(&s.requests, 1)
+
+	s.recordConnectionResult(err)
+
 	if err != nil {
+		errorsCount := atomic.AddUint64(&s.errors, 1)
+
 		l.WithError(err).Error("failed to perform request")
 
-		return
+		s.recordError(err, errorsCount)
+
+		return 0, false
+	}
+
+	s.recordNegotiatedTLS(res.TLS)
+	s.recordCertificate(res.Request.URL.Host, res.TLS)
+
+	result, d := s.evaluate(ctx, l, req, res, target)
+	result.Retries = retries
+
+	if d.report {
+		if len(s.probeMethods) > 0 {
+			s.probeResultMethods(ctx, l, req, &result)
+		}
+
+		if s.maxRedirects > 0 {
+			s.followRedirects(ctx, l, req, res, &result)
+		}
+
+		atomic.AddUint64(&s.results, 1)
+
+		results <- result
+
+		redirectTarget, shouldRedirect := s.shouldRedirect(l, req, res, target.Depth)
+		if shouldRedirect {
+			if location, err := res.Location(); err == nil && !s.inScope(location) {
+				s.reportOutOfScope(l, redirectTarget, *location, results)
+			} else {
+				s.processTarget(ctx, baseURL, redirectTarget, reproducer, results)
+			}
+
+			redirected = true
+		}
+	}
+
+	s.probeAround(ctx, l, req, res.StatusCode, d, baseURL, target, results)
+
+	if !d.recurse || s.fuzzingValues() {
+		return res.StatusCode, redirected
+	}
+
+	var recurse bool
+	if result.Target, recurse = s.recursionTarget(result.Target); !recurse {
+		l.Debug("not recursing, the path is excluded from the recursion")
+
+		return res.StatusCode, redirected
+	}
+
+	b := &branch{result: result}
+
+	s.trackBranch(b, target)
+
+	if s.queue != nil {
+		s.queue.push(b)
+
+		return res.StatusCode, redirected
+	}
+
+	s.recurse(ctx, baseURL, b, reproducer, results)
+
+	return res.StatusCode, redirected
+}
+
+// skipped tells whether the request was not performed on purpose, eg: it was already made or it is out of scope.
+func skipped(l *logrus.Entry, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case strings.Contains(err.Error(), client.ErrRequestRedundant.Error()):
+		l.WithError(err).Debug("skipping, request was already made")
+	case strings.Contains(err.Error(), client.ErrRequestOutOfScope.Error()):
+		l.WithError(err).Debug("skipping, request is out of scope")
+	case errors.Is(err, ErrRequestBudgetExhausted):
+		l.Debug("skipping, the request budget is exhausted")
+	default:
+		return false
+	}
+
+	return true
+}
+
+// probeAround retries the request to get around the restrictions of the target and probes the paths related
+// to the target, depending on the response it got.
+func (s *Scanner) probeAround(
+	ctx context.Context,
+	l *logrus.Entry,
+	req *http.Request,
+	statusCode int,
+	d decision,
+	baseURL url.URL,
+	target Target,
+	results chan<- Result,
+) {
+	if d.bypass {
+		s.retryWithBypass(l, req, target, results)
+	}
+
+	if s.methodOverride && (statusCode == http.StatusForbidden || statusCode == http.StatusMethodNotAllowed) {
+		s.retryWithOverrides(l, req, target, results)
+	}
+
+	if d.report && s.backupVariants && isFile(target) && !s.fuzzingValues() {
+		s.probeBackupVariants(ctx, l, baseURL, target, results)
+	}
+
+	if d.report && s.leakChecks && !urlpath.HasExtension(target.Path) && !s.fuzzingValues() {
+		s.checkLeaks(ctx, l, baseURL, target.Path, results)
+	}
+}
+
+// recurse scans the targets of the branch one after the other, skipping the ones it already reproduced.
+func (s *Scanner) recurse(
+	ctx context.Context,
+	baseURL url.URL,
+	b *branch,
+	reproducer func(r Result) <-chan Target,
+	results chan<- Result,
+) {
+	s.branches.enter(b.result.Target.Path)
+	defer s.branches.leave(b.result.Target.Path)
+
+	skip := atomic.LoadUint64(&b.reproduced)
+	reproduced := uint64(0)
+
+	for newTarget := range reproducer(b.result) {
+		reproduced++
+
+		// the remaining targets of a skipped branch or of a stopped scan still have to be drained
+		if reproduced > skip && !s.branches.isSkipped(newTarget.Path) && !s.Stopped() {
+			s.processTarget(ctx, baseURL, newTarget, reproducer, results)
+		}
+
+		if !s.interrupted(ctx) {
+			atomic.StoreUint64(&b.reproduced, reproduced)
+		}
+	}
+
+	s.untrackBranches(ctx, b)
+}
+
+// evaluate builds the result of the response and decides what to do with it, the body of the response is
+// read only when needed and always closed.
+func (s *Scanner) evaluate(
+	ctx context.Context,
+	l *logrus.Entry,
+	req *http.Request,
+	res *http.Response,
+	target Target,
+) (Result, decision) {
+	result := NewResult(target, res)
+
+	var body []byte
+
+	bodyRead := false
+	readBody := func() []byte {
+		if !bodyRead {
+			body, bodyRead = s.readBody(l, res), true
+		}
+
+		return body
+	}
+
+	// the length of the decompressed bodies is unknown, it is measured so that the filters
+	// based on the size reflect the actual content
+	if result.ContentLength < 0 && (res.Uncompressed || s.bodyMetrics) {
+		if decoded := readBody(); int64(len(decoded)) < s.maxBodySize {
+			result.ContentLength = int64(len(decoded))
+		}
+	}
+
+	if s.bodyMetrics {
+		result.Words, result.Lines = countWords(readBody()), countLines(readBody())
+	}
+
+	if s.classifier != nil {
+		classification := s.classifier.Classify(result, res.Header, readBody())
+
+		result.Labels = classification.Labels
+		result.Severity = EscalateSeverity(result.Severity, classification.Severity)
+	}
+
+	d := s.decide(result)
+
+	if d.report && s.hasBodyRegexes() && s.ignoredByBody(readBody()) {
+		l.Debug("ignoring, the body is filtered by the regexes")
+
+		d = decision{}
+	}
+
+	if d.report && s.calibrations != nil && s.matchesCalibration(ctx, l, req, res, readBody(), target) {
+		l.Debug("ignoring, the response matches the ones of the non-existent paths")
+
+		d = decision{}
+	}
+
+	// the bodies of the ignored results are never inspected
+	if d.report {
+		s.inspectBody(l, res.Header, readBody, &result)
 	}
 
 	if err := res.Body.Close(); err != nil {
 		l.WithError(err).Warn("failed to close response body")
 	}
 
-	result := NewResult(target, res)
+	if d.escalate {
+		result.Severity = SeverityHigh
+	}
 
-	if s.resultFilter.ShouldIgnore(result) {
-		return
+	return result, d
+}
+
+// inspectBody extracts from the body of a reported result, fingerprints it and stores it, as asked.
+func (s *Scanner) inspectBody(l *logrus.Entry, header http.Header, readBody func() []byte, result *Result) {
+	if s.extractor != nil || s.secretExtractor != nil {
+		s.extractFromBody(readBody(), result)
 	}
 
-	results <- result
+	if s.fingerprinting {
+		result.Technologies = fingerprint.Fingerprint(header, readBody())
+		s.recordTechnologies(l, result.Technologies)
+	}
 
-	redirectTarget, shouldRedirect := s.shouldRedirect(l, req, res, target.Depth)
-	if shouldRedirect {
-		s.processTarget(ctx, baseURL, redirectTarget, reproducer, results)
+	if s.bodyStore != nil {
+		s.storeBody(l, readBody(), result)
 	}
+}
 
-	for newTarget := range reproducer(result) {
-		s.processTarget(ctx, baseURL, newTarget, reproducer, results)
+func (s *Scanner) readBody(l *logrus.Entry, res *http.Response) []byte {
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, s.maxBodySize))
+	if err != nil {
+		l.WithError(err).Warn("failed to read response body")
+	}
+
+	return body
+}
+
+func (s *Scanner) extractFromBody(body []byte, result *Result) {
+	if s.extractor != nil {
+		result.Extracted = s.extractor.Extract(body)
+	}
+
+	if s.secretExtractor != nil {
+		result.Secrets = s.secretExtractor.Extract(body)
+
+		if len(result.Secrets) > 0 {
+			result.Severity = SeverityHigh
+		}
 	}
 }
 
@@ -180,6 +748,13 @@ func (s *Scanner) shouldRedirect(l *logrus.Entry, req *http.Request, res *http.R
 		return Target{}, false
 	}
 
+	// the path redirected to cannot be placed where the FuzzKeyword is
+	if s.fuzzingURL != nil {
+		l.Debug("fuzzing, not following any redirect")
+
+		return Target{}, false
+	}
+
 	redirectMethod := req.Method
 	location := res.Header.Get("Location")
 
@@ -242,7 +817,11 @@ func normalizeBaseURL(baseURL url.URL) url.URL {
 	return baseURL
 }
 
-func buildURL(baseURL url.URL, target Target) url.URL {
+func (s *Scanner) buildURL(baseURL url.URL, target Target) url.URL {
+	if s.fuzzingURL != nil {
+		return fuzzURL(baseURL, target)
+	}
+
 	baseURL.Path = urlpath.Join(baseURL.Path, target.Path)
 
 	return baseURL
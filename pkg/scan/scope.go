@@ -0,0 +1,34 @@
+package scan
+
+import (
+	"net/url"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/client"
+)
+
+// LabelOutOfScope is the label of the paths discovered out of the scope, which are reported without being
+// requested, see WithScope.
+const LabelOutOfScope = "out-of-scope"
+
+// WithScope keeps the redirects from leading the scan out of the given scope: the paths redirected to out of
+// it are reported with the LabelOutOfScope label rather than being requested and recursed into.
+func WithScope(scope client.Scope) ScannerOption {
+	return func(s *Scanner) {
+		s.scope = scope
+	}
+}
+
+func (s *Scanner) inScope(u *url.URL) bool {
+	return s.scope == nil || s.scope.InScope(u)
+}
+
+// reportOutOfScope emits the path discovered out of the scope, without requesting it.
+func (s *Scanner) reportOutOfScope(l *logrus.Entry, target Target, u url.URL, results chan<- Result) {
+	l.WithField("url", u.String()).Info("Discovered a path out of scope, not requesting it")
+
+	atomic.AddUint64(&s.results, 1)
+
+	results <- Result{Target: target, URL: u, Labels: []string{LabelOutOfScope}}
+}
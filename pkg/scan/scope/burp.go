@@ -0,0 +1,189 @@
+package scope
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const protocolAny = "any"
+
+// NewBurpScopeFromFile reads a Burp Suite project options export (the `target.scope` section)
+// and builds a BurpScope out of it. Both simple (prefix based) and advanced (regex based) modes are supported.
+func NewBurpScopeFromFile(path string) (*BurpScope, error) {
+	raw, err := os.ReadFile(path) // #nosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read scope file %s", path)
+	}
+
+	return NewBurpScope(raw)
+}
+
+// NewBurpScope builds a BurpScope from the raw json of a Burp Suite project options export.
+func NewBurpScope(raw []byte) (*BurpScope, error) {
+	cnf := burpConfig{}
+
+	if err := json.Unmarshal(raw, &cnf); err != nil {
+		return nil, errors.Wrap(err, "failed to decode burp scope")
+	}
+
+	s := &BurpScope{}
+
+	var err error
+
+	if s.include, err = buildBurpRules(cnf.Target.Scope.AdvancedMode, cnf.Target.Scope.Include); err != nil {
+		return nil, errors.Wrap(err, "invalid include rule")
+	}
+
+	if s.exclude, err = buildBurpRules(cnf.Target.Scope.AdvancedMode, cnf.Target.Scope.Exclude); err != nil {
+		return nil, errors.Wrap(err, "invalid exclude rule")
+	}
+
+	if len(s.include) == 0 {
+		return nil, errors.New("the burp scope does not contain any enabled include rule")
+	}
+
+	return s, nil
+}
+
+// BurpScope decides whether a URL is in scope following the same semantic used by Burp Suite:
+// a URL is in scope when it matches at least one include rule and none of the exclude rules.
+type BurpScope struct {
+	include []burpRule
+	exclude []burpRule
+}
+
+func (s *BurpScope) InScope(u *url.URL) bool {
+	included := false
+
+	for _, rule := range s.include {
+		if rule.matches(u) {
+			included = true
+
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, rule := range s.exclude {
+		if rule.matches(u) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type burpConfig struct {
+	Target struct {
+		Scope struct {
+			AdvancedMode bool          `json:"advanced_mode"`
+			Include      []rawBurpRule `json:"include"`
+			Exclude      []rawBurpRule `json:"exclude"`
+		} `json:"scope"`
+	} `json:"target"`
+}
+
+type rawBurpRule struct {
+	Enabled  bool   `json:"enabled"`
+	Prefix   string `json:"prefix"`
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	File     string `json:"file"`
+}
+
+type burpRule struct {
+	prefix   string
+	protocol string
+	host     *regexp.Regexp
+	port     *regexp.Regexp
+	file     *regexp.Regexp
+}
+
+func buildBurpRules(advancedMode bool, rawRules []rawBurpRule) ([]burpRule, error) {
+	rules := make([]burpRule, 0, len(rawRules))
+
+	for _, raw := range rawRules {
+		if !raw.Enabled {
+			continue
+		}
+
+		if !advancedMode {
+			rules = append(rules, burpRule{prefix: raw.Prefix})
+
+			continue
+		}
+
+		rule := burpRule{protocol: strings.ToLower(raw.Protocol)}
+
+		var err error
+
+		if rule.host, err = compileOptionalRegexp(raw.Host); err != nil {
+			return nil, errors.Wrapf(err, "invalid host `%s`", raw.Host)
+		}
+
+		if rule.port, err = compileOptionalRegexp(raw.Port); err != nil {
+			return nil, errors.Wrapf(err, "invalid port `%s`", raw.Port)
+		}
+
+		if rule.file, err = compileOptionalRegexp(raw.File); err != nil {
+			return nil, errors.Wrapf(err, "invalid file `%s`", raw.File)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r burpRule) matches(u *url.URL) bool {
+	if r.host == nil && r.port == nil && r.file == nil {
+		return len(r.prefix) > 0 && strings.HasPrefix(u.String(), r.prefix)
+	}
+
+	if r.protocol != "" && r.protocol != protocolAny && r.protocol != strings.ToLower(u.Scheme) {
+		return false
+	}
+
+	if r.host != nil && !r.host.MatchString(u.Hostname()) {
+		return false
+	}
+
+	if r.port != nil && !r.port.MatchString(portOf(u)) {
+		return false
+	}
+
+	if r.file != nil && !r.file.MatchString(u.RequestURI()) {
+		return false
+	}
+
+	return true
+}
+
+func compileOptionalRegexp(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return regexp.MustCompile(""), nil
+	}
+
+	return regexp.Compile(expr)
+}
+
+func portOf(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+
+	if strings.ToLower(u.Scheme) == "https" {
+		return "443"
+	}
+
+	return "80"
+}
@@ -0,0 +1,76 @@
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBurpScopeInAdvancedMode(t *testing.T) {
+	t.Parallel()
+
+	sut, err := scope.NewBurpScopeFromFile("testdata/burp_advanced.json")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		url      string
+		expected bool
+	}{
+		{url: "https://example.com/", expected: true},
+		{url: "https://example.com/admin/index.php?a=b", expected: true},
+		{url: "https://example.com:443/home", expected: true},
+		{url: "https://example.com/logout", expected: false},
+		{url: "http://example.com/home", expected: false},
+		{url: "https://example.com:8443/home", expected: false},
+		{url: "https://sub.example.com/home", expected: false},
+		{url: "http://api.example.com:8080/v1/users", expected: true},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, sut.InScope(test.MustParseURL(t, tc.url)), tc.url)
+	}
+}
+
+func TestBurpScopeInSimpleMode(t *testing.T) {
+	t.Parallel()
+
+	sut, err := scope.NewBurpScopeFromFile("testdata/burp_simple.json")
+	assert.NoError(t, err)
+
+	assert.True(t, sut.InScope(test.MustParseURL(t, "http://example.com/")))
+	assert.True(t, sut.InScope(test.MustParseURL(t, "http://example.com/home/index.php")))
+	assert.False(t, sut.InScope(test.MustParseURL(t, "http://example.com/static/app.js")))
+	assert.False(t, sut.InScope(test.MustParseURL(t, "https://example.com/")))
+	assert.False(t, sut.InScope(test.MustParseURL(t, "http://example.org/")))
+}
+
+func TestBurpScopeShouldFailWithoutIncludeRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := scope.NewBurpScope([]byte(`{"target":{"scope":{"include":[{"enabled":false,"prefix":"http://a/"}]}}}`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not contain any enabled include rule")
+}
+
+func TestBurpScopeShouldFailWithInvalidRegex(t *testing.T) {
+	t.Parallel()
+
+	_, err := scope.NewBurpScope(
+		[]byte(`{"target":{"scope":{"advanced_mode":true,"include":[{"enabled":true,"host":"(("}]}}}`),
+	)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid include rule")
+}
+
+func TestBurpScopeShouldFailWithInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := scope.NewBurpScopeFromFile("testdata/not_existing.json")
+	assert.Error(t, err)
+
+	_, err = scope.NewBurpScope([]byte(`{`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode burp scope")
+}
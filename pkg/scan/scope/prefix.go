@@ -0,0 +1,54 @@
+package scope
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NewPrefixScope builds a PrefixScope out of a domain (eg: example.com, its subdomains included), a URL prefix
+// (eg: https://example.com/app/) or a path prefix (eg: /app/).
+func NewPrefixScope(raw string) (*PrefixScope, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch {
+	case raw == "":
+		return nil, errors.New("the scope is empty")
+	case strings.HasPrefix(raw, "/"):
+		return &PrefixScope{path: raw}, nil
+	case strings.Contains(raw, "://"):
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, errors.Errorf("invalid scope `%s`, it is not a valid URL", raw)
+		}
+
+		return &PrefixScope{scheme: strings.ToLower(u.Scheme), host: strings.ToLower(u.Host), path: u.Path}, nil
+	case strings.ContainsAny(raw, "/?#@ "):
+		return nil, errors.Errorf("invalid scope `%s`, it must be a domain, a URL or a path", raw)
+	default:
+		return &PrefixScope{domain: strings.Trim(strings.ToLower(raw), ".")}, nil
+	}
+}
+
+// PrefixScope accepts the URLs of a domain and its subdomains, or the URLs starting with a prefix.
+type PrefixScope struct {
+	domain string
+	scheme string
+	host   string
+	path   string
+}
+
+func (s *PrefixScope) InScope(u *url.URL) bool {
+	if s.domain != "" {
+		host := strings.ToLower(u.Hostname())
+
+		return host == s.domain || strings.HasSuffix(host, "."+s.domain)
+	}
+
+	if s.host != "" && (s.scheme != strings.ToLower(u.Scheme) || s.host != strings.ToLower(u.Host)) {
+		return false
+	}
+
+	return strings.HasPrefix(u.EscapedPath(), s.path) || strings.HasPrefix(u.Path, s.path)
+}
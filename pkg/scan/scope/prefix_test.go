@@ -0,0 +1,46 @@
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/stefanoj3/dirstalk/pkg/common/test"
+	"github.com/stefanoj3/dirstalk/pkg/scan/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixScope(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scope    string
+		url      string
+		expected bool
+	}{
+		{scope: "example.com", url: "https://example.com/admin", expected: true},
+		{scope: "example.com", url: "http://api.Example.com:8080/v1", expected: true},
+		{scope: "example.com", url: "https://notexample.com/", expected: false},
+		{scope: "example.com", url: "https://example.com.evil.com/", expected: false},
+		{scope: "https://example.com/app/", url: "https://example.com/app/admin", expected: true},
+		{scope: "https://example.com/app/", url: "https://example.com/", expected: false},
+		{scope: "https://example.com/app/", url: "http://example.com/app/admin", expected: false},
+		{scope: "https://example.com/app/", url: "https://sub.example.com/app/admin", expected: false},
+		{scope: "/app/", url: "https://example.com/app/admin", expected: true},
+		{scope: "/app/", url: "https://example.com/static/app/", expected: false},
+	}
+
+	for _, tc := range testCases {
+		sut, err := scope.NewPrefixScope(tc.scope)
+		assert.NoError(t, err)
+
+		assert.Equal(t, tc.expected, sut.InScope(test.MustParseURL(t, tc.url)), tc.scope+" "+tc.url)
+	}
+}
+
+func TestPrefixScopeShouldFailForInvalidScopes(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{"", "example.com/app", "https://", "user@example.com"} {
+		_, err := scope.NewPrefixScope(raw)
+		assert.Error(t, err, raw)
+	}
+}
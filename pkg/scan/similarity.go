@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"bytes"
+	"hash/fnv"
+	"math"
+)
+
+// similarityHashSize is the amount of minimum hashes kept by a similarityHash, the bigger it is the more
+// accurate the similarity estimated.
+const similarityHashSize = 64
+
+// similarityHash is a MinHash of the words of a body: the share of minimum hashes two bodies have in common
+// estimates how many of their words they share, so that the bodies differing only in a few tokens, eg: a
+// timestamp or a CSRF token, are still recognized as similar.
+type similarityHash [similarityHashSize]uint64
+
+func newSimilarityHash(body []byte) *similarityHash {
+	h := &similarityHash{}
+	for i := range h {
+		h[i] = math.MaxUint64
+	}
+
+	seen := make(map[string]struct{})
+
+	for _, word := range bytes.Fields(body) {
+		if _, found := seen[string(word)]; found {
+			continue
+		}
+
+		seen[string(word)] = struct{}{}
+
+		hasher := fnv.New64a()
+		_, _ = hasher.Write(word)
+		wordHash := hasher.Sum64()
+
+		for i := range h {
+			if v := mixHash(wordHash + uint64(i)*0x9e3779b97f4a7c15); v < h[i] {
+				h[i] = v
+			}
+		}
+	}
+
+	return h
+}
+
+// similarity returns the estimated percentage of words the bodies have in common.
+func (h *similarityHash) similarity(other *similarityHash) int {
+	common := 0
+
+	for i := range h {
+		if h[i] == other[i] {
+			common++
+		}
+	}
+
+	return common * 100 / similarityHashSize
+}
+
+// mixHash is the finalizer of splitmix64, deriving independent hashes out of the hash of a word.
+func mixHash(v uint64) uint64 {
+	v = (v ^ (v >> 30)) * 0xbf58476d1ce4e5b9
+	v = (v ^ (v >> 27)) * 0x94d049bb133111eb
+
+	return v ^ (v >> 31)
+}
@@ -0,0 +1,22 @@
+package scan
+
+import "sync/atomic"
+
+// Stop stops the scan from dispatching new targets, eg: to shutdown gracefully; the requests in flight are
+// completed and their results delivered, unless the context given to Scan is done meanwhile. The results
+// channel is closed once they are.
+func (s *Scanner) Stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+
+	s.abortMx.Lock()
+	defer s.abortMx.Unlock()
+
+	if s.stopDispatch != nil {
+		s.stopDispatch()
+	}
+}
+
+// Stopped tells whether Stop was called.
+func (s *Scanner) Stopped() bool {
+	return atomic.LoadInt32(&s.stopped) == 1
+}
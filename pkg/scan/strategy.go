@@ -0,0 +1,126 @@
+package scan
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// StrategyDepthFirst recurses into the directories as soon as they are found.
+	StrategyDepthFirst = "depth-first"
+	// StrategyBreadthFirst recurses into the directories level by level, see WithBreadthFirst.
+	StrategyBreadthFirst = "breadth-first"
+	// StrategyPriority recurses into the most promising directories first, see WithPriorityScheduling.
+	StrategyPriority = "priority"
+)
+
+// Strategies returns the orders in which the directories found can be recursed into.
+func Strategies() []string {
+	return []string{StrategyDepthFirst, StrategyBreadthFirst, StrategyPriority}
+}
+
+// WithBreadthFirst scans the directories found level by level: the directories found while scanning a level
+// are recursed into once the whole level is scanned, to cover all the top-level directories early. By default
+// the directories are recursed into as soon as they are found, which goes deep into the application trees.
+func WithBreadthFirst() ScannerOption {
+	return func(s *Scanner) {
+		s.queue = &branchQueue{}
+	}
+}
+
+// branchQueue holds the branches to recurse into once the level being scanned is complete.
+type branchQueue struct {
+	mx      sync.Mutex
+	pending []*branch
+	// prioritized serves the most promising directory at a time instead of a whole level
+	prioritized bool
+}
+
+func (q *branchQueue) push(b *branch) {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.pending = append(q.pending, b)
+}
+
+// next returns the branches queued so far, emptying the queue; only the one with the highest directoryScore,
+// the first queued among the ties, when the queue is prioritized.
+func (q *branchQueue) next() []*branch {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	if q.prioritized && len(q.pending) > 0 {
+		best := 0
+
+		for i := range q.pending {
+			if directoryScore(q.pending[i].result.Target.Path) > directoryScore(q.pending[best].result.Target.Path) {
+				best = i
+			}
+		}
+
+		b := q.pending[best]
+		q.pending = append(q.pending[:best], q.pending[best+1:]...)
+
+		return []*branch{b}
+	}
+
+	pending := q.pending
+	q.pending = nil
+
+	return pending
+}
+
+// scanQueuedBranches recurses into the queued results until the scan is canceled: level by level, waiting for
+// every level to be complete, or one directory at a time when the queue is prioritized, waiting only once the
+// queue is empty for the directories being scanned to queue new ones.
+func (s *Scanner) scanQueuedBranches(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	dispatch func(targets <-chan Target, skip uint64),
+	reproducer func(r Result) <-chan Target,
+) {
+	// scanning are the branches dispatched whose targets may still be being scanned
+	scanning := make([]*branch, 0)
+
+	for ctx.Err() == nil {
+		branches := s.queue.next()
+		if len(branches) == 0 {
+			wg.Wait()
+
+			s.untrackBranches(ctx, scanning...)
+			scanning = scanning[:0]
+
+			if branches = s.queue.next(); len(branches) == 0 {
+				return
+			}
+		}
+
+		if !s.queue.prioritized {
+			s.logger.WithField("directories", len(branches)).Debug("Scanning the next level")
+		}
+
+		for _, b := range branches {
+			s.branches.enter(b.result.Target.Path)
+		}
+
+		for _, b := range branches {
+			if !s.branches.isSkipped(b.result.Target.Path) {
+				dispatch(reproducer(b.result), atomic.LoadUint64(&b.reproduced))
+			}
+		}
+
+		scanning = append(scanning, branches...)
+
+		if !s.queue.prioritized {
+			wg.Wait()
+
+			s.untrackBranches(ctx, scanning...)
+			scanning = scanning[:0]
+		}
+
+		for _, b := range branches {
+			s.branches.leave(b.result.Target.Path)
+		}
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stefanoj3/dirstalk/pkg/scan"
+	"github.com/stefanoj3/dirstalk/pkg/scan/fingerprint"
 )
 
 const (
-	breakingText = "Found something breaking"
-	foundText    = "Found"
+	breakingText   = "Found something breaking"
+	foundText      = "Found"
+	outOfScopeText = "Found out of scope"
 )
 
 func NewResultSummarizer(treePrinter ResultTree, logger *logrus.Logger) *ResultSummarizer {
@@ -24,11 +27,13 @@ func NewResultSummarizer(treePrinter ResultTree, logger *logrus.Logger) *ResultS
 }
 
 type ResultSummarizer struct {
-	treePrinter ResultTree
-	logger      *logrus.Logger
-	results     []scan.Result
-	resultMap   map[string]struct{}
-	mux         sync.RWMutex
+	treePrinter  ResultTree
+	logger       *logrus.Logger
+	results      []scan.Result
+	outOfScope   []scan.Result
+	technologies []fingerprint.Technology
+	resultMap    map[string]struct{}
+	mux          sync.RWMutex
 }
 
 func (s *ResultSummarizer) Add(result scan.Result) {
@@ -45,9 +50,24 @@ func (s *ResultSummarizer) Add(result scan.Result) {
 
 	s.resultMap[key] = struct{}{}
 
+	// the paths out of scope were not requested, so they are listed apart from the results
+	if isOutOfScope(result) {
+		s.outOfScope = append(s.outOfScope, result)
+
+		return
+	}
+
 	s.results = append(s.results, result)
 }
 
+// SetTechnologies sets the technologies recognized during the scan, listed at the end of the summary.
+func (s *ResultSummarizer) SetTechnologies(technologies []fingerprint.Technology) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.technologies = technologies
+}
+
 func (s *ResultSummarizer) Summarize() {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -70,6 +90,40 @@ func (s *ResultSummarizer) Summarize() {
 			),
 		)
 	}
+
+	s.printOutOfScope()
+	s.printTechnologies()
+}
+
+func (s *ResultSummarizer) printTechnologies() {
+	if len(s.technologies) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(s.logger.Out, fmt.Sprintf("\n%d technologies recognized", len(s.technologies)))
+
+	for _, t := range s.technologies {
+		_, _ = fmt.Fprintln(s.logger.Out, fmt.Sprintf("%s (%s)", t.String(), t.Evidence))
+	}
+}
+
+func (s *ResultSummarizer) printOutOfScope() {
+	if len(s.outOfScope) == 0 {
+		return
+	}
+
+	sort.Slice(s.outOfScope, func(i, j int) bool {
+		return s.outOfScope[i].URL.String() < s.outOfScope[j].URL.String()
+	})
+
+	_, _ = fmt.Fprintln(
+		s.logger.Out,
+		fmt.Sprintf("\n%d paths found out of scope, not requested", len(s.outOfScope)),
+	)
+
+	for _, r := range s.outOfScope {
+		_, _ = fmt.Fprintln(s.logger.Out, fmt.Sprintf("%s [%s]", r.URL.String(), r.Target.Method))
+	}
 }
 
 func (s *ResultSummarizer) printSummary() {
@@ -84,6 +138,15 @@ func (s *ResultSummarizer) printTree() {
 }
 
 func (s *ResultSummarizer) log(result scan.Result) {
+	if isOutOfScope(result) {
+		s.logger.WithFields(logrus.Fields{
+			"method": result.Target.Method,
+			"url":    result.URL.String(),
+		}).Info(outOfScopeText)
+
+		return
+	}
+
 	statusCode := result.StatusCode
 
 	l := s.logger.WithFields(logrus.Fields{
@@ -92,6 +155,18 @@ func (s *ResultSummarizer) log(result scan.Result) {
 		"url":         result.URL.String(),
 	})
 
+	if result.Host != "" {
+		l = l.WithField("host", result.Host)
+	}
+
+	if result.Words > 0 || result.Lines > 0 {
+		l = l.WithFields(logrus.Fields{"words": result.Words, "lines": result.Lines})
+	}
+
+	for name, value := range result.Headers {
+		l = l.WithField(strings.ToLower(name), value)
+	}
+
 	if statusCode >= http.StatusInternalServerError {
 		l.Warn(breakingText)
 	} else {
@@ -99,6 +174,16 @@ func (s *ResultSummarizer) log(result scan.Result) {
 	}
 }
 
+func isOutOfScope(result scan.Result) bool {
+	for _, label := range result.Labels {
+		if label == scan.LabelOutOfScope {
+			return true
+		}
+	}
+
+	return false
+}
+
 func keyForResult(result scan.Result) string {
 	return fmt.Sprintf("%s~%s", result.URL.String(), result.Target.Method)
 }
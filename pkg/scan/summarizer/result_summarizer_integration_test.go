@@ -197,6 +197,50 @@ http://mysite/path/to/my/files [200] [GET]
 	assert.Equal(t, expectedResult, loggerBuffer.String())
 }
 
+func TestResultSummarizerShouldListTheResultsOutOfScopeSeparately(t *testing.T) {
+	logger, loggerBuffer := test.NewLogger()
+	logger.SetLevel(logrus.FatalLevel)
+
+	sut := summarizer.NewResultSummarizer(tree.NewResultTreeProducer(), logger)
+
+	sut.Add(
+		scan.NewResult(
+			scan.Target{
+				Method: http.MethodGet,
+				Path:   "/app/old",
+			},
+			&http.Response{
+				StatusCode: http.StatusFound,
+				Request: &http.Request{
+					URL: test.MustParseURL(t, "http://mysite/app/old"),
+				},
+			},
+		),
+	)
+
+	sut.Add(
+		scan.Result{
+			Target: scan.Target{Method: http.MethodGet, Path: "/admin/"},
+			URL:    *test.MustParseURL(t, "http://mysite/admin/"),
+			Labels: []string{scan.LabelOutOfScope},
+		},
+	)
+
+	sut.Summarize()
+
+	expectedResult := `1 results found
+/
+└── app
+    └── old
+
+http://mysite/app/old [302] [GET]
+
+1 paths found out of scope, not requested
+http://mysite/admin/ [GET]
+`
+	assert.Equal(t, expectedResult, loggerBuffer.String())
+}
+
 func TestResultSummarizerShouldLogResults(t *testing.T) {
 	testCases := []struct {
 		result            scan.Result
@@ -242,6 +286,27 @@ func TestResultSummarizerShouldLogResults(t *testing.T) {
 				`url="http://mysite/index"`,
 			},
 		},
+		{
+			result: scan.NewResult(
+				scan.Target{
+					Method: http.MethodGet,
+					Path:   "/admin",
+				},
+				&http.Response{
+					StatusCode: http.StatusMovedPermanently,
+					Header:     http.Header{"Server": {"nginx"}, "Location": {"/admin/"}},
+					Request: &http.Request{
+						URL: test.MustParseURL(t, "http://mysite/admin"),
+					},
+				},
+			),
+			expectedToContain: []string{
+				"Found",
+				"location=/admin/",
+				"server=nginx",
+				"status-code=301",
+			},
+		},
 		{
 			result: scan.NewResult(
 				scan.Target{
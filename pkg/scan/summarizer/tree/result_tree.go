@@ -1,6 +1,8 @@
 package tree
 
 import (
+	"fmt"
+	"net/http"
 	"sort"
 	"strings"
 
@@ -8,20 +10,62 @@ import (
 	"github.com/stefanoj3/dirstalk/pkg/scan"
 )
 
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
 func NewResultTreeProducer() ResultTreeProducer {
 	return ResultTreeProducer{}
 }
 
-type ResultTreeProducer struct{}
+// NewDetailedResultTreeProducer returns a producer rendering, next to each path, the status code, method,
+// size and content type of its results. When onlyFound is true the results replying with an error status
+// are left out, when colored is true the details are colored by status class.
+func NewDetailedResultTreeProducer(onlyFound, colored bool) ResultTreeProducer {
+	return ResultTreeProducer{detailed: true, onlyFound: onlyFound, colored: colored}
+}
+
+type ResultTreeProducer struct {
+	detailed  bool
+	onlyFound bool
+	colored   bool
+}
+
+type node struct {
+	name     string
+	children []*node
+	results  []scan.Result
+}
+
+func (n *node) child(name string) *node {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+
+	c := &node{name: name}
+	n.children = append(n.children, c)
+
+	return c
+}
 
 func (s ResultTreeProducer) String(results []scan.Result) string {
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Target.Path < results[j].Target.Path
 	})
 
-	root := gotree.New("/")
+	root := &node{name: "/"}
 
 	for _, r := range results {
+		if s.onlyFound && r.StatusCode >= http.StatusBadRequest {
+			continue
+		}
+
 		currentBranch := root
 
 		parts := strings.Split(r.URL.Path, "/")
@@ -30,28 +74,84 @@ func (s ResultTreeProducer) String(results []scan.Result) string {
 				continue
 			}
 
-			found := false
-
-			for _, item := range currentBranch.Items() {
-				if item.Text() != p {
-					continue
-				}
+			currentBranch = currentBranch.child(p)
+		}
 
-				currentBranch = item
-				found = true
+		currentBranch.results = append(currentBranch.results, r)
+	}
 
-				break
-			}
+	return s.toTree(root).Print()
+}
 
-			if found {
-				continue
-			}
+func (s ResultTreeProducer) toTree(n *node) gotree.Tree {
+	text := n.name
 
-			newTree := gotree.New(p)
-			currentBranch.AddTree(newTree)
-			currentBranch = newTree
+	if s.detailed {
+		for _, r := range n.results {
+			text += " " + s.details(r)
 		}
 	}
 
-	return root.Print()
+	t := gotree.New(text)
+
+	for _, c := range n.children {
+		t.AddTree(s.toTree(c))
+	}
+
+	return t
+}
+
+func (s ResultTreeProducer) details(r scan.Result) string {
+	details := fmt.Sprintf("[%d] [%s]", r.StatusCode, r.Target.Method)
+
+	if r.ContentLength >= 0 {
+		details += fmt.Sprintf(" [%s]", humanReadableSize(r.ContentLength))
+	}
+
+	if r.Words > 0 || r.Lines > 0 {
+		details += fmt.Sprintf(" [%d words, %d lines]", r.Words, r.Lines)
+	}
+
+	if r.ContentType != "" {
+		details += fmt.Sprintf(" [%s]", r.ContentType)
+	}
+
+	if len(r.Labels) > 0 {
+		details += fmt.Sprintf(" [%s]", strings.Join(r.Labels, ", "))
+	}
+
+	if !s.colored {
+		return details
+	}
+
+	return colorForStatusCode(r.StatusCode) + details + colorReset
+}
+
+func colorForStatusCode(statusCode int) string {
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		return colorRed
+	case statusCode >= http.StatusBadRequest:
+		return colorYellow
+	case statusCode >= http.StatusMultipleChoices:
+		return colorCyan
+	default:
+		return colorGreen
+	}
+}
+
+func humanReadableSize(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
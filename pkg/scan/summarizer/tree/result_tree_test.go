@@ -423,3 +423,52 @@ func BenchmarkResultTree(b *testing.B) {
 
 	testResult += "1"
 }
+
+func TestDetailedResultTreePrinter(t *testing.T) {
+	newResult := func(method, path string, statusCode int, contentLength int64, contentType string) scan.Result {
+		return scan.NewResult(
+			scan.Target{Method: method, Path: path},
+			&http.Response{
+				StatusCode:    statusCode,
+				ContentLength: contentLength,
+				Header:        http.Header{"Content-Type": []string{contentType}},
+				Request: &http.Request{
+					URL: test.MustParseURL(t, "http://mysite"+path),
+				},
+			},
+		)
+	}
+
+	results := []scan.Result{
+		newResult(http.MethodGet, "/home", http.StatusOK, 2048, "text/html"),
+		newResult(http.MethodPost, "/home", http.StatusMethodNotAllowed, 12, ""),
+		newResult(http.MethodGet, "/home/old", http.StatusMovedPermanently, -1, ""),
+		newResult(http.MethodGet, "/api/crash", http.StatusInternalServerError, 1572864, "application/json"),
+	}
+
+	expected := `/
+├── api
+│   └── crash ` + "\x1b[31m[500] [GET] [1.5MB] [application/json]\x1b[0m" + `
+└── home ` + "\x1b[32m[200] [GET] [2.0KB] [text/html]\x1b[0m \x1b[33m[405] [POST] [12B]\x1b[0m" + `
+    └── old ` + "\x1b[36m[301] [GET]\x1b[0m" + `
+`
+	assert.Equal(t, expected, tree.NewDetailedResultTreeProducer(false, true).String(results))
+
+	expectedOnlyFound := `/
+└── home [200] [GET] [2.0KB] [text/html]
+    └── old [301] [GET]
+`
+	assert.Equal(t, expectedOnlyFound, tree.NewDetailedResultTreeProducer(true, false).String(results))
+
+	labeled := newResult(http.MethodGet, "/login", http.StatusOK, 10, "text/html")
+	labeled.Labels = []string{"login-page", "interesting"}
+
+	expectedLabeled := `/
+└── login [200] [GET] [10B] [text/html] [login-page, interesting]
+`
+	assert.Equal(
+		t,
+		expectedLabeled,
+		tree.NewDetailedResultTreeProducer(false, false).String([]scan.Result{labeled}),
+	)
+}
@@ -0,0 +1,132 @@
+package scan
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/scan/fingerprint"
+)
+
+// WithFingerprinting recognizes the server and framework technologies of the target, by its root and its
+// favicon, and of every result, by their headers, cookies and body; see Technologies.
+func WithFingerprinting() ScannerOption {
+	return func(s *Scanner) {
+		s.fingerprinting = true
+	}
+}
+
+// technologyRegistry keeps the technologies recognized during the scan, with their version when any
+// response reveals it.
+type technologyRegistry struct {
+	mx           sync.Mutex
+	technologies map[string]fingerprint.Technology
+}
+
+// record stores the technology, returning true when it was unknown or its version was.
+func (r *technologyRegistry) record(technology fingerprint.Technology) bool {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.technologies == nil {
+		r.technologies = make(map[string]fingerprint.Technology)
+	}
+
+	known, found := r.technologies[technology.Name]
+	if found && (known.Version != "" || technology.Version == "") {
+		return false
+	}
+
+	r.technologies[technology.Name] = technology
+
+	return true
+}
+
+func (r *technologyRegistry) all() []fingerprint.Technology {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if len(r.technologies) == 0 {
+		return nil
+	}
+
+	technologies := make([]fingerprint.Technology, 0, len(r.technologies))
+	for _, t := range r.technologies {
+		technologies = append(technologies, t)
+	}
+
+	sort.Slice(technologies, func(i, j int) bool {
+		return technologies[i].Name < technologies[j].Name
+	})
+
+	return technologies
+}
+
+// Technologies returns the technologies recognized during the scan, nil when fingerprinting is not enabled or
+// nothing was recognized.
+func (s *Scanner) Technologies() []fingerprint.Technology {
+	return s.technologies.all()
+}
+
+func (s *Scanner) recordTechnologies(l *logrus.Entry, technologies []fingerprint.Technology) {
+	for _, t := range technologies {
+		if s.technologies.record(t) {
+			l.WithFields(logrus.Fields{
+				"technology": t.String(),
+				"evidence":   t.Evidence,
+			}).Info("Technology recognized")
+		}
+	}
+}
+
+// fingerprintTarget recognizes the technologies of the root of the target and of its favicon.
+func (s *Scanner) fingerprintTarget(ctx context.Context, baseURL url.URL) {
+	l := s.logger.WithField("url", baseURL.String())
+
+	if res, body, ok := s.fingerprintRequest(ctx, l, baseURL); ok {
+		s.recordTechnologies(l, fingerprint.Fingerprint(res.Header, body))
+	}
+
+	faviconURL := url.URL{Scheme: baseURL.Scheme, Host: baseURL.Host, Path: "/favicon.ico"}
+
+	res, body, ok := s.fingerprintRequest(ctx, l, faviconURL)
+	if !ok || res.StatusCode != http.StatusOK || len(body) == 0 {
+		return
+	}
+
+	l.WithField("favicon-hash", fingerprint.FaviconHash(body)).Debug("Favicon hash")
+
+	if technology, found := fingerprint.FingerprintFavicon(body); found {
+		s.recordTechnologies(l, []fingerprint.Technology{technology})
+	}
+}
+
+func (s *Scanner) fingerprintRequest(ctx context.Context, l *logrus.Entry, u url.URL) (*http.Response, []byte, bool) {
+	req, err := s.newRequest(ctx, u, Target{Method: http.MethodGet})
+	if err != nil {
+		l.WithError(err).Warn("failed to build the fingerprinting request")
+
+		return nil, nil, false
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		l.WithError(err).WithField("url", u.String()).Debug("fingerprinting request failed")
+
+		return nil, nil, false
+	}
+
+	atomic.AddUint64(&s.requests, 1)
+
+	body := s.readBody(l, res)
+
+	if err := res.Body.Close(); err != nil {
+		l.WithError(err).Warn("failed to close response body")
+	}
+
+	return res, body, true
+}
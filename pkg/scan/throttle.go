@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// adaptiveMinInterval is the interval between the requests used when the target starts throttling
+	// a scan without rate limit.
+	adaptiveMinInterval = 50 * time.Millisecond
+	adaptiveMaxInterval = 10 * time.Second
+	// adaptiveMaxPause caps the Retry-After of the target, so that a misbehaving one cannot stall the scan.
+	adaptiveMaxPause = 5 * time.Minute
+	// adaptiveSpeedUpAfter is the amount of consecutive responses that were not throttled required
+	// to halve the interval between the requests.
+	adaptiveSpeedUpAfter = 20
+	// adaptiveMaxAttempts is the amount of times a throttled request is performed before giving up on it.
+	adaptiveMaxAttempts = 5
+)
+
+// WithAdaptiveRate slows down the scan when the target throttles it, replying with 429 or with 503
+// and a Retry-After header: the requests are paused for the time asked by the target, the interval
+// between them is doubled and the throttled request is performed again.
+// The scan speeds back up, down to the rate limit if any, once the target stops throttling it.
+func WithAdaptiveRate() ScannerOption {
+	return func(s *Scanner) {
+		s.adaptiveRate = true
+	}
+}
+
+// isThrottled tells if the target replied asking to slow down, together with how long to wait for.
+func isThrottled(res *http.Response) (bool, time.Duration) {
+	retryAfter, hasRetryAfter := parseRetryAfter(res.Header.Get("Retry-After"))
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true, retryAfter
+	case res.StatusCode == http.StatusServiceUnavailable && hasRetryAfter:
+		return true, retryAfter
+	default:
+		return false, 0
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	var retryAfter time.Duration
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = time.Until(date)
+	} else {
+		return 0, false
+	}
+
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+
+	if retryAfter > adaptiveMaxPause {
+		retryAfter = adaptiveMaxPause
+	}
+
+	return retryAfter, true
+}
+
+// slowDown pauses the requests for the given time and doubles the interval between them.
+func (r *rateLimiter) slowDown(pause time.Duration) time.Duration {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.successes = 0
+
+	r.interval *= 2
+	if r.interval < adaptiveMinInterval {
+		r.interval = adaptiveMinInterval
+	}
+
+	if r.interval > adaptiveMaxInterval {
+		r.interval = adaptiveMaxInterval
+	}
+
+	if until := time.Now().Add(pause); r.next.Before(until) {
+		r.next = until
+	}
+
+	return r.interval
+}
+
+// speedUp halves the interval between the requests after enough of them were not throttled,
+// it never goes below the configured rate limit.
+func (r *rateLimiter) speedUp() (interval time.Duration, changed bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if r.interval <= r.baseline {
+		return r.interval, false
+	}
+
+	r.successes++
+	if r.successes < adaptiveSpeedUpAfter {
+		return r.interval, false
+	}
+
+	r.successes = 0
+
+	r.interval /= 2
+	if r.interval < r.baseline || r.interval < adaptiveMinInterval {
+		r.interval = r.baseline
+	}
+
+	return r.interval, true
+}
+
+// adaptRate slows down or speeds up the scan depending on the response, it returns true when
+// the request was throttled and has to be performed again.
+func (s *Scanner) adaptRate(res *http.Response) bool {
+	throttled, pause := isThrottled(res)
+
+	l := s.logger.WithFields(logrus.Fields{
+		"url":         res.Request.URL.String(),
+		"status-code": res.StatusCode,
+	})
+
+	if !throttled {
+		if interval, changed := s.rateLimiter.speedUp(); changed {
+			l.WithField("interval", interval).Debug("target stopped throttling, speeding up")
+		}
+
+		return false
+	}
+
+	interval := s.rateLimiter.slowDown(pause)
+
+	l.WithFields(logrus.Fields{"pause": pause, "interval": interval}).Debug("target is throttling, slowing down")
+
+	return true
+}
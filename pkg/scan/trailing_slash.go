@@ -0,0 +1,49 @@
+package scan
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stefanoj3/dirstalk/pkg/common/urlpath"
+)
+
+// WithTrailingSlash requests every dictionary entry also with a trailing slash, as the existence of many
+// directories is revealed only by the variant with the slash, eg: /admin answering 404 and /admin/ 403.
+// The variant is not requested when the entry redirected and the redirect was followed.
+func WithTrailingSlash() ScannerOption {
+	return func(s *Scanner) {
+		s.trailingSlash = true
+	}
+}
+
+// needsTrailingSlash tells whether the target may point to a directory and has no trailing slash yet.
+func needsTrailingSlash(target Target) bool {
+	return target.Path != "" && !strings.HasSuffix(target.Path, "/") && !urlpath.HasExtension(target.Path)
+}
+
+// processTrailingSlash processes the target with a trailing slash, logging when the response differs from the
+// one of the target without it.
+func (s *Scanner) processTrailingSlash(
+	ctx context.Context,
+	l *logrus.Entry,
+	baseURL url.URL,
+	target Target,
+	statusCode int,
+	reproducer func(r Result) <-chan Target,
+	results chan<- Result,
+) {
+	slashTarget := target
+	slashTarget.Path += "/"
+
+	slashStatusCode := s.processTarget(ctx, baseURL, slashTarget, reproducer, results)
+	if slashStatusCode == 0 || slashStatusCode == statusCode {
+		return
+	}
+
+	l.WithFields(logrus.Fields{
+		"status-code":       statusCode,
+		"slash-status-code": slashStatusCode,
+	}).Info("The trailing slash changes the response")
+}
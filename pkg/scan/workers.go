@@ -0,0 +1,56 @@
+package scan
+
+import "sync"
+
+// workerLimiter limits the number of targets processed concurrently, the limit can be changed at any time.
+type workerLimiter struct {
+	mx     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+func newWorkerLimiter(limit int) *workerLimiter {
+	w := &workerLimiter{limit: limit}
+	w.cond = sync.NewCond(&w.mx)
+
+	return w
+}
+
+func (w *workerLimiter) acquire() {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	for w.active >= w.limit {
+		w.cond.Wait()
+	}
+
+	w.active++
+}
+
+func (w *workerLimiter) release() {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	w.active--
+	w.cond.Broadcast()
+}
+
+func (w *workerLimiter) setLimit(limit int) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	w.limit = limit
+	w.cond.Broadcast()
+}
+
+func (w *workerLimiter) state() (limit int, active int) {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	return w.limit, w.active
+}